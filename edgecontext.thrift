@@ -53,6 +53,29 @@ struct Session {
 
     */
     1: string id;
+    /** The time when the session was created, in number of milliseconds since
+    epoch.
+
+    */
+    2: optional i64 created_ms;
+    /** The type of the session, one of "cookie", "mobile" or "api".
+
+    */
+    3: optional string type;
+    /** If the session recently completed step-up authentication, the time
+    until when it's considered elevated, in number of milliseconds since epoch.
+
+    */
+    4: optional i64 elevated_until_ms;
+    /** Whether the session was authenticated with multi-factor authentication.
+
+    */
+    5: optional bool mfa;
+    /** How the user authenticated, one of "password", "google", "apple",
+    "magic_link" or "refresh".
+
+    */
+    6: optional string auth_method;
 }
 
 /** The components of the device making a request to our services that we want to
@@ -68,6 +91,29 @@ struct Device {
 
     */
     1: string id;
+    /** The model of the device, e.g. "iPhone14,2" or "Pixel 7".
+
+    */
+    2: optional string model;
+    /** The name of the operating system of the device, e.g. "iOS" or "Android".
+
+    */
+    3: optional string os_name;
+    /** The version of the operating system of the device, e.g. "16.4.1".
+
+    */
+    4: optional string os_version;
+    /** The coarse form factor of the device, one of "phone", "tablet",
+    "desktop", "tv" or "bot".
+
+    */
+    5: optional string form_factor;
+    /** The verdict of the mobile device attestation (Play Integrity or App
+    Attest) evaluated at the edge, one of "strong", "device", "basic",
+    "failed" or "unavailable".
+
+    */
+    6: optional string attestation_verdict;
 }
 
 /** Metadata about the origin service for a request.
@@ -84,6 +130,19 @@ struct OriginService {
 
     */
     1: string name
+    /** The version of the origin service.
+
+    */
+    2: optional string version
+    /** The id of the deployment of the origin service.
+
+    */
+    3: optional string deploy_id
+    /** The edge POP or region that terminated the client connection, e.g.
+    "sjc1".
+
+    */
+    4: optional string edge_pop
 }
 
 /** Geolocation data from a request to our services that we want to
@@ -98,6 +157,22 @@ struct Geolocation {
     /** The country code of the requesting client based on geographic location.
     */
     1: CountryCode country_code
+    /** The ISO 3166-2 subdivision code (e.g. state or province) of the
+    requesting client based on geographic location, e.g. "US-CA".
+    */
+    2: optional string subdivision_code
+    /** The name of the city of the requesting client based on geographic
+    location, e.g. "San Francisco".
+    */
+    3: optional string city
+    /** The Nielsen DMA (Designated Market Area) code of the requesting client
+    based on geographic location, e.g. 807 for San Francisco-Oakland-San Jose.
+    */
+    4: optional i32 metro_code
+    /** How the country code was determined, one of "ip" (IP address lookup),
+    "account" (user account setting), or "carrier" (mobile carrier header).
+    */
+    5: optional string provenance
 }
 
 /** Unique identifier of this Edge Request
@@ -111,6 +186,10 @@ struct RequestId {
     /** The id of this Edge Request, in the most human-readable format.
     */
     1: string readable_id
+    /** The W3C Trace Context trace id of the distributed trace this Edge Request
+    belongs to, as 32 lowercase hex characters.
+    */
+    2: optional string trace_id
 }
 
 /** Locale data from a request to our services that we want to
@@ -128,6 +207,125 @@ struct Locale {
     ISO 3166-1 alpha-2 region subtag.
     */
     1: LocaleCode locale_code
+    /** IANA time zone database name of the time zone of the client,
+    e.g. "America/New_York".
+    */
+    2: optional string timezone
+    /** The full list of locales accepted by the client, in order of preference,
+    e.g. from the Accept-Language header.
+
+    */
+    3: optional list<string> accepted_locale_codes
+}
+
+/** Metadata about the client making the request to our services that we want
+to propagate between services.
+
+This model is a component of the "Edge-Request" header.  You should not need to
+interact with this model directly, but rather through the EdgeRequestContext
+interface provided by baseplate.
+
+*/
+struct Client {
+    /** The IP address of the client, as seen by the edge.
+
+    Either IPv4 dotted decimal ("192.0.2.1") or IPv6 ("2001:db8::1") form.
+    */
+    1: string ip
+    /** The User-Agent header sent by the client to the edge.
+    */
+    2: string user_agent
+    /** The semantic version of the client application, e.g. "2023.21.0".
+
+    */
+    3: string app_version
+    /** The build number of the client application.
+
+    */
+    4: i64 build_number
+    /** The platform of the client, one of "ios", "android", "web", "mweb"
+    (mobile web), or "api" (third party API clients).
+
+    */
+    5: string platform
+    /** Whether the request originates from the corporate network (VPN) or an
+    employee device.
+    */
+    6: bool internal_network
+    /** Whether the client asked to limit ad tracking, e.g. via iOS App Tracking
+    Transparency, the Android advertising ID opt-out, or the DNT and Sec-GPC
+    headers.
+    */
+    7: bool limit_ad_tracking
+    /** Whether the edge determined the client IP belongs to a VPN or an
+    anonymizing proxy.
+    */
+    8: bool anonymizing_proxy
+    /** The autonomous system number of the client IP, 0 if unknown.
+    */
+    9: i64 asn
+}
+
+/** The privacy consents given by the user making the request.
+
+This model is a component of the "Edge-Request" header.  You should not need to
+interact with this model directly, but rather through the EdgeRequestContext
+interface provided by baseplate.
+
+*/
+struct Consent {
+    /** Whether the user consented to personalized ads.
+    */
+    1: bool ads_personalization
+    /** Whether the user consented to analytics tracking.
+    */
+    2: bool analytics
+    /** Whether the user consented to sharing their data with third parties.
+    */
+    3: bool third_party_sharing
+}
+
+/** The components of the account of the user making the request that we want
+to propagate between services.
+
+This model is a component of the "Edge-Request" header.  You should not need to
+interact with this model directly, but rather through the EdgeRequestContext
+interface provided by baseplate.
+
+*/
+struct Account {
+    /** Whether the account is subject to teen-safety or parental-control
+    restrictions.
+    */
+    1: bool teen_restricted
+    /** Whether the email address of the account is verified.
+    */
+    2: bool email_verified
+    /** Whether the account has an active premium subscription at the time of
+    the request.
+    */
+    3: bool premium
+}
+
+/** The classification of the traffic a request belongs to, set by the edge.
+
+This model is a component of the "Edge-Request" header.  You should not need to
+interact with this model directly, but rather through the EdgeRequestContext
+interface provided by baseplate.
+
+*/
+struct Traffic {
+    /** Whether the request is replayed or shadowed (dark launches, traffic
+    mirroring), in which case side effects should be suppressed.
+    */
+    1: bool shadow
+    /** Whether the request is generated by a synthetic probe or a load test.
+    */
+    2: bool synthetic
+    /** Whether the request belongs to the canary (early rollout) cohort chosen
+    by the edge.
+    */
+    3: bool canary
 }
 
 /** Container model for the Edge-Request context header.
@@ -147,4 +345,45 @@ struct Request {
     6: Geolocation geolocation;
     7: optional RequestId request_id;
     8: optional Locale locale;
+    9: optional Client client;
+    /** Per-request feature flag overrides, keyed by feature flag name.
+
+    Only set by the edge for internal and debug clients.
+    */
+    10: optional map<string, string> feature_flag_overrides;
+    11: optional Consent consent;
+    /** The data protection regime that applies to the request, computed once at
+    the edge, one of "gdpr", "ccpa", "lgpd", or "none".
+    */
+    12: optional string compliance_region;
+    13: optional Account account;
+    /** Generic low-cardinality metadata set by the edge, for values that don't
+    warrant their own field.
+    */
+    14: optional map<string, string> baggage;
+    /** The SPIFFE ID of the calling workload, for requests initiated by a
+    service instead of a user, e.g. "spiffe://reddit.com/ns/jobs/sa/backfill".
+    */
+    15: optional string workload_identity;
+    /** The absolute deadline by which the response must be sent back to the
+    client, in number of milliseconds since epoch.
+    */
+    16: optional i64 deadline_ms;
+    17: optional Traffic traffic;
+    /** The names of the services that forwarded this Edge-Request header, in
+    order, with the most recent hops last.
+    */
+    18: optional list<string> hops;
+    /** The id of the tenant (brand) the request is served for, in deployments
+    that serve multiple tenants from shared infrastructure.
+    */
+    19: optional string tenant_id;
+    /** The product surface that initiated the request, one of "home_feed",
+    "push_notification", "email", "embed", or "other".
+    */
+    20: optional string referring_surface;
+    /** The auth token of the service initiating the request on behalf of the
+    user in authentication_token, if any.
+    */
+    21: optional AuthenticationToken service_authentication_token;
 }
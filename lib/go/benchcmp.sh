@@ -0,0 +1,42 @@
+#!/bin/sh
+
+# Compares the benchmarks of the current working tree against a base git ref.
+#
+# Usage: sh benchcmp.sh [base-ref]
+#
+# base-ref defaults to HEAD, so running it with uncommitted changes shows the
+# performance impact of those changes.
+# Set BENCH to limit the benchmarks to run (default "."),
+# and COUNT to change the number of runs per benchmark (default 10).
+#
+# Results are compared with benchstat (golang.org/x/perf/cmd/benchstat),
+# which will be installed if it's not already in PATH.
+
+set -e
+
+cd $(dirname $0)
+
+BASE=${1:-HEAD}
+BENCH=${BENCH:-.}
+COUNT=${COUNT:-10}
+GO=${GO:-go}
+PKG=./edgecontext/
+
+if ! command -v benchstat > /dev/null; then
+  $GO install golang.org/x/perf/cmd/benchstat@latest
+  PATH=$PATH:$($GO env GOPATH)/bin
+fi
+
+TMPDIR=$(mktemp -d)
+trap 'git worktree remove --force $TMPDIR/base > /dev/null 2>&1; rm -Rf $TMPDIR' EXIT
+
+PREFIX=$(git rev-parse --show-prefix)
+git worktree add --detach $TMPDIR/base $BASE > /dev/null 2>&1
+
+echo "Running benchmarks on $BASE..."
+(cd $TMPDIR/base/$PREFIX && $GO test -run '^$' -bench "$BENCH" -benchmem -count $COUNT $PKG > $TMPDIR/old.txt)
+
+echo "Running benchmarks on working tree..."
+$GO test -run '^$' -bench "$BENCH" -benchmem -count $COUNT $PKG > $TMPDIR/new.txt
+
+benchstat $TMPDIR/old.txt $TMPDIR/new.txt
@@ -0,0 +1,69 @@
+package edgecontext
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/reddit/baseplate.go/errorsbp"
+
+	ecthrift "github.com/reddit/edgecontext/lib/go/internal/reddit/edgecontext"
+)
+
+// FromHeaders is the batch version of FromHeader,
+// optimized for stream processors decoding a lot of headers at once.
+//
+// A single deserializer and thrift request are reused for the whole batch,
+// instead of checking them out of the pools for every header.
+//
+// The returned slice always has the same length as headers.
+// The EdgeRequestContext for an empty header, or a header failed to parse,
+// will be nil.
+// All the parsing errors are aggregated into an errorsbp.Batch,
+// each of them prefixed with the index of the header.
+func FromHeaders(ctx context.Context, headers []string, impl *Impl) ([]*EdgeRequestContext, error) {
+	ecs := make([]*EdgeRequestContext, len(headers))
+	if impl != nil && impl.lazy && !impl.strict {
+		// Lazy parsing never fails in FromHeader.
+		for i, header := range headers {
+			ecs[i], _ = FromHeader(ctx, header, impl)
+		}
+		return ecs, nil
+	}
+
+	strict := impl != nil && impl.strict
+	transport := thrift.NewTMemoryBufferLen(1024)
+	d := &thrift.TDeserializer{
+		Transport: transport,
+		Protocol:  impl.protocol().GetProtocol(transport),
+	}
+	request := requestPool.Get().(*ecthrift.Request)
+	defer func() {
+		*request = ecthrift.Request{}
+		requestPool.Put(request)
+	}()
+
+	var batch errorsbp.Batch
+	for i, header := range headers {
+		if header == "" {
+			continue
+		}
+		*request = ecthrift.Request{}
+		if err := d.ReadString(ctx, request, header); err != nil {
+			batch.AddPrefix(strconv.Itoa(i), err)
+			continue
+		}
+		if n := transport.Len(); strict && n > 0 {
+			batch.AddPrefix(strconv.Itoa(i), fmt.Errorf("%w: %d byte(s)", ErrTrailingBytes, n))
+			continue
+		}
+		ecs[i] = &EdgeRequestContext{
+			impl:   impl,
+			header: header,
+			raw:    argsFromRequest(request),
+			ctx:    ctx,
+		}
+	}
+	return ecs, batch.Compile()
+}
@@ -0,0 +1,89 @@
+package edgecontext
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// AuthorizationHeader is the HTTP header carrying the bearer token,
+// as defined in RFC 6750 section 2.1.
+//
+// The gRPC metadata key is the lowercase form of it.
+const AuthorizationHeader = "Authorization"
+
+// bearerScheme is the authentication scheme of bearer tokens,
+// which is case-insensitive.
+const bearerScheme = "Bearer"
+
+// ErrNoBearerToken is an error returned by BearerToken and
+// BearerTokenFromMetadata indicates that there's no Authorization header with
+// a non-empty bearer token.
+var ErrNoBearerToken = errors.New("edgecontext: no bearer token in authorization header")
+
+// BearerToken returns the bearer token from the Authorization header of h.
+func BearerToken(h http.Header) (string, error) {
+	return parseBearer(h.Get(AuthorizationHeader))
+}
+
+// BearerTokenFromMetadata returns the bearer token from the authorization key
+// of gRPC metadata.
+//
+// metadata.MD from google.golang.org/grpc/metadata can be passed in directly.
+func BearerTokenFromMetadata(md map[string][]string) (string, error) {
+	values := md[strings.ToLower(AuthorizationHeader)]
+	if len(values) == 0 {
+		return "", ErrNoBearerToken
+	}
+	return parseBearer(values[0])
+}
+
+func parseBearer(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if len(value) <= len(bearerScheme) ||
+		!strings.EqualFold(value[:len(bearerScheme)], bearerScheme) ||
+		value[len(bearerScheme)] != ' ' {
+		return "", ErrNoBearerToken
+	}
+	token := strings.TrimSpace(value[len(bearerScheme):])
+	if token == "" {
+		return "", ErrNoBearerToken
+	}
+	return token, nil
+}
+
+// NewArgsFromHeader validates the bearer token from the Authorization header
+// of h, and returns NewArgs pre-filled with it and its LoID claim,
+// to be completed with the other fields of the request before calling New.
+func (impl *Impl) NewArgsFromHeader(h http.Header) (NewArgs, error) {
+	token, err := BearerToken(h)
+	if err != nil {
+		return NewArgs{}, err
+	}
+	return impl.newArgsFromBearerToken(token)
+}
+
+// NewArgsFromMetadata is like NewArgsFromHeader,
+// but reads the bearer token from gRPC metadata.
+func (impl *Impl) NewArgsFromMetadata(md map[string][]string) (NewArgs, error) {
+	token, err := BearerTokenFromMetadata(md)
+	if err != nil {
+		return NewArgs{}, err
+	}
+	return impl.newArgsFromBearerToken(token)
+}
+
+func (impl *Impl) newArgsFromBearerToken(token string) (NewArgs, error) {
+	claims, err := impl.ValidateToken(token)
+	if err != nil {
+		return NewArgs{}, err
+	}
+	args := NewArgs{
+		AuthToken: token,
+	}
+	if loid, createdAt, ok := claims.LoIDClaim(); ok {
+		args.LoID = loid
+		args.LoIDCreatedAt = createdAt
+	}
+	return args, nil
+}
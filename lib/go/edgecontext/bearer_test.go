@@ -0,0 +1,129 @@
+package edgecontext_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/timebp"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestBearerToken(t *testing.T) {
+	for _, c := range []struct {
+		label  string
+		header string
+		want   string
+	}{
+		{
+			label:  "bearer",
+			header: "Bearer foo.bar.baz",
+			want:   "foo.bar.baz",
+		},
+		{
+			label:  "case-insensitive",
+			header: "bearer  foo.bar.baz ",
+			want:   "foo.bar.baz",
+		},
+		{
+			label: "empty",
+		},
+		{
+			label:  "no-token",
+			header: "Bearer ",
+		},
+		{
+			label:  "basic",
+			header: "Basic Zm9vOmJhcg==",
+		},
+		{
+			label:  "no-space",
+			header: "Bearerfoo.bar.baz",
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			var wantErr error
+			if c.want == "" {
+				wantErr = edgecontext.ErrNoBearerToken
+			}
+
+			h := make(http.Header)
+			if c.header != "" {
+				h.Set(edgecontext.AuthorizationHeader, c.header)
+			}
+			token, err := edgecontext.BearerToken(h)
+			if !errors.Is(err, wantErr) {
+				t.Errorf("BearerToken error mismatch: want %v, got %v", wantErr, err)
+			}
+			if token != c.want {
+				t.Errorf("BearerToken got %q, want %q", token, c.want)
+			}
+
+			md := make(map[string][]string)
+			if c.header != "" {
+				md["authorization"] = []string{c.header}
+			}
+			token, err = edgecontext.BearerTokenFromMetadata(md)
+			if !errors.Is(err, wantErr) {
+				t.Errorf("BearerTokenFromMetadata error mismatch: want %v, got %v", wantErr, err)
+			}
+			if token != c.want {
+				t.Errorf("BearerTokenFromMetadata got %q, want %q", token, c.want)
+			}
+		})
+	}
+}
+
+func TestNewArgsFromHeader(t *testing.T) {
+	key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{})
+	claims := edgecontext.AuthenticationToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "t2_example",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	claims.LoID.ID = expectedLoID
+	claims.LoID.CreatedAt = timebp.TimestampMillisecond(expectedCookieTime)
+	token, err := edgecontext.NewSigner(key, keyID).Sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := make(http.Header)
+	h.Set(edgecontext.AuthorizationHeader, "Bearer "+token)
+	args, err := impl.NewArgsFromHeader(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args.AuthToken != token {
+		t.Errorf("AuthToken got %q, want %q", args.AuthToken, token)
+	}
+	if args.LoID != expectedLoID {
+		t.Errorf("LoID got %q, want %q", args.LoID, expectedLoID)
+	}
+	if !args.LoIDCreatedAt.Equal(expectedCookieTime) {
+		t.Errorf("LoIDCreatedAt got %v, want %v", args.LoIDCreatedAt, expectedCookieTime)
+	}
+
+	e, err := edgecontext.New(context.Background(), impl, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := e.User().ID(); got != "t2_example" {
+		t.Errorf("User ID got %q, want %q", got, "t2_example")
+	}
+
+	md := map[string][]string{
+		"authorization": {"Bearer " + token[:len(token)-4] + "abcd"},
+	}
+	if _, err := impl.NewArgsFromMetadata(md); !errors.Is(err, edgecontext.ErrBadSignature) {
+		t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrBadSignature, err)
+	}
+	if _, err := impl.NewArgsFromMetadata(nil); !errors.Is(err, edgecontext.ErrNoBearerToken) {
+		t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrNoBearerToken, err)
+	}
+}
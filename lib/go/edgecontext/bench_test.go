@@ -0,0 +1,178 @@
+package edgecontext_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func BenchmarkFromHeader(b *testing.B) {
+	for _, c := range []struct {
+		label  string
+		header string
+	}{
+		{
+			label:  "no-auth",
+			header: headerWithNoAuth,
+		},
+		{
+			label:  "valid-auth",
+			header: headerWithValidAuth,
+		},
+	} {
+		b.Run(c.label, func(b *testing.B) {
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := edgecontext.FromHeader(ctx, c.header, globalTestImpl); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkFromHeaders(b *testing.B) {
+	const batchSize = 1000
+	headers := make([]string, batchSize)
+	for i := range headers {
+		headers[i] = headerWithValidAuth
+	}
+	ctx := context.Background()
+
+	b.Run("FromHeader", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, header := range headers {
+				if _, err := edgecontext.FromHeader(ctx, header, globalTestImpl); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("FromHeaders", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := edgecontext.FromHeaders(ctx, headers, globalTestImpl); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkRequestIDFromHeader(b *testing.B) {
+	b.Run("FromHeader", func(b *testing.B) {
+		ctx := context.Background()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ec, err := edgecontext.FromHeader(ctx, headerWithValidAuth, globalTestImpl)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = ec.RequestID()
+		}
+	})
+
+	b.Run("RequestIDFromHeader", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := edgecontext.RequestIDFromHeader(headerWithValidAuth); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkNew(b *testing.B) {
+	for _, c := range []struct {
+		label string
+		args  edgecontext.NewArgs
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label: "full",
+			args: edgecontext.NewArgs{
+				LoID:              expectedLoID,
+				LoIDCreatedAt:     expectedCookieTime,
+				SessionID:         expectedSessionID,
+				AuthToken:         validToken,
+				DeviceID:          expectedDeviceID,
+				CountryCode:       expectedCountryCode,
+				OriginServiceName: expectedOrigin,
+				RequestID:         expectedRequestID,
+				LocaleCode:        expectedLocaleCode,
+			},
+		},
+	} {
+		b.Run(c.label, func(b *testing.B) {
+			benchmarkNew(b, globalTestImpl, c.args)
+		})
+
+		b.Run(c.label+"-cached", func(b *testing.B) {
+			impl := newTestImpl(b, edgecontext.Config{
+				HeaderCacheSize: 16,
+			})
+			benchmarkNew(b, impl, c.args)
+		})
+	}
+}
+
+func benchmarkNew(b *testing.B, impl *edgecontext.Impl, args edgecontext.NewArgs) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := edgecontext.New(ctx, impl, args); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkValidateToken(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := globalTestImpl.ValidateToken(validToken); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkRoundTrip(b *testing.B) {
+	ctx := context.Background()
+	args := edgecontext.NewArgs{
+		LoID:              expectedLoID,
+		LoIDCreatedAt:     expectedCookieTime,
+		SessionID:         expectedSessionID,
+		AuthToken:         validToken,
+		DeviceID:          expectedDeviceID,
+		CountryCode:       expectedCountryCode,
+		OriginServiceName: expectedOrigin,
+		RequestID:         expectedRequestID,
+		LocaleCode:        expectedLocaleCode,
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			e, err := edgecontext.New(ctx, globalTestImpl, args)
+			if err != nil {
+				b.Fatal(err)
+			}
+			parsed, err := edgecontext.FromHeader(ctx, e.Header(), globalTestImpl)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := parsed.ValidatedAuthToken(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
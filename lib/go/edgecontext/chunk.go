@@ -0,0 +1,120 @@
+package edgecontext
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ChunkedHeaderPrefix is the prefix of the numbered HTTP headers used by
+// WriteChunkedHeader and ReadChunkedHeader.
+//
+// The full header names are ChunkedHeaderPrefix followed by the 1-based index
+// of the chunk, e.g. "X-Edge-Request-1", "X-Edge-Request-2", etc.
+const ChunkedHeaderPrefix = "X-Edge-Request-"
+
+// MaxHeaderChunks is the maximum number of chunks WriteChunkedHeader will
+// produce and ReadChunkedHeader will read.
+const MaxHeaderChunks = 64
+
+var (
+	// ErrInvalidChunkSize is returned by WriteChunkedHeader when the chunk size
+	// is not positive.
+	ErrInvalidChunkSize = errors.New("edgecontext: chunk size must be positive")
+
+	// ErrTooManyHeaderChunks is returned by WriteChunkedHeader and
+	// ReadChunkedHeader when the header needs more than MaxHeaderChunks chunks.
+	ErrTooManyHeaderChunks = errors.New("edgecontext: too many header chunks")
+)
+
+// SplitHeader splits an edge context header into chunks,
+// each of them at most chunkSize bytes long.
+//
+// An empty header results in nil chunks.
+func SplitHeader(header string, chunkSize int) ([]string, error) {
+	if chunkSize <= 0 {
+		return nil, ErrInvalidChunkSize
+	}
+	if header == "" {
+		return nil, nil
+	}
+	n := (len(header) + chunkSize - 1) / chunkSize
+	if n > MaxHeaderChunks {
+		return nil, ErrTooManyHeaderChunks
+	}
+	chunks := make([]string, 0, n)
+	for len(header) > chunkSize {
+		chunks = append(chunks, header[:chunkSize])
+		header = header[chunkSize:]
+	}
+	return append(chunks, header), nil
+}
+
+// JoinHeader reassembles chunks produced by SplitHeader back into the
+// original header.
+func JoinHeader(chunks []string) string {
+	return strings.Join(chunks, "")
+}
+
+// ChunkedHeaderName returns the name of the i-th (1-based) chunked header.
+func ChunkedHeaderName(i int) string {
+	return ChunkedHeaderPrefix + strconv.Itoa(i)
+}
+
+// WriteChunkedHeader base64 encodes value, which is usually the binary header
+// returned by EdgeRequestContext.Header, splits it with SplitHeader and writes
+// the chunks into h as numbered headers (see ChunkedHeaderPrefix).
+//
+// The value is encoded the same way as httpbp encodes the X-Edge-Request
+// header, as net/http rejects control bytes in header values and proxies could
+// trim whitespaces at the chunk boundaries.
+// chunkSize is the size of the encoded chunks.
+//
+// It's intended for infrastructure with hard per-header size caps.
+// Any stale chunks left in h from a previous, longer value are removed.
+func WriteChunkedHeader(h http.Header, value string, chunkSize int) error {
+	chunks, err := SplitHeader(base64.StdEncoding.EncodeToString([]byte(value)), chunkSize)
+	if err != nil {
+		return err
+	}
+	for i, chunk := range chunks {
+		h.Set(ChunkedHeaderName(i+1), chunk)
+	}
+	for i := len(chunks) + 1; i <= MaxHeaderChunks; i++ {
+		name := ChunkedHeaderName(i)
+		if h.Get(name) == "" {
+			break
+		}
+		h.Del(name)
+	}
+	return nil
+}
+
+// ReadChunkedHeader reads the numbered headers written by WriteChunkedHeader
+// from h, reassembles and base64 decodes them.
+//
+// Chunks are read in order starting from 1 until the first missing one.
+// If h contains no chunks, it returns an empty string and nil error.
+// If the reassembled value is not valid base64, it returns an error wrapping
+// ErrMalformedHeader.
+func ReadChunkedHeader(h http.Header) (string, error) {
+	var chunks []string
+	for i := 1; ; i++ {
+		chunk := h.Get(ChunkedHeaderName(i))
+		if chunk == "" {
+			break
+		}
+		if i > MaxHeaderChunks {
+			return "", ErrTooManyHeaderChunks
+		}
+		chunks = append(chunks, chunk)
+	}
+	value, err := base64.StdEncoding.DecodeString(JoinHeader(chunks))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrMalformedHeader, err)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,135 @@
+package edgecontext_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestSplitHeader(t *testing.T) {
+	for _, c := range []struct {
+		label     string
+		header    string
+		chunkSize int
+		expected  []string
+		err       error
+	}{
+		{
+			label:     "empty",
+			header:    "",
+			chunkSize: 4,
+		},
+		{
+			label:     "single-chunk",
+			header:    "abc",
+			chunkSize: 4,
+			expected:  []string{"abc"},
+		},
+		{
+			label:     "exact-multiple",
+			header:    "abcdefgh",
+			chunkSize: 4,
+			expected:  []string{"abcd", "efgh"},
+		},
+		{
+			label:     "remainder",
+			header:    "abcdefghij",
+			chunkSize: 4,
+			expected:  []string{"abcd", "efgh", "ij"},
+		},
+		{
+			label:     "invalid-size",
+			header:    "abc",
+			chunkSize: 0,
+			err:       edgecontext.ErrInvalidChunkSize,
+		},
+		{
+			label:     "too-many-chunks",
+			header:    strings.Repeat("a", edgecontext.MaxHeaderChunks+1),
+			chunkSize: 1,
+			err:       edgecontext.ErrTooManyHeaderChunks,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			chunks, err := edgecontext.SplitHeader(c.header, c.chunkSize)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if len(chunks) != len(c.expected) {
+				t.Fatalf("Expected chunks %q, got %q", c.expected, chunks)
+			}
+			for i := range chunks {
+				if chunks[i] != c.expected[i] {
+					t.Errorf("Expected chunks %q, got %q", c.expected, chunks)
+				}
+			}
+			if err == nil {
+				if joined := edgecontext.JoinHeader(chunks); joined != c.header {
+					t.Errorf("Expected joined header %q, got %q", c.header, joined)
+				}
+			}
+		})
+	}
+}
+
+func TestChunkedHTTPHeader(t *testing.T) {
+	newHeader := func(t *testing.T, deviceID string) string {
+		t.Helper()
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			LoID:      "t2_loid",
+			DeviceID:  deviceID,
+			UserAgent: "agent\r\n",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e.Header()
+	}
+	// The 10 bytes long device id is serialized with a "\n" byte in its length.
+	value := newHeader(t, "0123456789")
+	if !strings.Contains(value, "\x00") || !strings.Contains(value, "\n") {
+		t.Fatalf("Expected binary header, got %q", value)
+	}
+
+	h := make(http.Header)
+	if err := edgecontext.WriteChunkedHeader(h, newHeader(t, strings.Repeat("0", 100)), 16); err != nil {
+		t.Fatal(err)
+	}
+	if err := edgecontext.WriteChunkedHeader(h, value, 16); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for name, values := range h {
+		n++
+		for _, v := range values {
+			if strings.TrimSpace(v) != v || strings.ContainsAny(v, "\x00\r\n") {
+				t.Errorf("Expected header %s to be safe, got %q", name, v)
+			}
+		}
+	}
+	if expected := (base64.StdEncoding.EncodedLen(len(value)) + 15) / 16; n != expected {
+		t.Errorf("Expected %d chunks with the stale ones removed, got %d: %v", expected, n, h)
+	}
+
+	got, err := edgecontext.ReadChunkedHeader(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != value {
+		t.Errorf("Expected header %q, got %q", value, got)
+	}
+}
+
+func TestReadChunkedHeaderMalformed(t *testing.T) {
+	h := make(http.Header)
+	h.Set(edgecontext.ChunkedHeaderName(1), "not base64!")
+	if _, err := edgecontext.ReadChunkedHeader(h); !errors.Is(err, edgecontext.ErrMalformedHeader) {
+		t.Errorf("Expected error %v, got %v", edgecontext.ErrMalformedHeader, err)
+	}
+}
@@ -0,0 +1,70 @@
+package edgecontext
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// ClientVersionRegex validates that client versions are semantic versions,
+// with optional pre-release and build metadata parts.
+// e.g. 2023.21.0, 1.2.3-beta.1
+var ClientVersionRegex = regexp.MustCompile(`^\d+\.\d+\.\d+(-[\da-zA-Z.-]+)?(\+[\da-zA-Z.-]+)?$`)
+
+// Platform is the platform of the client making the request.
+type Platform string
+
+// Platform values.
+const (
+	PlatformIOS       Platform = "ios"
+	PlatformAndroid   Platform = "android"
+	PlatformWeb       Platform = "web"
+	PlatformMobileWeb Platform = "mweb"
+	PlatformAPI       Platform = "api"
+)
+
+// IsKnown returns true if p is one of the Platform constants.
+func (p Platform) IsKnown() bool {
+	switch p {
+	case PlatformIOS, PlatformAndroid, PlatformWeb, PlatformMobileWeb, PlatformAPI:
+		return true
+	}
+	return false
+}
+
+// IsMobileApp returns true if p is one of the native mobile app platforms.
+func (p Platform) IsMobileApp() bool {
+	return p == PlatformIOS || p == PlatformAndroid
+}
+
+// ClientVersion is the version of the client application making the request.
+type ClientVersion struct {
+	// The semantic version of the application, e.g. "2023.21.0".
+	Version string
+
+	// The build number of the application.
+	Build int64
+}
+
+// IsZero returns true if neither Version nor Build is set.
+func (v ClientVersion) IsZero() bool {
+	return v.Version == "" && v.Build == 0
+}
+
+// String returns the version in the form of "2023.21.0 (123456)",
+// or just the version when there's no build number.
+func (v ClientVersion) String() string {
+	if v.Build == 0 {
+		return v.Version
+	}
+	return v.Version + " (" + strconv.FormatInt(v.Build, 10) + ")"
+}
+
+func (v ClientVersion) validate() error {
+	if v.Version != "" && !ClientVersionRegex.MatchString(v.Version) {
+		return ErrInvalidClientVersion
+	}
+	if v.Build < 0 {
+		return ErrInvalidClientVersion
+	}
+	return nil
+}
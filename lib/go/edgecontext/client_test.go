@@ -0,0 +1,171 @@
+package edgecontext_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestClientVersion(t *testing.T) {
+	for _, c := range []struct {
+		label   string
+		version edgecontext.ClientVersion
+		str     string
+		err     error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label:   "version-and-build",
+			version: edgecontext.ClientVersion{Version: "2023.21.0", Build: 123456},
+			str:     "2023.21.0 (123456)",
+		},
+		{
+			label:   "pre-release",
+			version: edgecontext.ClientVersion{Version: "1.2.3-beta.1+exp.sha.5114f85"},
+			str:     "1.2.3-beta.1+exp.sha.5114f85",
+		},
+		{
+			label:   "invalid-version",
+			version: edgecontext.ClientVersion{Version: "1.2"},
+			err:     edgecontext.ErrInvalidClientVersion,
+		},
+		{
+			label:   "negative-build",
+			version: edgecontext.ClientVersion{Version: "1.2.3", Build: -1},
+			err:     edgecontext.ErrInvalidClientVersion,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				ClientVersion: c.version,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			got := reparse(t, e).ClientVersion()
+			if got != c.version {
+				t.Errorf("Expected client version %#v, got %#v", c.version, got)
+			}
+			if got.String() != c.str {
+				t.Errorf("Expected client version string %q, got %q", c.str, got.String())
+			}
+		})
+	}
+}
+
+func TestPlatform(t *testing.T) {
+	for _, c := range []struct {
+		platform  edgecontext.Platform
+		mobileApp bool
+		err       error
+	}{
+		{
+			platform: "",
+		},
+		{
+			platform:  edgecontext.PlatformIOS,
+			mobileApp: true,
+		},
+		{
+			platform:  edgecontext.PlatformAndroid,
+			mobileApp: true,
+		},
+		{
+			platform: edgecontext.PlatformWeb,
+		},
+		{
+			platform: edgecontext.PlatformMobileWeb,
+		},
+		{
+			platform: edgecontext.PlatformAPI,
+		},
+		{
+			platform: "windows-phone",
+			err:      edgecontext.ErrInvalidPlatform,
+		},
+	} {
+		t.Run(string(c.platform), func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				Platform: c.platform,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			got := reparse(t, e).Platform()
+			if got != c.platform {
+				t.Errorf("Expected platform %q, got %q", c.platform, got)
+			}
+			if got.IsMobileApp() != c.mobileApp {
+				t.Errorf("Expected IsMobileApp %v, got %v", c.mobileApp, got.IsMobileApp())
+			}
+		})
+	}
+}
+
+func TestInternalNetwork(t *testing.T) {
+	for _, internal := range []bool{false, true} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			InternalNetwork: internal,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reparse(t, e).IsInternalNetwork(); got != internal {
+			t.Errorf("Expected IsInternalNetwork %v, got %v", internal, got)
+		}
+	}
+}
+
+func TestLimitAdTracking(t *testing.T) {
+	for _, limit := range []bool{false, true} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			LimitAdTracking: limit,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reparse(t, e).LimitAdTracking(); got != limit {
+			t.Errorf("Expected LimitAdTracking %v, got %v", limit, got)
+		}
+	}
+}
+
+func TestAnonymizingProxy(t *testing.T) {
+	for _, proxy := range []bool{false, true} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			ClientIP:         "192.0.2.1",
+			AnonymizingProxy: proxy,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reparse(t, e).IsAnonymizingProxy(); got != proxy {
+			t.Errorf("Expected IsAnonymizingProxy %v, got %v", proxy, got)
+		}
+	}
+}
+
+func TestClientASN(t *testing.T) {
+	for _, asn := range []uint32{0, 7018, 4294967295} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			ClientASN: asn,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := reparse(t, e).ClientASN()
+		if got != asn || ok != (asn != 0) {
+			t.Errorf("Expected client ASN %d, got %d, %v", asn, got, ok)
+		}
+	}
+}
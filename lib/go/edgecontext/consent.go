@@ -0,0 +1,42 @@
+package edgecontext
+
+// Consent holds the privacy consents given by the user making the request.
+//
+// The zero value means no consent is given.
+type Consent struct {
+	// Whether the user consented to personalized ads.
+	AdsPersonalization bool
+
+	// Whether the user consented to analytics tracking.
+	Analytics bool
+
+	// Whether the user consented to sharing their data with third parties.
+	ThirdPartySharing bool
+}
+
+// ComplianceRegion is the data protection regime that applies to a request.
+type ComplianceRegion string
+
+// ComplianceRegion values.
+const (
+	// The EU General Data Protection Regulation (and UK GDPR).
+	ComplianceRegionGDPR ComplianceRegion = "gdpr"
+
+	// The California Consumer Privacy Act.
+	ComplianceRegionCCPA ComplianceRegion = "ccpa"
+
+	// The Brazilian General Data Protection Law.
+	ComplianceRegionLGPD ComplianceRegion = "lgpd"
+
+	// No special data protection regime applies.
+	ComplianceRegionNone ComplianceRegion = "none"
+)
+
+// IsKnown returns true if r is one of the ComplianceRegion constants.
+func (r ComplianceRegion) IsKnown() bool {
+	switch r {
+	case ComplianceRegionGDPR, ComplianceRegionCCPA, ComplianceRegionLGPD, ComplianceRegionNone:
+		return true
+	}
+	return false
+}
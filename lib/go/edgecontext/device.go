@@ -0,0 +1,64 @@
+package edgecontext
+
+// FormFactor is the coarse form factor of the device making the request.
+//
+// Rendering and pagination services can use it to adapt their responses
+// without sniffing the User-Agent.
+type FormFactor string
+
+// FormFactor values.
+const (
+	FormFactorPhone   FormFactor = "phone"
+	FormFactorTablet  FormFactor = "tablet"
+	FormFactorDesktop FormFactor = "desktop"
+	FormFactorTV      FormFactor = "tv"
+	FormFactorBot     FormFactor = "bot"
+)
+
+// IsKnown returns true if f is one of the FormFactor constants.
+func (f FormFactor) IsKnown() bool {
+	switch f {
+	case FormFactorPhone, FormFactorTablet, FormFactorDesktop, FormFactorTV, FormFactorBot:
+		return true
+	}
+	return false
+}
+
+// AttestationVerdict is the verdict of the mobile device attestation
+// (Play Integrity on Android, App Attest on iOS) evaluated at the edge.
+type AttestationVerdict string
+
+// AttestationVerdict values.
+const (
+	// The app and device passed hardware-backed attestation.
+	AttestationVerdictStrong AttestationVerdict = "strong"
+
+	// The app is running on a genuine, certified device.
+	AttestationVerdictDevice AttestationVerdict = "device"
+
+	// The app is running on a device that passed only basic integrity checks,
+	// e.g. an uncertified or rooted device.
+	AttestationVerdictBasic AttestationVerdict = "basic"
+
+	// The device failed attestation.
+	AttestationVerdictFailed AttestationVerdict = "failed"
+
+	// Attestation could not be evaluated, e.g. the platform or the client
+	// version does not support it, or the attestation service was unreachable.
+	AttestationVerdictUnavailable AttestationVerdict = "unavailable"
+)
+
+// IsKnown returns true if v is one of the AttestationVerdict constants.
+func (v AttestationVerdict) IsKnown() bool {
+	switch v {
+	case AttestationVerdictStrong, AttestationVerdictDevice, AttestationVerdictBasic, AttestationVerdictFailed, AttestationVerdictUnavailable:
+		return true
+	}
+	return false
+}
+
+// IsTrusted returns true if v indicates a genuine device,
+// either AttestationVerdictStrong or AttestationVerdictDevice.
+func (v AttestationVerdict) IsTrusted() bool {
+	return v == AttestationVerdictStrong || v == AttestationVerdictDevice
+}
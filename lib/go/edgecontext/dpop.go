@@ -0,0 +1,447 @@
+package edgecontext
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultDPoPMaxProofAge is the default DPoPConfig.MaxProofAge.
+const DefaultDPoPMaxProofAge = 5 * time.Minute
+
+// dpopProofType is the required typ header of DPoP proofs.
+const dpopProofType = "dpop+jwt"
+
+var (
+	// ErrDPoPProofRequired is an error returned by ValidateTokenWithDPoP
+	// (and ValidateToken when DPoPConfig.RequireProof is true)
+	// indicates that the token is bound to a DPoP key, but no DPoP proof was
+	// sent with it.
+	ErrDPoPProofRequired = errors.New("edgecontext.ValidateTokenWithDPoP: token is bound to a DPoP key but no proof was sent")
+
+	// ErrInvalidDPoPProof is an error returned by ValidateTokenWithDPoP
+	// indicates that the DPoP proof is malformed, not properly signed,
+	// or does not match the request.
+	ErrInvalidDPoPProof = errors.New("edgecontext.ValidateTokenWithDPoP: invalid DPoP proof")
+
+	// ErrDPoPKeyMismatch is an error returned by ValidateTokenWithDPoP
+	// indicates that the token is not bound to the key signing the DPoP proof.
+	ErrDPoPKeyMismatch = errors.New("edgecontext.ValidateTokenWithDPoP: token is not bound to the DPoP proof key")
+
+	// ErrDPoPUseNonce is an error returned by ValidateTokenWithDPoP
+	// indicates that DPoPConfig.ValidateNonce rejected the nonce of the proof.
+	//
+	// The caller should respond with a "use_dpop_nonce" error and a fresh
+	// nonce in the DPoP-Nonce header, as defined in RFC 9449 section 8.
+	ErrDPoPUseNonce = errors.New("edgecontext.ValidateTokenWithDPoP: invalid DPoP nonce")
+
+	// ErrDPoPReplay is an error returned by ValidateTokenWithDPoP indicates
+	// that DPoPConfig.CheckReplay rejected the proof as a replay.
+	ErrDPoPReplay = errors.New("edgecontext.ValidateTokenWithDPoP: DPoP proof replayed")
+)
+
+// DPoPConfig is the configuration for ValidateTokenWithDPoP.
+//
+// All fields are optional.
+type DPoPConfig struct {
+	// The max age of the iat claim of the DPoP proofs,
+	// proofs issued more than that in the past or in the future are rejected.
+	// When it's non-positive, DefaultDPoPMaxProofAge will be used.
+	MaxProofAge time.Duration
+
+	// When ValidateNonce is non-nil, it's called with the nonce claim of every
+	// DPoP proof, which is empty when the proof doesn't have one.
+	// When it returns an error, ValidateTokenWithDPoP returns ErrDPoPUseNonce.
+	//
+	// Issuing the nonces (via the DPoP-Nonce response header) is up to the
+	// caller.
+	ValidateNonce func(nonce string) error
+
+	// When CheckReplay is non-nil, it's called with the jti and iat claims of
+	// every DPoP proof after the proof is otherwise validated.
+	// It should return an error if the jti was already seen within
+	// MaxProofAge.
+	// When it returns an error, ValidateTokenWithDPoP returns ErrDPoPReplay.
+	CheckReplay func(jti string, issuedAt time.Time) error
+
+	// When RequireProof is true, ValidateToken (and in turn
+	// EdgeRequestContext.ValidatedAuthToken) rejects tokens bound to a DPoP key
+	// with ErrDPoPProofRequired,
+	// so they are only accepted by ValidateTokenWithDPoP with a valid proof.
+	//
+	// It's false by default, for the services receiving the bound tokens
+	// through the edge context after the edge verified the proofs,
+	// in which case the binding is only enforced by the callers of
+	// ValidateTokenWithDPoP.
+	RequireProof bool
+}
+
+func (cfg DPoPConfig) maxProofAge() time.Duration {
+	if cfg.MaxProofAge <= 0 {
+		return DefaultDPoPMaxProofAge
+	}
+	return cfg.MaxProofAge
+}
+
+// DPoPProof is the DPoP (RFC 9449) proof sent with a request.
+type DPoPProof struct {
+	// The value of the DPoP request header.
+	Proof string
+
+	// The HTTP method and URL of the request,
+	// to be checked against the htm and htu claims of the proof.
+	Method string
+	URL    string
+}
+
+// dpopProofClaims defines the json format of DPoP proofs.
+//
+// NOTE: jti and iat claims are in RegisteredClaims.
+type dpopProofClaims struct {
+	jwt.RegisteredClaims
+
+	HTM   string `json:"htm"`
+	HTU   string `json:"htu"`
+	ATH   string `json:"ath"`
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// dpopParser is the jwt.Parser used for DPoP proofs,
+// which are always signed by asymmetric keys.
+var dpopParser = jwt.NewParser(jwt.WithValidMethods([]string{
+	"RS256",
+	"PS256",
+	"ES256",
+	"ES384",
+	"EdDSA",
+}))
+
+// ValidateTokenWithDPoP is like ValidateToken,
+// but also validates the DPoP (RFC 9449) proof sent with the token,
+// for clients sending sender-constrained tokens.
+//
+// Tokens bound to a DPoP key (with the jkt confirmation claim) must be sent
+// with a valid proof signed by that key, so a stolen token is useless without
+// the private key.
+// Tokens not bound to a DPoP key are treated as bearer tokens,
+// and must be sent without a proof.
+//
+// The binding is only enforced here,
+// unless DPoPConfig.RequireProof is true,
+// ValidateToken still accepts the bound tokens without proofs.
+func (impl *Impl) ValidateTokenWithDPoP(token string, proof DPoPProof) (*AuthenticationToken, error) {
+	claims, err := impl.validateToken(token, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := impl.dpop.verify(token, claims, proof, time.Now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (cfg DPoPConfig) verify(token string, claims *AuthenticationToken, proof DPoPProof, now time.Time) error {
+	jkt, bound := claims.DPoPThumbprint()
+	if proof.Proof == "" {
+		if bound {
+			return ErrDPoPProofRequired
+		}
+		return nil
+	}
+	if !bound {
+		return ErrDPoPKeyMismatch
+	}
+
+	var thumbprint string
+	pc := new(dpopProofClaims)
+	tok, err := dpopParser.ParseWithClaims(
+		proof.Proof,
+		pc,
+		func(jt *jwt.Token) (interface{}, error) {
+			if typ, _ := jt.Header["typ"].(string); typ != dpopProofType {
+				return nil, fmt.Errorf("typ header should be %q, got %q", dpopProofType, typ)
+			}
+			key, err := parseJWK(jt.Header["jwk"])
+			if err != nil {
+				return nil, err
+			}
+			thumbprint, err = DPoPKeyThumbprint(key)
+			if err != nil {
+				return nil, err
+			}
+			return key, nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDPoPProof, err)
+	}
+	if !tok.Valid {
+		return ErrInvalidDPoPProof
+	}
+	if thumbprint != jkt {
+		return ErrDPoPKeyMismatch
+	}
+
+	if pc.ID == "" {
+		return fmt.Errorf("%w: missing jti claim", ErrInvalidDPoPProof)
+	}
+	if pc.HTM != proof.Method {
+		return fmt.Errorf("%w: htm claim %q does not match method %q", ErrInvalidDPoPProof, pc.HTM, proof.Method)
+	}
+	if !htuMatches(pc.HTU, proof.URL) {
+		return fmt.Errorf("%w: htu claim %q does not match url %q", ErrInvalidDPoPProof, pc.HTU, proof.URL)
+	}
+	if pc.IssuedAt == nil {
+		return fmt.Errorf("%w: missing iat claim", ErrInvalidDPoPProof)
+	}
+	if age := now.Sub(pc.IssuedAt.Time); age > cfg.maxProofAge() || -age > cfg.maxProofAge() {
+		return fmt.Errorf("%w: iat claim %v is out of range", ErrInvalidDPoPProof, pc.IssuedAt.Time)
+	}
+	if pc.ATH != accessTokenHash(token) {
+		return fmt.Errorf("%w: ath claim does not match the token", ErrInvalidDPoPProof)
+	}
+
+	if cfg.ValidateNonce != nil {
+		if err := cfg.ValidateNonce(pc.Nonce); err != nil {
+			return fmt.Errorf("%w: %v", ErrDPoPUseNonce, err)
+		}
+	}
+	if cfg.CheckReplay != nil {
+		if err := cfg.CheckReplay(pc.ID, pc.IssuedAt.Time); err != nil {
+			return fmt.Errorf("%w: %v", ErrDPoPReplay, err)
+		}
+	}
+	return nil
+}
+
+// accessTokenHash calculates the ath claim of the DPoP proofs for the token.
+func accessTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// htuMatches compares the htu claim of a DPoP proof against the request url,
+// ignoring the query and fragment parts,
+// as defined in RFC 9449 section 4.3.
+func htuMatches(htu, requestURL string) bool {
+	a, err := normalizeHTU(htu)
+	if err != nil {
+		return false
+	}
+	b, err := normalizeHTU(requestURL)
+	if err != nil {
+		return false
+	}
+	return a == b
+}
+
+// normalizeHTU applies the syntax-based and scheme-based normalizations of
+// RFC 3986 section 6.2.2 and 6.2.3 that matter in practice to an url.
+func normalizeHTU(s string) (string, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return "", fmt.Errorf("url %q is not absolute", s)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	switch {
+	case u.Scheme == "https" && u.Port() == "443",
+		u.Scheme == "http" && u.Port() == "80":
+		u.Host = u.Hostname()
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.User = nil
+	return u.String(), nil
+}
+
+// jwk is the json format of the public JSON Web Keys (RFC 7517) embedded in
+// DPoP proofs and served by JWKS endpoints.
+type jwk struct {
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	D   string `json:"d,omitempty"`
+}
+
+// parseJWK parses the jwk header of a DPoP proof into a public key.
+func parseJWK(header interface{}) (crypto.PublicKey, error) {
+	if header == nil {
+		return nil, errors.New("missing jwk header")
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	var k jwk
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("malformed jwk header: %w", err)
+	}
+	return k.publicKey()
+}
+
+// publicKey converts the jwk into a public key.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	if k.D != "" {
+		return nil, errors.New("jwk should not contain a private key")
+	}
+
+	switch k.Kty {
+	default:
+		return nil, fmt.Errorf("unsupported jwk kty %q", k.Kty)
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("jwk rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		default:
+			return nil, fmt.Errorf("unsupported jwk crv %q", k.Crv)
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		}
+		x, err := decodeJWKInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("jwk ec point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported jwk crv %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("malformed jwk x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("jwk ed25519 key has wrong size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+}
+
+func decodeJWKInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("malformed jwk integer: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty jwk integer")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// DPoPKeyThumbprint calculates the JWK SHA-256 thumbprint (RFC 7638) of a
+// public key, which is the jkt confirmation claim of the tokens bound to that
+// key.
+//
+// Supported key types are *rsa.PublicKey, *ecdsa.PublicKey (P-256 and P-384)
+// and ed25519.PublicKey.
+func DPoPKeyThumbprint(pub crypto.PublicKey) (string, error) {
+	// The required members of the keys in lexicographic order,
+	// as json.Marshal keeps the order of the struct fields.
+	var v interface{}
+	switch key := pub.(type) {
+	default:
+		return "", fmt.Errorf("edgecontext.DPoPKeyThumbprint: unsupported key type %T", pub)
+	case *rsa.PublicKey:
+		v = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{
+			E:   encodeJWKBytes(big.NewInt(int64(key.E)).Bytes()),
+			Kty: "RSA",
+			N:   encodeJWKBytes(key.N.Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		var crv string
+		switch key.Curve {
+		default:
+			return "", fmt.Errorf("edgecontext.DPoPKeyThumbprint: unsupported curve %v", key.Curve.Params().Name)
+		case elliptic.P256():
+			crv = "P-256"
+		case elliptic.P384():
+			crv = "P-384"
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		v = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{
+			Crv: crv,
+			Kty: "EC",
+			X:   encodeJWKBytes(key.X.FillBytes(make([]byte, size))),
+			Y:   encodeJWKBytes(key.Y.FillBytes(make([]byte, size))),
+		}
+	case ed25519.PublicKey:
+		v = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{
+			Crv: "Ed25519",
+			Kty: "OKP",
+			X:   encodeJWKBytes(key),
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func encodeJWKBytes(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,414 @@
+package edgecontext_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestDPoPKeyThumbprint(t *testing.T) {
+	// Example from RFC 7638 section 3.1.
+	n, err := base64.RawURLEncoding.DecodeString(
+		"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: 65537,
+	}
+	got, err := edgecontext.DPoPKeyThumbprint(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	if got != want {
+		t.Errorf("DPoPKeyThumbprint got %q, want %q", got, want)
+	}
+
+	if _, err := edgecontext.DPoPKeyThumbprint("foo"); err == nil {
+		t.Error("Expected error for unsupported key type, got nil")
+	}
+}
+
+// dpopProofKey is a private key used to sign DPoP proofs in tests.
+type dpopProofKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+	jwk    map[string]interface{}
+	jkt    string
+}
+
+func newECDSAProofKey(t testing.TB) dpopProofKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jkt, err := edgecontext.DPoPKeyThumbprint(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dpopProofKey{
+		method: jwt.SigningMethodES256,
+		key:    key,
+		jwk: map[string]interface{}{
+			"kty": "EC",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		},
+		jkt: jkt,
+	}
+}
+
+func newEd25519ProofKey(t testing.TB) dpopProofKey {
+	t.Helper()
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jkt, err := edgecontext.DPoPKeyThumbprint(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dpopProofKey{
+		method: jwt.SigningMethodEdDSA,
+		key:    key,
+		jwk: map[string]interface{}{
+			"kty": "OKP",
+			"crv": "Ed25519",
+			"x":   base64.RawURLEncoding.EncodeToString(pub),
+		},
+		jkt: jkt,
+	}
+}
+
+// sign signs a DPoP proof with the claims,
+// after applying the header overrides.
+func (k dpopProofKey) sign(t testing.TB, claims jwt.MapClaims, header map[string]interface{}) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["typ"] = "dpop+jwt"
+	token.Header["jwk"] = k.jwk
+	for name, value := range header {
+		if value == nil {
+			delete(token.Header, name)
+		} else {
+			token.Header[name] = value
+		}
+	}
+	signed, err := token.SignedString(k.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestValidateTokenWithDPoP(t *testing.T) {
+	const (
+		method = "POST"
+		url    = "https://oauth.reddit.com/api/comment"
+		nonce  = "server-nonce"
+	)
+
+	seen := make(map[string]bool)
+	key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{
+		DPoP: edgecontext.DPoPConfig{
+			ValidateNonce: func(n string) error {
+				if n != nonce {
+					return errors.New("unknown nonce")
+				}
+				return nil
+			},
+			CheckReplay: func(jti string, _ time.Time) error {
+				if seen[jti] {
+					return errors.New("jti already seen")
+				}
+				seen[jti] = true
+				return nil
+			},
+		},
+	})
+	signer := edgecontext.NewSigner(key, keyID)
+	proofKey := newECDSAProofKey(t)
+
+	sign := func(t *testing.T, jkt string) string {
+		t.Helper()
+
+		claims := edgecontext.AuthenticationToken{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "t2_user",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+		if jkt != "" {
+			claims.Confirmation = &edgecontext.TokenConfirmation{
+				JWKThumbprint: jkt,
+			}
+		}
+		signed, err := signer.Sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	bound := sign(t, proofKey.jkt)
+	bearer := sign(t, "")
+
+	proofClaims := func(token string) jwt.MapClaims {
+		sum := sha256.Sum256([]byte(token))
+		return jwt.MapClaims{
+			"htm":   method,
+			"htu":   url,
+			"iat":   time.Now().Unix(),
+			"ath":   base64.RawURLEncoding.EncodeToString(sum[:]),
+			"nonce": nonce,
+		}
+	}
+
+	var jti int
+	for _, c := range []struct {
+		label    string
+		token    string
+		key      dpopProofKey
+		claims   func(jwt.MapClaims)
+		header   map[string]interface{}
+		noProof  bool
+		url      string
+		expected error
+	}{
+		{
+			label: "valid",
+			token: bound,
+		},
+		{
+			label: "valid-url-normalized",
+			token: bound,
+			url:   "HTTPS://oauth.reddit.com:443/api/comment?foo=bar#baz",
+		},
+		{
+			label:   "bearer-without-proof",
+			token:   bearer,
+			noProof: true,
+		},
+		{
+			label:    "bearer-with-proof",
+			token:    bearer,
+			expected: edgecontext.ErrDPoPKeyMismatch,
+		},
+		{
+			label:    "bound-without-proof",
+			token:    bound,
+			noProof:  true,
+			expected: edgecontext.ErrDPoPProofRequired,
+		},
+		{
+			label:    "other-key",
+			token:    bound,
+			key:      newECDSAProofKey(t),
+			expected: edgecontext.ErrDPoPKeyMismatch,
+		},
+		{
+			label:    "wrong-typ",
+			token:    bound,
+			header:   map[string]interface{}{"typ": "JWT"},
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label:    "missing-jwk",
+			token:    bound,
+			header:   map[string]interface{}{"jwk": nil},
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label: "private-jwk",
+			token: bound,
+			header: map[string]interface{}{"jwk": map[string]interface{}{
+				"kty": "EC",
+				"crv": "P-256",
+				"x":   proofKey.jwk["x"],
+				"y":   proofKey.jwk["y"],
+				"d":   "foo",
+			}},
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label:    "missing-jti",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { delete(c, "jti") },
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label:    "wrong-htm",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { c["htm"] = "GET" },
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label:    "wrong-htu",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { c["htu"] = "https://oauth.reddit.com/api/vote" },
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label:    "stale-iat",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { c["iat"] = time.Now().Add(-time.Hour).Unix() },
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label:    "future-iat",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() },
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label:    "missing-iat",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { delete(c, "iat") },
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label:    "wrong-ath",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { c["ath"] = proofClaims(bearer)["ath"] },
+			expected: edgecontext.ErrInvalidDPoPProof,
+		},
+		{
+			label:    "wrong-nonce",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { c["nonce"] = "foo" },
+			expected: edgecontext.ErrDPoPUseNonce,
+		},
+		{
+			label:    "missing-nonce",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { delete(c, "nonce") },
+			expected: edgecontext.ErrDPoPUseNonce,
+		},
+		{
+			label:    "replay",
+			token:    bound,
+			claims:   func(c jwt.MapClaims) { c["jti"] = "jti-1" },
+			expected: edgecontext.ErrDPoPReplay,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			proof := edgecontext.DPoPProof{
+				Method: method,
+				URL:    url,
+			}
+			if c.url != "" {
+				proof.URL = c.url
+			}
+			if !c.noProof {
+				claims := proofClaims(c.token)
+				jti++
+				claims["jti"] = "jti-" + strconv.Itoa(jti)
+				if c.claims != nil {
+					c.claims(claims)
+				}
+				key := c.key
+				if key.key == nil {
+					key = proofKey
+				}
+				proof.Proof = key.sign(t, claims, c.header)
+			}
+
+			token, err := impl.ValidateTokenWithDPoP(c.token, proof)
+			if !errors.Is(err, c.expected) {
+				t.Fatalf("Expected error %v, got %v", c.expected, err)
+			}
+			if err == nil && token.Subject() != "t2_user" {
+				t.Errorf("Expected subject %q, got %q", "t2_user", token.Subject())
+			}
+		})
+	}
+
+	t.Run("ed25519", func(t *testing.T) {
+		key := newEd25519ProofKey(t)
+		token := sign(t, key.jkt)
+		claims := proofClaims(token)
+		claims["jti"] = "jti-ed25519"
+		if _, err := impl.ValidateTokenWithDPoP(token, edgecontext.DPoPProof{
+			Proof:  key.sign(t, claims, nil),
+			Method: method,
+			URL:    url,
+		}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestDPoPRequireProof(t *testing.T) {
+	proofKey := newECDSAProofKey(t)
+	claims := edgecontext.AuthenticationToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "t2_user",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Confirmation: &edgecontext.TokenConfirmation{
+			JWKThumbprint: proofKey.jkt,
+		},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		// The binding is intentionally not enforced by ValidateToken by default,
+		// for the tokens coming from the edge, which already verified the proofs.
+		key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{})
+		bound, err := edgecontext.NewSigner(key, keyID).Sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := impl.ValidateToken(bound); err != nil {
+			t.Errorf("ValidateToken returned error: %v", err)
+		}
+		if _, err := impl.ValidateTokenWithDPoP(bound, edgecontext.DPoPProof{}); !errors.Is(err, edgecontext.ErrDPoPProofRequired) {
+			t.Errorf("Expected ErrDPoPProofRequired, got %v", err)
+		}
+	})
+
+	t.Run("require-proof", func(t *testing.T) {
+		key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{
+			DPoP: edgecontext.DPoPConfig{
+				RequireProof: true,
+			},
+		})
+		signer := edgecontext.NewSigner(key, keyID)
+		bound, err := signer.Sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := impl.ValidateToken(bound); !errors.Is(err, edgecontext.ErrDPoPProofRequired) {
+			t.Errorf("Expected ErrDPoPProofRequired, got %v", err)
+		}
+
+		bearerClaims := claims
+		bearerClaims.Confirmation = nil
+		bearer, err := signer.Sign(bearerClaims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := impl.ValidateToken(bearer); err != nil {
+			t.Errorf("ValidateToken returned error for bearer token: %v", err)
+		}
+	})
+}
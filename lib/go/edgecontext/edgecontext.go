@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/reddit/baseplate.go/detach"
 	"github.com/reddit/baseplate.go/ecinterface"
 	"github.com/reddit/baseplate.go/log"
@@ -38,6 +42,68 @@ func init() {
 // LoIDPrefix is the prefix for all LoIDs.
 const LoIDPrefix = "t2_"
 
+// TimezoneRegex validates that time zones are formatted like IANA time zone
+// database names.
+// e.g. UTC, America/New_York, America/Argentina/Buenos_Aires, Etc/GMT+5
+//
+// Note that it only checks the format, not whether the time zone exists.
+var TimezoneRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z_-]*(/[A-Za-z0-9_+-]+)*$`)
+
+// MaxCityLength is the maximum length, in bytes, of the city name in
+// NewArgs.
+const MaxCityLength = 128
+
+// MaxDeviceFieldLength is the maximum length, in bytes, of the device model,
+// OS name and OS version in NewArgs.
+const MaxDeviceFieldLength = 64
+
+// Limits of NewArgs.FeatureFlagOverrides.
+const (
+	MaxFeatureFlagOverrides   = 16
+	MaxFeatureFlagNameLength  = 64
+	MaxFeatureFlagValueLength = 256
+)
+
+// Limits of NewArgs.Baggage.
+const (
+	MaxBaggageEntries     = 32
+	MaxBaggageKeyLength   = 64
+	MaxBaggageValueLength = 256
+)
+
+// BaggageKeyRegex validates the keys of NewArgs.Baggage.
+var BaggageKeyRegex = regexp.MustCompile(`^[a-zA-Z\d_.-]+$`)
+
+// TraceIDRegex validates that trace ids are formatted as W3C Trace Context
+// trace ids.
+var TraceIDRegex = regexp.MustCompile(`^[\da-f]{32}$`)
+
+// invalidTraceID is the all zeros trace id, which is forbidden by W3C Trace
+// Context.
+const invalidTraceID = "00000000000000000000000000000000"
+
+// WorkloadIdentityRegex validates that workload identities are SPIFFE IDs,
+// e.g. spiffe://reddit.com/ns/jobs/sa/backfill.
+var WorkloadIdentityRegex = regexp.MustCompile(`^spiffe://[a-z\d._-]+(/[a-zA-Z\d._-]+)*$`)
+
+// MaxWorkloadIdentityLength is the maximum length, in bytes, of the workload
+// identity in NewArgs, as defined by the SPIFFE ID specification.
+const MaxWorkloadIdentityLength = 2048
+
+// TenantIDRegex validates that tenant ids only consist of lowercase letters,
+// digits, underscores and hyphens, and start with a letter or digit.
+var TenantIDRegex = regexp.MustCompile(`^[a-z\d][a-z\d_-]*$`)
+
+// MaxTenantIDLength is the maximum length, in bytes, of the tenant id in
+// NewArgs.
+const MaxTenantIDLength = 64
+
+// SubdivisionCodeRegex validates that subdivision codes are formatted as ISO
+// 3166-2 codes: an ISO 3166-1 alpha-2 country code and up to three
+// alphanumeric characters separated by a hyphen.
+// e.g. US-CA, GB-ENG, FR-75
+var SubdivisionCodeRegex = regexp.MustCompile(`^[A-Z]{2}-[A-Z\d]{1,3}$`)
+
 // LocaleRegex validates that locale codes are correctly formatted. They can contain
 // either a language, or a language and region specifier separated by an underscore.
 // e.g. en, en_US
@@ -50,6 +116,94 @@ var (
 
 	// ErrInvalidLocaleCode is returned by New() when an invalid locale code is passed in.
 	ErrInvalidLocaleCode = errors.New("edgecontext: locale code should match format: en, en_US")
+
+	// ErrInvalidClientIP is returned by New() when the client ip is not a valid
+	// IPv4 or IPv6 address.
+	ErrInvalidClientIP = errors.New("edgecontext: client ip should be a valid IPv4 or IPv6 address")
+
+	// ErrInvalidClientVersion is returned by New() when the client version is not
+	// a valid semantic version, or the build number is negative.
+	ErrInvalidClientVersion = errors.New("edgecontext: client version should be a semantic version like 2023.21.0, with non-negative build number")
+
+	// ErrInvalidPlatform is returned by New() when the platform is not one of the
+	// Platform constants.
+	ErrInvalidPlatform = errors.New("edgecontext: unknown platform")
+
+	// ErrInvalidTimezone is returned by New() when an invalid time zone is passed
+	// in.
+	ErrInvalidTimezone = errors.New("edgecontext: time zone should be an IANA time zone database name like America/New_York")
+
+	// ErrInvalidSubdivisionCode is returned by New() when the subdivision code is
+	// not a valid ISO 3166-2 code, or does not belong to the country code.
+	ErrInvalidSubdivisionCode = errors.New("edgecontext: subdivision code should be an ISO 3166-2 code of the country code, like US-CA")
+
+	// ErrInvalidCity is returned by New() when the city name is too long or is not
+	// valid UTF-8.
+	ErrInvalidCity = errors.New("edgecontext: city should be valid UTF-8 of at most 128 bytes")
+
+	// ErrInvalidMetroCode is returned by New() when the metro code is not a
+	// 3-digit Nielsen DMA code.
+	ErrInvalidMetroCode = errors.New("edgecontext: metro code should be a 3-digit DMA code")
+
+	// ErrInvalidGeoProvenance is returned by New() when the geolocation
+	// provenance is not one of the GeoProvenance constants.
+	ErrInvalidGeoProvenance = errors.New("edgecontext: unknown geolocation provenance")
+
+	// ErrInvalidFeatureFlagOverrides is returned by New() when the feature flag
+	// overrides exceed the size limits, or have an empty feature flag name.
+	ErrInvalidFeatureFlagOverrides = errors.New("edgecontext: feature flag overrides exceed size limits")
+
+	// ErrInvalidComplianceRegion is returned by New() when the compliance region
+	// is not one of the ComplianceRegion constants.
+	ErrInvalidComplianceRegion = errors.New("edgecontext: unknown compliance region")
+
+	// ErrInvalidBaggage is returned by New() when the baggage exceeds the size
+	// limits, or has invalid keys.
+	ErrInvalidBaggage = errors.New("edgecontext: invalid baggage")
+
+	// ErrInvalidTraceID is returned by New() when the trace id is not a valid W3C
+	// Trace Context trace id.
+	ErrInvalidTraceID = errors.New("edgecontext: trace id should be 32 lowercase hex characters, not all zeros")
+
+	// ErrInvalidDevice is returned by New() when the device model, OS name or OS
+	// version is too long or is not valid UTF-8.
+	ErrInvalidDevice = errors.New("edgecontext: device model, os name and os version should be valid UTF-8 of at most 64 bytes")
+
+	// ErrInvalidFormFactor is returned by New() when the device form factor is
+	// not one of the FormFactor constants.
+	ErrInvalidFormFactor = errors.New("edgecontext: unknown device form factor")
+
+	// ErrInvalidSessionType is returned by New() when the session type is not
+	// one of the SessionType constants.
+	ErrInvalidSessionType = errors.New("edgecontext: unknown session type")
+
+	// ErrInvalidAttestationVerdict is returned by New() when the device
+	// attestation verdict is not one of the AttestationVerdict constants.
+	ErrInvalidAttestationVerdict = errors.New("edgecontext: unknown device attestation verdict")
+
+	// ErrInvalidAuthMethod is returned by New() when the authentication method
+	// is not one of the AuthMethod constants.
+	ErrInvalidAuthMethod = errors.New("edgecontext: unknown authentication method")
+
+	// ErrInvalidWorkloadIdentity is returned by New() when the workload identity
+	// is not a valid SPIFFE ID.
+	ErrInvalidWorkloadIdentity = errors.New("edgecontext: workload identity should be a SPIFFE ID of at most 2048 bytes")
+
+	// ErrInvalidHops is returned by New() when the hop list exceeds the size
+	// limits, or contains invalid service names.
+	ErrInvalidHops = errors.New("edgecontext: invalid hops")
+
+	// ErrInvalidTenantID is returned by New() when the tenant id is too long or
+	// does not match TenantIDRegex.
+	ErrInvalidTenantID = errors.New("edgecontext: tenant id should match TenantIDRegex and be at most 64 bytes")
+
+	// ErrInvalidReferringSurface is returned by New() when the referring surface
+	// is not one of the ReferringSurface constants.
+	ErrInvalidReferringSurface = errors.New("edgecontext: unknown referring surface")
+
+	// ErrTrailingBytes is returned by FromHeader in strict mode when there are
+	// leftover bytes after the thrift payload in the header.
+	ErrTrailingBytes = errors.New("edgecontext: trailing bytes after header payload")
 )
 
 // An Impl is an initialized edge context implementation.
@@ -60,7 +214,54 @@ var (
 type Impl struct {
 	store     *secrets.Store
 	logger    log.Wrapper
+	strict    bool
+	lazy      bool
 	keysValue atomic.Value
+
+	// pasetoKeysValue holds the *keysType for PASETO tokens.
+	pasetoKeysValue atomic.Value
+
+	parser      *jwt.Parser
+	tokenCache  *tokenCache
+	headerCache *headerCache
+	dpop        DPoPConfig
+
+	introspector      TokenIntrospector
+	revocationChecker RevocationChecker
+	requiredClaims    []string
+	tokenType         string
+	audiences         []string
+	issuers           map[string]*issuer
+	strictKeyID       bool
+	keyAlgs           map[string]string
+	pubKeySecretPaths []string
+	// pasetoPubKeySecretPath is Config.PASETOPubKeySecretPath.
+	pasetoPubKeySecretPath string
+	simplePubKeys          bool
+
+	// externalKeys is true when the default keys are loaded from Config.JWKS,
+	// Config.KeyDir or Config.LocalKeys instead of the secrets store.
+	externalKeys bool
+	// closers stop the background loading of the keys, see Close.
+	closers []func()
+
+	maxKeyStaleness time.Duration
+	// keysRefreshed holds the time.Time of the last successful refresh of the
+	// default keys.
+	keysRefreshed atomic.Value
+	// staleWarned is 1 when the stale keys were logged since the last refresh,
+	// accessed atomically.
+	staleWarned int32
+
+	keysUpdatedLock sync.Mutex
+	keysUpdated     []func(fingerprints []string)
+
+	// devHMACSecret is the shared secret for HS256 tokens in dev mode.
+	devHMACSecret []byte
+
+	protocolFactory  thrift.TProtocolFactory
+	serializerPool   *thrift.TSerializerPool
+	deserializerPool *thrift.TDeserializerPool
 }
 
 var _ ecinterface.Interface = (*Impl)(nil)
@@ -83,11 +284,46 @@ func (impl *Impl) HeaderToContext(ctx context.Context, header string) (context.C
 	return SetEdgeContext(ctx, ec), nil
 }
 
+// DefaultPoolSize is the default initial buffer size of the pooled thrift
+// serializers and deserializers used by an Impl.
+const DefaultPoolSize = 1024
+
 var (
-	serializerPool   = thrift.NewTSerializerPoolSizeFactory(1024, thrift.NewTBinaryProtocolFactoryDefault())
-	deserializerPool = thrift.NewTDeserializerPoolSizeFactory(1024, thrift.NewTBinaryProtocolFactoryDefault())
+	defaultProtocolFactory  = thrift.NewTBinaryProtocolFactoryDefault()
+	defaultSerializerPool   = thrift.NewTSerializerPoolSizeFactory(DefaultPoolSize, defaultProtocolFactory)
+	defaultDeserializerPool = thrift.NewTDeserializerPoolSizeFactory(DefaultPoolSize, defaultProtocolFactory)
 )
 
+// protocol returns the thrift protocol factory used by impl.
+//
+// It's safe to be called on nil impl.
+func (impl *Impl) protocol() thrift.TProtocolFactory {
+	if impl == nil || impl.protocolFactory == nil {
+		return defaultProtocolFactory
+	}
+	return impl.protocolFactory
+}
+
+// serializers returns the thrift serializer pool used by impl.
+//
+// It's safe to be called on nil impl.
+func (impl *Impl) serializers() *thrift.TSerializerPool {
+	if impl == nil || impl.serializerPool == nil {
+		return defaultSerializerPool
+	}
+	return impl.serializerPool
+}
+
+// deserializers returns the thrift deserializer pool used by impl.
+//
+// It's safe to be called on nil impl.
+func (impl *Impl) deserializers() *thrift.TDeserializerPool {
+	if impl == nil || impl.deserializerPool == nil {
+		return defaultDeserializerPool
+	}
+	return impl.deserializerPool
+}
+
 type contextKey int
 
 const (
@@ -111,10 +347,237 @@ func GetEdgeContext(ctx context.Context) (ec *EdgeRequestContext, ok bool) {
 
 // Config for Init function.
 type Config struct {
-	// The secret store to get the keys for jwt validation
+	// The secret store to get the keys for jwt validation,
+	// it's optional in dev mode (see InsecureDevModeHMACSecret),
+	// or when the keys are loaded from JWKS, KeyDir or LocalKeys.
 	Store *secrets.Store
 	// The logger to log key decoding errors
 	Logger log.Wrapper
+	// Optional, the path of the versioned secret in Store holding the public
+	// keys for jwt tokens, e.g. for staging vaults or per-region secret trees.
+	// When it's empty, DefaultPubKeySecretPath will be used.
+	PubKeySecretPath string
+	// Optional, the paths of additional versioned secrets in Store,
+	// whose public keys are merged with the ones from PubKeySecretPath,
+	// e.g. while the public keys are migrating to a new secrets location.
+	//
+	// The keys from PubKeySecretPath come first,
+	// so its current key is still the fallback for tokens without a kid.
+	ExtraPubKeySecretPaths []string
+	// When AllowSimplePubKeySecrets is true, the secrets at PubKeySecretPath
+	// and ExtraPubKeySecretPaths that are not versioned secrets are read as
+	// simple secrets instead, for deployments publishing a single public key.
+	AllowSimplePubKeySecrets bool
+	// Optional, the path of the versioned secret in Store holding the
+	// Ed25519 public keys for PASETO tokens.
+	// When it's empty, DefaultPASETOPubKeySecretPath will be used.
+	PASETOPubKeySecretPath string
+
+	// Optional, when JWKS.URL is non-empty, the public keys for jwt tokens are
+	// fetched from the JWKS endpoint and refreshed in the background,
+	// instead of loaded from PubKeySecretPath in Store.
+	//
+	// The first fetch happens in Init.
+	// Call Impl.Close to stop the background refresh.
+	JWKS JWKSConfig
+
+	// Optional, when KeyDir.Path is non-empty, the public keys for jwt tokens
+	// are loaded from the files in the directory (e.g. a mounted Kubernetes
+	// ConfigMap) and reloaded when they change,
+	// instead of loaded from PubKeySecretPath in Store.
+	//
+	// It's ignored when JWKS is also configured.
+	// Call Impl.Close to stop watching the directory.
+	KeyDir KeyDirConfig
+
+	// Optional, for local development and CI,
+	// the public keys for jwt tokens are loaded once from local files or an
+	// environment variable in Init, instead of from PubKeySecretPath in Store,
+	// so no secrets fetcher is needed.
+	//
+	// It's ignored when JWKS or KeyDir is also configured.
+	LocalKeys LocalKeysConfig
+
+	// Optional, when MaxKeyStaleness is positive, Impl.Healthy reports
+	// ErrKeysStale when the public keys for jwt tokens were not successfully
+	// refreshed within that duration, and a warning is logged once until the
+	// next refresh, when the staleness is checked by Impl.Healthy or
+	// Impl.KeyStalenessCollector.
+	//
+	// Keys from the secrets store are refreshed every time the secrets file is
+	// updated, keys from JWKS and KeyDir on every successful refresh or
+	// re-scan, and keys from LocalKeys only once in Init,
+	// so it should be longer than the refresh interval of the source.
+	MaxKeyStaleness time.Duration
+
+	// When StrictHeaderParsing is true, FromHeader returns ErrTrailingBytes if
+	// there are leftover bytes after the thrift payload in the header,
+	// instead of silently ignoring them.
+	StrictHeaderParsing bool
+	// When LazyHeaderParsing is true, FromHeader defers decoding the header
+	// until the first time a field is read from the EdgeRequestContext.
+	//
+	// This saves the decoding cost for services that only forward the header.
+	// With lazy parsing FromHeader no longer returns decoding errors,
+	// they are logged on first access and all fields will be empty instead.
+	//
+	// It's ignored when StrictHeaderParsing is also true.
+	LazyHeaderParsing bool
+
+	// Optional, additional options for the jwt.Parser used by ValidateToken,
+	// e.g. jwt.WithLeeway to tolerate clock skew.
+	//
+	// The parser is created once in Init and reused for all the tokens.
+	// Its valid methods are restricted to JWTAlgorithms and
+	// ExtraJWTAlgorithms before applying these options,
+	// so passing jwt.WithValidMethods here overrides that.
+	JWTParserOptions []jwt.ParserOption
+
+	// Optional, the allow-list of the signing methods accepted by
+	// ValidateToken, with the public keys of the matching types in the secrets
+	// store.
+	// Tokens signed with other methods are rejected before the signature
+	// verification.
+	//
+	// When it's empty, DefaultJWTAlgorithms (RS256) will be used.
+	// Supported values are RS256/384/512 and PS256/384/512 (RSA keys),
+	// ES256/384/512 (ECDSA keys of the matching curves) and EdDSA (Ed25519
+	// keys).
+	// Unsupported values (e.g. "none" and HMAC methods) are logged and ignored.
+	JWTAlgorithms []string
+
+	// Optional, additional signing methods accepted by ValidateToken besides
+	// JWTAlgorithms, e.g. "ES256", "EdDSA" or "PS256" to roll out a new
+	// algorithm without repeating the defaults.
+	//
+	// It supports the same values as JWTAlgorithms.
+	ExtraJWTAlgorithms []string
+
+	// Optional, the only signing method accepted for each public key,
+	// keyed by the kid (see PublicKeyFingerprint, or the kid member of JWK
+	// keys),
+	// e.g. to only accept PS256 for a RSA key in a mixed RSA/ECDSA key set.
+	//
+	// ValidateToken rejects tokens signed with any other method with
+	// ErrKeyAlgorithmMismatch,
+	// and never falls back to the mapped keys for them.
+	// Keys not in the map accept all the methods in JWTAlgorithms usable by
+	// their types.
+	// It applies to the keys of Issuers as well.
+	KeyAlgorithms map[string]string
+
+	// INSECURE, ONLY FOR LOCAL DEVELOPMENT.
+	//
+	// When InsecureDevModeHMACSecret is non-empty, Impl runs in dev mode:
+	// ValidateToken also accepts HS256 tokens signed with this shared secret
+	// (see NewInsecureDevSigner), and Store becomes optional,
+	// so engineers can run services locally without provisioning RSA key pairs
+	// and the secrets fetcher.
+	//
+	// Anyone knowing the secret can mint any token,
+	// so it must never be set in production.
+	// A warning is logged by Init when it's set.
+	InsecureDevModeHMACSecret []byte
+
+	// When TokenCacheSize is positive, ValidateToken caches up to that many
+	// successfully validated tokens (in LRU order),
+	// so the same token is not verified again on every request of a session.
+	//
+	// A cached token is dropped when it expires, or when TokenCacheTTL elapsed
+	// since it was cached, whichever comes first.
+	// The whole cache is dropped when the public keys are updated.
+	TokenCacheSize int
+	// Optional, the max time a validated token stays in the cache.
+	// When it's non-positive, tokens stay in the cache until they expire.
+	TokenCacheTTL time.Duration
+
+	// Optional, the configuration for the DPoP proof validation of
+	// ValidateTokenWithDPoP,
+	// and of ValidateToken when DPoP.RequireProof is true.
+	DPoP DPoPConfig
+
+	// Optional, when TokenIntrospector is non-nil, ValidateToken uses it to
+	// validate opaque tokens (neither JWT nor PASETO),
+	// so services can accept both token styles during migration.
+	// Opaque tokens are validated even when no public keys for jwt tokens are
+	// loaded.
+	//
+	// The introspection results are cached the same way as validated JWT
+	// tokens, so TokenCacheSize (and TokenCacheTTL for tokens without
+	// expiration) should be set as well to avoid introspecting the same token
+	// on every request.
+	TokenIntrospector TokenIntrospector
+
+	// Optional, when RevocationChecker is non-nil, ValidateToken rejects tokens
+	// it reports as revoked with ErrTokenRevoked,
+	// even if they are already in the token cache.
+	RevocationChecker RevocationChecker
+
+	// Optional, the claims all the tokens must have,
+	// e.g. "sub", "exp", "roles".
+	// ValidateToken rejects tokens without any of them (or with a zero value,
+	// see AuthenticationToken.HasClaim) with ErrMissingRequiredClaim,
+	// instead of returning zero values for them.
+	RequiredClaims []string
+
+	// Optional, when TokenTypeHeader is non-empty, ValidateToken rejects JWT
+	// tokens with a different typ header (e.g. "JWT" or "at+jwt") with
+	// ErrUnexpectedTokenTypeHeader,
+	// so tokens minted for other purposes with the same keys are not accepted.
+	TokenTypeHeader string
+
+	// Optional, when Audiences is non-empty, ValidateToken rejects tokens
+	// without any of them in the aud claim with jwt.ErrTokenInvalidAudience,
+	// so tokens minted for other properties can't be replayed against us.
+	Audiences []string
+
+	// Optional, additional trusted issuers with their own key sets and
+	// algorithm policies, e.g. to validate the tokens from both the legacy and
+	// the new auth service during migration.
+	//
+	// JWT tokens with the iss claim of a configured issuer are only validated
+	// with the keys and algorithms of that issuer,
+	// all the other tokens are validated with the default keys and
+	// algorithms.
+	Issuers []IssuerConfig
+
+	// When StrictKeyID is true, ValidateToken rejects JWT tokens without the
+	// kid header with ErrMissingKeyID,
+	// and tokens with a kid not matching any of the loaded public keys with
+	// ErrUnknownKeyID,
+	// instead of falling back to the first (usually current) key,
+	// which could mask key distribution problems.
+	//
+	// The fallbacks in non-strict mode are counted in the
+	// edgecontext_jwt_kid_fallbacks_total prometheus counter,
+	// which should be zero before turning on strict mode.
+	StrictKeyID bool
+
+	// When HeaderCacheSize is positive, New memoizes the serialized headers of
+	// up to that many different NewArgs,
+	// so the thrift serialization is skipped for repeated identical NewArgs
+	// (e.g. anonymous traffic on the edge).
+	//
+	// It's only useful when a lot of the NewArgs passed to New are identical,
+	// which usually means that they don't have RequestID set.
+	HeaderCacheSize int
+
+	// Optional, the initial buffer sizes of the pooled thrift serializers
+	// (used by New) and deserializers (used by FromHeader).
+	// When they are non-positive, DefaultPoolSize will be used.
+	//
+	// Deployments with very large headers can raise them to avoid buffer
+	// growth, small deployments can lower them to save memory.
+	SerializerPoolSize   int
+	DeserializerPoolSize int
+	// Optional, the thrift protocol used to serialize and deserialize headers.
+	// When it's nil, the binary protocol with default configuration will be used.
+	//
+	// Note that all the services exchanging edge context headers must use the
+	// same protocol, and RequestIDFromHeader and LoIDFromHeader only support
+	// the binary protocol.
+	// Only change it when you know what you are doing.
+	ProtocolFactory thrift.TProtocolFactory
 }
 
 // Factory returns an ecinterface.Factory implementation by wrapping Init.
@@ -134,12 +597,77 @@ func Init(cfg Config) *Impl {
 	impl := &Impl{
 		store:  cfg.Store,
 		logger: cfg.Logger,
+		strict: cfg.StrictHeaderParsing,
+		lazy:   cfg.LazyHeaderParsing,
+		parser: newJWTParser(jwtAlgs(context.Background(), cfg), cfg.JWTParserOptions),
+		dpop:   cfg.DPoP,
+
+		devHMACSecret: cfg.InsecureDevModeHMACSecret,
+
+		introspector:           cfg.TokenIntrospector,
+		revocationChecker:      cfg.RevocationChecker,
+		requiredClaims:         cfg.RequiredClaims,
+		tokenType:              cfg.TokenTypeHeader,
+		audiences:              cfg.Audiences,
+		issuers:                newIssuers(context.Background(), cfg),
+		strictKeyID:            cfg.StrictKeyID,
+		maxKeyStaleness:        cfg.MaxKeyStaleness,
+		keyAlgs:                keyAlgs(context.Background(), cfg),
+		pubKeySecretPaths:      pubKeySecretPaths(cfg),
+		pasetoPubKeySecretPath: cfg.PASETOPubKeySecretPath,
+		simplePubKeys:          cfg.AllowSimplePubKeySecrets,
+		externalKeys:           cfg.JWKS.URL != "" || cfg.KeyDir.Path != "" || cfg.LocalKeys.configured(),
+	}
+	if cfg.TokenCacheSize > 0 {
+		impl.tokenCache = newTokenCache(cfg.TokenCacheSize, cfg.TokenCacheTTL)
+	}
+	if cfg.HeaderCacheSize > 0 {
+		impl.headerCache = newHeaderCache(cfg.HeaderCacheSize)
+	}
+	impl.protocolFactory = cfg.ProtocolFactory
+	if impl.protocolFactory == nil {
+		impl.protocolFactory = defaultProtocolFactory
+	}
+	serializerPoolSize := cfg.SerializerPoolSize
+	if serializerPoolSize <= 0 {
+		serializerPoolSize = DefaultPoolSize
+	}
+	deserializerPoolSize := cfg.DeserializerPoolSize
+	if deserializerPoolSize <= 0 {
+		deserializerPoolSize = DefaultPoolSize
+	}
+	impl.serializerPool = thrift.NewTSerializerPoolSizeFactory(serializerPoolSize, impl.protocolFactory)
+	impl.deserializerPool = thrift.NewTDeserializerPoolSizeFactory(deserializerPoolSize, impl.protocolFactory)
+	if len(impl.devHMACSecret) > 0 {
+		impl.logger.Log(context.Background(), "WARNING: edgecontext is running in INSECURE dev mode, HS256 tokens signed with the shared secret are accepted. NEVER use it in production.")
+	}
+	if impl.store != nil {
+		impl.store.AddMiddlewares(impl.validatorMiddleware)
+	}
+	switch {
+	case cfg.JWKS.URL != "":
+		impl.startJWKSRefresh(cfg.JWKS)
+	case cfg.KeyDir.Path != "":
+		impl.startKeyDirWatcher(cfg.KeyDir)
+	case cfg.LocalKeys.configured():
+		impl.loadLocalKeys(cfg.LocalKeys)
 	}
-	impl.store.AddMiddlewares(impl.validatorMiddleware)
 	ecinterface.Set(impl)
 	return impl
 }
 
+// Close stops the background loading of the keys from Config.JWKS or
+// Config.KeyDir.
+//
+// It's a no-op when neither of them is configured.
+func (impl *Impl) Close() error {
+	for _, stop := range impl.closers {
+		stop()
+	}
+	impl.closers = nil
+	return nil
+}
+
 // NewArgs are the args for New function.
 //
 // All fields are optional.
@@ -148,19 +676,184 @@ type NewArgs struct {
 	LoID          string
 	LoIDCreatedAt time.Time
 
-	SessionID string
+	SessionID        string
+	SessionCreatedAt time.Time
+
+	// SessionElevatedUntil should be set when the session recently completed
+	// step-up authentication, to the time the elevation expires.
+	SessionElevatedUntil time.Time
+
+	// SessionMFA should be set when the session was authenticated with
+	// multi-factor authentication.
+	SessionMFA bool
+
+	// If AuthMethod is non-empty, it must be one of the AuthMethod constants.
+	AuthMethod AuthMethod
+
+	// If SessionType is non-empty, it must be one of the SessionType constants.
+	SessionType SessionType
 
 	DeviceID string
 
+	// If non-empty, DeviceModel, DeviceOSName and DeviceOSVersion must be valid
+	// UTF-8 of at most MaxDeviceFieldLength bytes.
+	DeviceModel     string
+	DeviceOSName    string
+	DeviceOSVersion string
+
+	// If FormFactor is non-empty, it must be one of the FormFactor constants.
+	FormFactor FormFactor
+
+	// If AttestationVerdict is non-empty, it must be one of the
+	// AttestationVerdict constants.
+	AttestationVerdict AttestationVerdict
+
 	AuthToken string
 
-	OriginServiceName string
+	// ServiceAuthToken is the auth token of the service initiating the request
+	// on behalf of the user in AuthToken, if any.
+	ServiceAuthToken string
+
+	OriginServiceName     string
+	OriginServiceVersion  string
+	OriginServiceDeployID string
+
+	// EdgePOP is the edge POP or region that terminated the client connection.
+	EdgePOP string
 
 	CountryCode string
 
+	// If SubdivisionCode is non-empty, it must be an ISO 3166-2 subdivision
+	// code (see SubdivisionCodeRegex) of the country in CountryCode, e.g. US-CA.
+	SubdivisionCode string
+
+	// If City is non-empty, it must be valid UTF-8 of at most MaxCityLength
+	// bytes.
+	City string
+
+	// If MetroCode is non-zero, it must be a 3-digit Nielsen DMA code.
+	MetroCode int32
+
+	// If GeoProvenance is non-empty, it must be one of the GeoProvenance
+	// constants.
+	GeoProvenance GeoProvenance
+
 	RequestID string
 
+	// If TraceID is non-empty, it must be a W3C Trace Context trace id:
+	// 32 lowercase hex characters, not all zeros.
+	TraceID string
+
 	LocaleCode string
+
+	// If ClientIP is non-empty, it must be a valid IPv4 or IPv6 address.
+	ClientIP string
+
+	UserAgent string
+
+	// If ClientVersion.Version is non-empty,
+	// it must be a semantic version (see ClientVersionRegex).
+	ClientVersion ClientVersion
+
+	// If Platform is non-empty, it must be one of the Platform constants.
+	Platform Platform
+
+	// InternalNetwork should be set when the request originates from the
+	// corporate network (VPN) or an employee device.
+	InternalNetwork bool
+
+	// LimitAdTracking should be set when the client asked to limit ad tracking.
+	LimitAdTracking bool
+
+	// AnonymizingProxy should be set when the edge determined the client IP
+	// belongs to a VPN or an anonymizing proxy.
+	AnonymizingProxy bool
+
+	// ClientASN is the autonomous system number of the client IP, 0 if unknown.
+	ClientASN uint32
+
+	// If Timezone is non-empty, it must be formatted like an IANA time zone
+	// database name (see TimezoneRegex), e.g. America/New_York.
+	Timezone string
+
+	// AcceptedLocales is the full list of locale codes accepted by the client,
+	// in order of preference, complementing LocaleCode.
+	// All of them must match the same format as LocaleCode.
+	AcceptedLocales []string
+
+	// FeatureFlagOverrides are the per-request feature flag overrides, keyed by
+	// the feature flag name.
+	//
+	// The edge should only set them for internal and debug clients.
+	// It can have at most MaxFeatureFlagOverrides entries,
+	// with keys of at most MaxFeatureFlagNameLength bytes and values of at most
+	// MaxFeatureFlagValueLength bytes.
+	FeatureFlagOverrides map[string]string
+
+	// Consent is the privacy consents given by the user.
+	// Leave it nil when the consents are unknown.
+	Consent *Consent
+
+	// If ComplianceRegion is non-empty, it must be one of the ComplianceRegion
+	// constants.
+	ComplianceRegion ComplianceRegion
+
+	// WorkloadIdentity should only be set for requests initiated by a service
+	// instead of a user.
+	// If it's non-empty, it must be a SPIFFE ID (see WorkloadIdentityRegex) of
+	// at most MaxWorkloadIdentityLength bytes.
+	WorkloadIdentity string
+
+	// TenantID should only be set in deployments that serve multiple tenants.
+	// If it's non-empty, it must match TenantIDRegex and be at most
+	// MaxTenantIDLength bytes.
+	TenantID string
+
+	// If ReferringSurface is non-empty, it must be one of the ReferringSurface
+	// constants.
+	ReferringSurface ReferringSurface
+
+	// Deadline is the absolute client-facing deadline by which the response
+	// must be sent back to the client.
+	Deadline time.Time
+
+	// Shadow should be set when the request is replayed or shadowed (dark
+	// launches, traffic mirroring).
+	Shadow bool
+
+	// Synthetic should be set when the request is generated by a synthetic probe
+	// or a load test.
+	Synthetic bool
+
+	// Canary should be set when the request belongs to the canary (early
+	// rollout) cohort.
+	Canary bool
+
+	// TeenRestricted is true when the account is subject to teen-safety or
+	// parental-control restrictions.
+	TeenRestricted bool
+
+	// EmailVerified is true when the email address of the account is verified.
+	EmailVerified bool
+
+	// Premium is true when the account has an active premium subscription.
+	Premium bool
+
+	// Baggage is generic low-cardinality metadata, for values that don't warrant
+	// their own field.
+	//
+	// It can have at most MaxBaggageEntries entries.
+	// The keys must match BaggageKeyRegex and be at most MaxBaggageKeyLength
+	// bytes, the values must be at most MaxBaggageValueLength bytes.
+	Baggage map[string]string
+
+	// Hops is the names of the services that forwarded the edge context,
+	// with the most recent hops last.
+	// Use EdgeRequestContext.WithHop to append to it.
+	//
+	// It can have at most MaxHops entries.
+	// The service names must match HopRegex and be at most MaxHopLength bytes.
+	Hops []string
 }
 
 // New creates a new EdgeRequestContext from scratch.
@@ -168,105 +861,497 @@ type NewArgs struct {
 // This function should be used by services on the edge talking to clients
 // directly, after talked to authentication service to get the auth token.
 func New(ctx context.Context, impl *Impl, args NewArgs) (*EdgeRequestContext, error) {
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
+	header, err := impl.serialize(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return &EdgeRequestContext{
+		impl:   impl,
+		header: header,
+		// Don't share the maps and slices with the caller.
+		raw: args.clone(),
+		ctx: ctx,
+	}, nil
+}
+
+// serialize serializes validated args into a header,
+// using the header cache if it's enabled.
+//
+// It's safe to be called on nil impl.
+func (impl *Impl) serialize(ctx context.Context, args NewArgs) (string, error) {
+	var cache *headerCache
+	if impl != nil {
+		cache = impl.headerCache
+	}
+	if cache != nil {
+		if header, ok := cache.get(args); ok {
+			return header, nil
+		}
+	}
+	header, err := impl.serializers().WriteString(ctx, args.toRequest())
+	if err != nil {
+		return "", err
+	}
+	if cache != nil {
+		cache.add(args, header)
+	}
+	return header, nil
+}
+
+// validate checks the args for New.
+func (args NewArgs) validate() error {
+	if args.LoID != "" && !strings.HasPrefix(args.LoID, userPrefix) {
+		return ErrLoIDWrongPrefix
+	}
+	if args.LocaleCode != "" && !LocaleRegex.MatchString(args.LocaleCode) {
+		return ErrInvalidLocaleCode
+	}
+	if args.TraceID != "" && (!TraceIDRegex.MatchString(args.TraceID) || args.TraceID == invalidTraceID) {
+		return ErrInvalidTraceID
+	}
+	if args.ClientIP != "" && net.ParseIP(args.ClientIP) == nil {
+		return ErrInvalidClientIP
+	}
+	if err := args.ClientVersion.validate(); err != nil {
+		return err
+	}
+	if args.Platform != "" && !args.Platform.IsKnown() {
+		return ErrInvalidPlatform
+	}
+	if args.SubdivisionCode != "" {
+		if !SubdivisionCodeRegex.MatchString(args.SubdivisionCode) {
+			return ErrInvalidSubdivisionCode
+		}
+		if args.CountryCode != "" && !strings.HasPrefix(args.SubdivisionCode, args.CountryCode+"-") {
+			return ErrInvalidSubdivisionCode
+		}
+	}
+	if len(args.City) > MaxCityLength || !utf8.ValidString(args.City) {
+		return ErrInvalidCity
+	}
+	for _, s := range [...]string{args.DeviceModel, args.DeviceOSName, args.DeviceOSVersion} {
+		if len(s) > MaxDeviceFieldLength || !utf8.ValidString(s) {
+			return ErrInvalidDevice
+		}
+	}
+	if args.FormFactor != "" && !args.FormFactor.IsKnown() {
+		return ErrInvalidFormFactor
+	}
+	if args.AttestationVerdict != "" && !args.AttestationVerdict.IsKnown() {
+		return ErrInvalidAttestationVerdict
+	}
+	if args.SessionType != "" && !args.SessionType.IsKnown() {
+		return ErrInvalidSessionType
+	}
+	if args.AuthMethod != "" && !args.AuthMethod.IsKnown() {
+		return ErrInvalidAuthMethod
+	}
+	if args.MetroCode != 0 && (args.MetroCode < 100 || args.MetroCode > 999) {
+		return ErrInvalidMetroCode
+	}
+	if args.GeoProvenance != "" && !args.GeoProvenance.IsKnown() {
+		return ErrInvalidGeoProvenance
+	}
+	if args.Timezone != "" && !TimezoneRegex.MatchString(args.Timezone) {
+		return ErrInvalidTimezone
+	}
+	for _, locale := range args.AcceptedLocales {
+		if !LocaleRegex.MatchString(locale) {
+			return ErrInvalidLocaleCode
+		}
+	}
+	if args.ComplianceRegion != "" && !args.ComplianceRegion.IsKnown() {
+		return ErrInvalidComplianceRegion
+	}
+	if args.ReferringSurface != "" && !args.ReferringSurface.IsKnown() {
+		return ErrInvalidReferringSurface
+	}
+	if args.TenantID != "" && (len(args.TenantID) > MaxTenantIDLength || !TenantIDRegex.MatchString(args.TenantID)) {
+		return ErrInvalidTenantID
+	}
+	if args.WorkloadIdentity != "" && (len(args.WorkloadIdentity) > MaxWorkloadIdentityLength || !WorkloadIdentityRegex.MatchString(args.WorkloadIdentity)) {
+		return ErrInvalidWorkloadIdentity
+	}
+	if len(args.Baggage) > MaxBaggageEntries {
+		return ErrInvalidBaggage
+	}
+	for key, value := range args.Baggage {
+		if len(key) > MaxBaggageKeyLength || len(value) > MaxBaggageValueLength || !BaggageKeyRegex.MatchString(key) {
+			return ErrInvalidBaggage
+		}
+	}
+	if len(args.Hops) > MaxHops {
+		return ErrInvalidHops
+	}
+	for _, hop := range args.Hops {
+		if len(hop) > MaxHopLength || !HopRegex.MatchString(hop) {
+			return ErrInvalidHops
+		}
+	}
+	if len(args.FeatureFlagOverrides) > MaxFeatureFlagOverrides {
+		return ErrInvalidFeatureFlagOverrides
+	}
+	for name, value := range args.FeatureFlagOverrides {
+		if name == "" || len(name) > MaxFeatureFlagNameLength || len(value) > MaxFeatureFlagValueLength {
+			return ErrInvalidFeatureFlagOverrides
+		}
+	}
+	return nil
+}
+
+// toRequest converts validated args into the thrift request to be serialized.
+func (args NewArgs) toRequest() *ecthrift.Request {
 	request := ecthrift.NewRequest()
 	if args.LoID != "" {
-		if !strings.HasPrefix(args.LoID, userPrefix) {
-			return nil, ErrLoIDWrongPrefix
-		}
 		request.Loid = &ecthrift.Loid{
 			ID:        args.LoID,
 			CreatedMs: timebp.TimeToMilliseconds(args.LoIDCreatedAt),
 		}
 	}
-	if args.SessionID != "" {
+	if args.SessionID != "" || !args.SessionCreatedAt.IsZero() || args.SessionType != "" || !args.SessionElevatedUntil.IsZero() || args.SessionMFA || args.AuthMethod != "" {
 		request.Session = &ecthrift.Session{
 			ID: args.SessionID,
 		}
+		if !args.SessionCreatedAt.IsZero() {
+			request.Session.CreatedMs = thrift.Int64Ptr(timebp.TimeToMilliseconds(args.SessionCreatedAt))
+		}
+		if args.SessionType != "" {
+			request.Session.Type = thrift.StringPtr(string(args.SessionType))
+		}
+		if !args.SessionElevatedUntil.IsZero() {
+			request.Session.ElevatedUntilMs = thrift.Int64Ptr(timebp.TimeToMilliseconds(args.SessionElevatedUntil))
+		}
+		if args.SessionMFA {
+			request.Session.Mfa = thrift.BoolPtr(true)
+		}
+		if args.AuthMethod != "" {
+			request.Session.AuthMethod = thrift.StringPtr(string(args.AuthMethod))
+		}
 	}
-	if args.DeviceID != "" {
+	if args.DeviceID != "" || args.DeviceModel != "" || args.DeviceOSName != "" || args.DeviceOSVersion != "" || args.FormFactor != "" || args.AttestationVerdict != "" {
 		request.Device = &ecthrift.Device{
 			ID: args.DeviceID,
 		}
+		if args.DeviceModel != "" {
+			request.Device.Model = thrift.StringPtr(args.DeviceModel)
+		}
+		if args.DeviceOSName != "" {
+			request.Device.OsName = thrift.StringPtr(args.DeviceOSName)
+		}
+		if args.DeviceOSVersion != "" {
+			request.Device.OsVersion = thrift.StringPtr(args.DeviceOSVersion)
+		}
+		if args.FormFactor != "" {
+			request.Device.FormFactor = thrift.StringPtr(string(args.FormFactor))
+		}
+		if args.AttestationVerdict != "" {
+			request.Device.AttestationVerdict = thrift.StringPtr(string(args.AttestationVerdict))
+		}
 	}
-	if args.OriginServiceName != "" {
+	if args.OriginServiceName != "" || args.OriginServiceVersion != "" || args.OriginServiceDeployID != "" || args.EdgePOP != "" {
 		request.OriginService = &ecthrift.OriginService{
 			Name: args.OriginServiceName,
 		}
+		if args.OriginServiceVersion != "" {
+			request.OriginService.Version = thrift.StringPtr(args.OriginServiceVersion)
+		}
+		if args.OriginServiceDeployID != "" {
+			request.OriginService.DeployID = thrift.StringPtr(args.OriginServiceDeployID)
+		}
+		if args.EdgePOP != "" {
+			request.OriginService.EdgePop = thrift.StringPtr(args.EdgePOP)
+		}
 	}
-	if args.CountryCode != "" {
+	if args.CountryCode != "" || args.SubdivisionCode != "" || args.City != "" || args.MetroCode != 0 || args.GeoProvenance != "" {
 		request.Geolocation = &ecthrift.Geolocation{
 			CountryCode: ecthrift.CountryCode(args.CountryCode),
 		}
+		if args.SubdivisionCode != "" {
+			request.Geolocation.SubdivisionCode = thrift.StringPtr(args.SubdivisionCode)
+		}
+		if args.City != "" {
+			request.Geolocation.City = thrift.StringPtr(args.City)
+		}
+		if args.MetroCode != 0 {
+			request.Geolocation.MetroCode = thrift.Int32Ptr(args.MetroCode)
+		}
+		if args.GeoProvenance != "" {
+			request.Geolocation.Provenance = thrift.StringPtr(string(args.GeoProvenance))
+		}
 	}
-	if args.RequestID != "" {
+	if args.RequestID != "" || args.TraceID != "" {
 		request.RequestID = &ecthrift.RequestId{
 			ReadableID: args.RequestID,
 		}
-	}
-	if args.LocaleCode != "" {
-		if !LocaleRegex.MatchString(args.LocaleCode) {
-			return nil, ErrInvalidLocaleCode
+		if args.TraceID != "" {
+			request.RequestID.TraceID = thrift.StringPtr(args.TraceID)
 		}
+	}
+	if args.LocaleCode != "" || args.Timezone != "" || len(args.AcceptedLocales) > 0 {
 		request.Locale = &ecthrift.Locale{
 			LocaleCode: ecthrift.LocaleCode(args.LocaleCode),
 		}
+		if args.Timezone != "" {
+			request.Locale.Timezone = thrift.StringPtr(args.Timezone)
+		}
+		if len(args.AcceptedLocales) > 0 {
+			request.Locale.AcceptedLocaleCodes = args.AcceptedLocales
+		}
+	}
+	if args.ClientIP != "" || args.UserAgent != "" || !args.ClientVersion.IsZero() || args.Platform != "" || args.InternalNetwork || args.LimitAdTracking || args.AnonymizingProxy || args.ClientASN != 0 {
+		request.Client = &ecthrift.Client{
+			IP:               args.ClientIP,
+			UserAgent:        args.UserAgent,
+			AppVersion:       args.ClientVersion.Version,
+			BuildNumber:      args.ClientVersion.Build,
+			Platform:         string(args.Platform),
+			InternalNetwork:  args.InternalNetwork,
+			LimitAdTracking:  args.LimitAdTracking,
+			AnonymizingProxy: args.AnonymizingProxy,
+			Asn:              int64(args.ClientASN),
+		}
+	}
+
+	if len(args.FeatureFlagOverrides) > 0 {
+		request.FeatureFlagOverrides = copyStringMap(args.FeatureFlagOverrides)
+	}
+	if len(args.Baggage) > 0 {
+		request.Baggage = args.Baggage
+	}
+	if len(args.Hops) > 0 {
+		request.Hops = args.Hops
+	}
+	if args.TeenRestricted || args.EmailVerified || args.Premium {
+		request.Account = &ecthrift.Account{
+			TeenRestricted: args.TeenRestricted,
+			EmailVerified:  args.EmailVerified,
+			Premium:        args.Premium,
+		}
+	}
+	if args.ComplianceRegion != "" {
+		request.ComplianceRegion = thrift.StringPtr(string(args.ComplianceRegion))
+	}
+	if args.WorkloadIdentity != "" {
+		request.WorkloadIdentity = thrift.StringPtr(args.WorkloadIdentity)
+	}
+	if args.TenantID != "" {
+		request.TenantID = thrift.StringPtr(args.TenantID)
+	}
+	if args.ReferringSurface != "" {
+		request.ReferringSurface = thrift.StringPtr(string(args.ReferringSurface))
+	}
+	if !args.Deadline.IsZero() {
+		request.DeadlineMs = thrift.Int64Ptr(timebp.TimeToMilliseconds(args.Deadline))
+	}
+	if args.Shadow || args.Synthetic || args.Canary {
+		request.Traffic = &ecthrift.Traffic{
+			Shadow:    args.Shadow,
+			Synthetic: args.Synthetic,
+			Canary:    args.Canary,
+		}
+	}
+	if args.Consent != nil {
+		request.Consent = &ecthrift.Consent{
+			AdsPersonalization: args.Consent.AdsPersonalization,
+			Analytics:          args.Consent.Analytics,
+			ThirdPartySharing:  args.Consent.ThirdPartySharing,
+		}
 	}
 
 	request.AuthenticationToken = ecthrift.AuthenticationToken(args.AuthToken)
+	if args.ServiceAuthToken != "" {
+		token := ecthrift.AuthenticationToken(args.ServiceAuthToken)
+		request.ServiceAuthenticationToken = &token
+	}
+	return request
+}
 
-	header, err := serializerPool.WriteString(ctx, request)
+// FromHeader returns a new EdgeRequestContext from the given header string
+// using the given Impl.
+//
+// If the Impl was initialized with StrictHeaderParsing,
+// FromHeader returns ErrTrailingBytes when the header contains extra bytes
+// after the thrift payload.
+//
+// If the Impl was initialized with LazyHeaderParsing (and without
+// StrictHeaderParsing), the header is not decoded until the first time any
+// field is accessed on the returned EdgeRequestContext.
+func FromHeader(ctx context.Context, header string, impl *Impl) (*EdgeRequestContext, error) {
+	if header == "" {
+		return nil, nil
+	}
+
+	if impl != nil && impl.lazy && !impl.strict {
+		return &EdgeRequestContext{
+			impl:   impl,
+			header: header,
+			lazy:   true,
+			ctx:    ctx,
+		}, nil
+	}
+
+	raw, err := impl.parseHeader(ctx, header, impl != nil && impl.strict)
 	if err != nil {
 		return nil, err
 	}
 	return &EdgeRequestContext{
 		impl:   impl,
 		header: header,
-		raw:    args,
+		raw:    raw,
 		ctx:    ctx,
 	}, nil
 }
 
-// FromHeader returns a new EdgeRequestContext from the given header string
-// using the given Impl.
-func FromHeader(ctx context.Context, header string, impl *Impl) (*EdgeRequestContext, error) {
-	if header == "" {
-		return nil, nil
-	}
+// requestPool pools the *ecthrift.Request objects used by parseHeader.
+//
+// The decoded values are always copied into NewArgs,
+// so the request can be reused right after parseHeader returns.
+var requestPool = sync.Pool{
+	New: func() interface{} {
+		return ecthrift.NewRequest()
+	},
+}
 
-	request := ecthrift.NewRequest()
-	if err := deserializerPool.ReadString(ctx, request, header); err != nil {
-		return nil, err
+// parseHeader decodes the thrift payload in header into NewArgs.
+//
+// It's safe to be called on nil impl.
+func (impl *Impl) parseHeader(ctx context.Context, header string, strict bool) (NewArgs, error) {
+	request := requestPool.Get().(*ecthrift.Request)
+	defer func() {
+		// Drop the references to nested structs before returning it to the pool.
+		*request = ecthrift.Request{}
+		requestPool.Put(request)
+	}()
+
+	if strict {
+		if err := readStrict(ctx, impl.protocol(), request, header); err != nil {
+			return NewArgs{}, err
+		}
+	} else {
+		if err := impl.deserializers().ReadString(ctx, request, header); err != nil {
+			return NewArgs{}, err
+		}
 	}
+	return argsFromRequest(request), nil
+}
 
+// argsFromRequest converts a decoded thrift request into NewArgs.
+//
+// Only the enumerated low cardinality fields (session and device types,
+// country and locale codes, origin service names, etc.) are interned to reduce
+// the steady-state heap usage of long-running services.
+// High cardinality or client controlled fields (device models, cities,
+// baggage, hops, etc.) are kept as plain strings,
+// so they can't fill up the interning table.
+func argsFromRequest(request *ecthrift.Request) NewArgs {
 	raw := NewArgs{
-		AuthToken: string(request.AuthenticationToken),
+		AuthToken:        string(request.AuthenticationToken),
+		ServiceAuthToken: string(request.GetServiceAuthenticationToken()),
 	}
 	if request.Session != nil {
 		raw.SessionID = request.Session.ID
+		raw.SessionCreatedAt = timebp.MillisecondsToTime(request.Session.GetCreatedMs())
+		raw.SessionType = SessionType(intern(request.Session.GetType()))
+		raw.SessionElevatedUntil = timebp.MillisecondsToTime(request.Session.GetElevatedUntilMs())
+		raw.SessionMFA = request.Session.GetMfa()
+		raw.AuthMethod = AuthMethod(intern(request.Session.GetAuthMethod()))
 	}
 	if request.Device != nil {
 		raw.DeviceID = request.Device.ID
+		raw.DeviceModel = request.Device.GetModel()
+		raw.DeviceOSName = intern(request.Device.GetOsName())
+		raw.DeviceOSVersion = request.Device.GetOsVersion()
+		raw.FormFactor = FormFactor(intern(request.Device.GetFormFactor()))
+		raw.AttestationVerdict = AttestationVerdict(intern(request.Device.GetAttestationVerdict()))
 	}
 	if request.Loid != nil {
 		raw.LoID = request.Loid.ID
 		raw.LoIDCreatedAt = timebp.MillisecondsToTime(request.Loid.CreatedMs)
 	}
 	if request.OriginService != nil {
-		raw.OriginServiceName = request.OriginService.Name
+		raw.OriginServiceName = intern(request.OriginService.Name)
+		raw.OriginServiceVersion = request.OriginService.GetVersion()
+		raw.OriginServiceDeployID = request.OriginService.GetDeployID()
+		raw.EdgePOP = intern(request.OriginService.GetEdgePop())
 	}
 	if request.Geolocation != nil {
-		raw.CountryCode = string(request.Geolocation.CountryCode)
+		raw.CountryCode = intern(string(request.Geolocation.CountryCode))
+		raw.SubdivisionCode = request.Geolocation.GetSubdivisionCode()
+		raw.City = request.Geolocation.GetCity()
+		raw.MetroCode = request.Geolocation.GetMetroCode()
+		raw.GeoProvenance = GeoProvenance(intern(request.Geolocation.GetProvenance()))
 	}
 	if request.RequestID != nil {
 		raw.RequestID = request.RequestID.ReadableID
+		raw.TraceID = request.RequestID.GetTraceID()
 	}
 	if request.Locale != nil {
-		raw.LocaleCode = string(request.Locale.LocaleCode)
+		raw.LocaleCode = intern(string(request.Locale.LocaleCode))
+		raw.Timezone = intern(request.Locale.GetTimezone())
+		if locales := request.Locale.GetAcceptedLocaleCodes(); len(locales) > 0 {
+			raw.AcceptedLocales = append([]string(nil), locales...)
+		}
 	}
-	return &EdgeRequestContext{
-		impl:   impl,
-		header: header,
-		raw:    raw,
-		ctx:    ctx,
-	}, nil
+	if len(request.FeatureFlagOverrides) > 0 {
+		raw.FeatureFlagOverrides = copyStringMap(request.FeatureFlagOverrides)
+	}
+	raw.ComplianceRegion = ComplianceRegion(intern(request.GetComplianceRegion()))
+	raw.WorkloadIdentity = request.GetWorkloadIdentity()
+	raw.TenantID = request.GetTenantID()
+	raw.ReferringSurface = ReferringSurface(intern(request.GetReferringSurface()))
+	raw.Deadline = timebp.MillisecondsToTime(request.GetDeadlineMs())
+	if request.Traffic != nil {
+		raw.Shadow = request.Traffic.Shadow
+		raw.Synthetic = request.Traffic.Synthetic
+		raw.Canary = request.Traffic.Canary
+	}
+	if len(request.Baggage) > 0 {
+		raw.Baggage = copyStringMap(request.Baggage)
+	}
+	if len(request.Hops) > 0 {
+		raw.Hops = append([]string(nil), request.Hops...)
+	}
+	if request.Account != nil {
+		raw.TeenRestricted = request.Account.TeenRestricted
+		raw.EmailVerified = request.Account.EmailVerified
+		raw.Premium = request.Account.Premium
+	}
+	if request.Consent != nil {
+		raw.Consent = &Consent{
+			AdsPersonalization: request.Consent.AdsPersonalization,
+			Analytics:          request.Consent.Analytics,
+			ThirdPartySharing:  request.Consent.ThirdPartySharing,
+		}
+	}
+	if request.Client != nil {
+		raw.ClientIP = request.Client.IP
+		raw.UserAgent = request.Client.UserAgent
+		raw.ClientVersion = ClientVersion{
+			Version: request.Client.AppVersion,
+			Build:   request.Client.BuildNumber,
+		}
+		raw.Platform = Platform(intern(request.Client.Platform))
+		raw.InternalNetwork = request.Client.InternalNetwork
+		raw.LimitAdTracking = request.Client.LimitAdTracking
+		raw.AnonymizingProxy = request.Client.AnonymizingProxy
+		raw.ClientASN = uint32(request.Client.Asn)
+	}
+	return raw
+}
+
+// readStrict deserializes header into request,
+// and returns ErrTrailingBytes if not all bytes in header were consumed.
+func readStrict(ctx context.Context, protocolFactory thrift.TProtocolFactory, request *ecthrift.Request, header string) error {
+	buf := thrift.NewTMemoryBufferLen(len(header))
+	buf.WriteString(header)
+	if err := request.Read(ctx, protocolFactory.GetProtocol(buf)); err != nil {
+		return err
+	}
+	if n := buf.Len(); n > 0 {
+		return fmt.Errorf("%w: %d byte(s)", ErrTrailingBytes, n)
+	}
+	return nil
 }
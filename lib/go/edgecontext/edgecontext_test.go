@@ -3,13 +3,18 @@ package edgecontext_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/reddit/baseplate.go/detach"
+	"github.com/reddit/baseplate.go/errorsbp"
 	"github.com/reddit/baseplate.go/experiments"
+	"github.com/reddit/baseplate.go/log"
 	"github.com/reddit/baseplate.go/timebp"
 
 	"github.com/reddit/edgecontext/lib/go/edgecontext"
@@ -818,3 +823,1733 @@ func TestDetachIntegration(t *testing.T) {
 		})
 	})
 }
+
+func TestFromHeaderStrict(t *testing.T) {
+	strictImpl := newTestImpl(t, edgecontext.Config{
+		StrictHeaderParsing: true,
+	})
+
+	const header = headerWithNoAuth + "\x0c\x00\x01"
+
+	t.Run("lenient", func(t *testing.T) {
+		e, err := edgecontext.FromHeader(context.Background(), header, globalTestImpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.SessionID() != expectedSessionID {
+			t.Errorf("Expected session id %q, got %q", expectedSessionID, e.SessionID())
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := edgecontext.FromHeader(context.Background(), header, strictImpl)
+		if !errors.Is(err, edgecontext.ErrTrailingBytes) {
+			t.Errorf("Expected edgecontext.ErrTrailingBytes, got %v", err)
+		}
+	})
+
+	t.Run("strict-valid", func(t *testing.T) {
+		e, err := edgecontext.FromHeader(context.Background(), headerWithValidAuth, strictImpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.RequestID() != expectedRequestID {
+			t.Errorf("Expected request id %q, got %q", expectedRequestID, e.RequestID())
+		}
+	})
+}
+
+func TestFromHeaderLazy(t *testing.T) {
+	lazyImpl := newTestImpl(t, edgecontext.Config{
+		LazyHeaderParsing: true,
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		e, err := edgecontext.FromHeader(context.Background(), headerWithValidAuth, lazyImpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Header() != headerWithValidAuth {
+			t.Errorf("Header expected %q, got %q", headerWithValidAuth, e.Header())
+		}
+		if e.SessionID() != expectedSessionID {
+			t.Errorf("Expected session id %q, got %q", expectedSessionID, e.SessionID())
+		}
+		if e.OriginService().Name() != expectedOrigin {
+			t.Errorf("Expected origin service %q, got %q", expectedOrigin, e.OriginService().Name())
+		}
+		if loid, _ := e.User().LoID(); loid != "t2_example" {
+			t.Errorf("LoID expected %q, got %q", "t2_example", loid)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		const header = "\x0c\x00\x01\x0b"
+		e, err := edgecontext.FromHeader(context.Background(), header, newTestImpl(t, edgecontext.Config{
+			Logger:            log.NopWrapper,
+			LazyHeaderParsing: true,
+		}))
+		if err != nil {
+			t.Fatalf("Expected lazy parsing to defer errors, got %v", err)
+		}
+		if e.Header() != header {
+			t.Errorf("Header expected %q, got %q", header, e.Header())
+		}
+		if e.SessionID() != "" {
+			t.Errorf("Expected empty session id, got %q", e.SessionID())
+		}
+	})
+}
+
+func TestDerive(t *testing.T) {
+	e, err := edgecontext.FromHeader(context.Background(), headerWithValidAuth, globalTestImpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		const locale = "es_MX"
+		derived, err := e.Derive(func(args *edgecontext.NewArgs) {
+			args.LocaleCode = locale
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if derived.LocaleCode() != locale {
+			t.Errorf("Expected derived locale code %q, got %q", locale, derived.LocaleCode())
+		}
+		if e.LocaleCode() != expectedLocaleCode {
+			t.Errorf("Expected original locale code %q, got %q", expectedLocaleCode, e.LocaleCode())
+		}
+		if e.Header() != headerWithValidAuth {
+			t.Errorf("Expected original header to be unchanged, got %q", e.Header())
+		}
+
+		parsed, err := edgecontext.FromHeader(context.Background(), derived.Header(), globalTestImpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.LocaleCode() != locale {
+			t.Errorf("Expected parsed locale code %q, got %q", locale, parsed.LocaleCode())
+		}
+		if parsed.SessionID() != expectedSessionID {
+			t.Errorf("Expected parsed session id %q, got %q", expectedSessionID, parsed.SessionID())
+		}
+		if !parsed.User().IsLoggedIn() {
+			t.Error("Expected logged in user, IsLoggedIn() returned false")
+		}
+	})
+
+	t.Run("deep-copy", func(t *testing.T) {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			AcceptedLocales:      []string{"en_US", "es_MX"},
+			FeatureFlagOverrides: map[string]string{"flag": "on"},
+			Consent:              &edgecontext.Consent{Analytics: true},
+			Baggage:              map[string]string{"key": "value"},
+			Hops:                 []string{"edge"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := e.Header()
+
+		if _, err := e.Derive(func(args *edgecontext.NewArgs) {
+			args.AcceptedLocales[0] = "fr_FR"
+			args.FeatureFlagOverrides["flag"] = "off"
+			args.Consent.Analytics = false
+			args.Baggage["key"] = "changed"
+			delete(args.Baggage, "key")
+			args.Hops[0] = "other"
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := e.AcceptedLocales()[0]; got != "en_US" {
+			t.Errorf("Expected original accepted locale %q, got %q", "en_US", got)
+		}
+		if got, _ := e.FeatureFlagOverride("flag"); got != "on" {
+			t.Errorf("Expected original feature flag override %q, got %q", "on", got)
+		}
+		if consent, _ := e.Consent(); !consent.Analytics {
+			t.Error("Expected original analytics consent to be unchanged")
+		}
+		if got, ok := e.BaggageItem("key"); got != "value" || !ok {
+			t.Errorf("Expected original baggage item %q, got %q, %v", "value", got, ok)
+		}
+		if got := e.Hops()[0]; got != "edge" {
+			t.Errorf("Expected original hop %q, got %q", "edge", got)
+		}
+		if e.Header() != header {
+			t.Errorf("Expected original header to be unchanged, got %q", e.Header())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := e.Derive(func(args *edgecontext.NewArgs) {
+			args.LocaleCode = "ES_MX"
+		})
+		if !errors.Is(err, edgecontext.ErrInvalidLocaleCode) {
+			t.Errorf("Expected edgecontext.ErrInvalidLocaleCode, got %v", err)
+		}
+	})
+}
+
+func TestValidatedAuthToken(t *testing.T) {
+	for _, c := range []struct {
+		label   string
+		header  string
+		subject string
+		err     error
+	}{
+		{
+			label:  "no-auth",
+			header: headerWithNoAuth,
+			err:    edgecontext.ErrEmptyToken,
+		},
+		{
+			label:   "valid-auth",
+			header:  headerWithValidAuth,
+			subject: "t2_example",
+		},
+		{
+			label:  "expired-auth",
+			header: headerWithExpiredAuth,
+			err:    jwt.ErrTokenExpired,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.FromHeader(context.Background(), c.header, globalTestImpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Call it twice to make sure the cached results are consistent.
+			for i := 0; i < 2; i++ {
+				token, err := e.ValidatedAuthToken()
+				if !errors.Is(err, c.err) {
+					t.Errorf("Expected error %v, got %v", c.err, err)
+				}
+				if c.err != nil {
+					if token != nil {
+						t.Errorf("Expected nil token, got %+v", *token)
+					}
+					continue
+				}
+				if token == nil {
+					t.Fatal("Expected non-nil token")
+				}
+				if token.Subject() != c.subject {
+					t.Errorf("Expected subject %q, got %q", c.subject, token.Subject())
+				}
+				if token != e.AuthToken() {
+					t.Error("Expected AuthToken to return the same cached token")
+				}
+			}
+		})
+	}
+}
+
+func TestFromHeaders(t *testing.T) {
+	headers := []string{
+		headerWithNoAuth,
+		"",
+		"\x0c\x00\x01\x0b",
+		headerWithValidAuth,
+		"\x0c\x00\x01\x0b",
+	}
+
+	ecs, err := edgecontext.FromHeaders(context.Background(), headers, globalTestImpl)
+	if size := errorsbp.BatchSize(err); size != 2 {
+		t.Errorf("Expected 2 errors, got %d: %v", size, err)
+	}
+	if len(ecs) != len(headers) {
+		t.Fatalf("Expected %d edge contexts, got %d", len(headers), len(ecs))
+	}
+	for i, e := range ecs {
+		switch i {
+		case 0, 3:
+			if e == nil {
+				t.Fatalf("Expected non-nil edge context #%d", i)
+			}
+			if e.Header() != headers[i] {
+				t.Errorf("#%d: Header expected %q, got %q", i, headers[i], e.Header())
+			}
+			if e.SessionID() != expectedSessionID {
+				t.Errorf("#%d: Expected session id %q, got %q", i, expectedSessionID, e.SessionID())
+			}
+		default:
+			if e != nil {
+				t.Errorf("Expected nil edge context #%d, got %#v", i, e)
+			}
+		}
+	}
+	if ecs[3].LocaleCode() != expectedLocaleCode {
+		t.Errorf("Expected locale code %q, got %q", expectedLocaleCode, ecs[3].LocaleCode())
+	}
+}
+
+func TestProtocolFactory(t *testing.T) {
+	impl := newTestImpl(t, edgecontext.Config{
+		SerializerPoolSize:   64,
+		DeserializerPoolSize: 64,
+		ProtocolFactory:      thrift.NewTCompactProtocolFactoryConf(nil),
+	})
+	ctx := context.Background()
+	args := edgecontext.NewArgs{
+		LoID:       expectedLoID,
+		SessionID:  expectedSessionID,
+		RequestID:  expectedRequestID,
+		LocaleCode: expectedLocaleCode,
+	}
+	e, err := edgecontext.New(ctx, impl, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary, err := edgecontext.New(ctx, globalTestImpl, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Header() == binary.Header() {
+		t.Errorf("Expected compact header to be different from binary header %q", binary.Header())
+	}
+
+	parsed, err := edgecontext.FromHeader(ctx, e.Header(), impl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.RequestID() != expectedRequestID {
+		t.Errorf("Expected request id %q, got %q", expectedRequestID, parsed.RequestID())
+	}
+	if parsed.SessionID() != expectedSessionID {
+		t.Errorf("Expected session id %q, got %q", expectedSessionID, parsed.SessionID())
+	}
+}
+
+func TestNewHeaderCache(t *testing.T) {
+	impl := newTestImpl(t, edgecontext.Config{
+		HeaderCacheSize: 16,
+	})
+	ctx := context.Background()
+	args := edgecontext.NewArgs{
+		LoID:              expectedLoID,
+		LoIDCreatedAt:     expectedCookieTime,
+		SessionID:         expectedSessionID,
+		AuthToken:         validToken,
+		DeviceID:          expectedDeviceID,
+		CountryCode:       expectedCountryCode,
+		OriginServiceName: expectedOrigin,
+		RequestID:         expectedRequestID,
+		LocaleCode:        expectedLocaleCode,
+	}
+	for i := 0; i < 2; i++ {
+		e, err := edgecontext.New(ctx, impl, args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Header() != headerWithValidAuth {
+			t.Errorf("#%d: Header expected %q, got %q", i, headerWithValidAuth, e.Header())
+		}
+	}
+
+	args.AuthToken = ""
+	args.RequestID = ""
+	args.LocaleCode = ""
+	args.CountryCode = ""
+	args.OriginServiceName = ""
+	e, err := edgecontext.New(ctx, impl, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncached, err := edgecontext.New(ctx, globalTestImpl, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Header() != uncached.Header() {
+		t.Errorf("Header expected %q, got %q", uncached.Header(), e.Header())
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	for _, c := range []struct {
+		label string
+		ip    string
+		err   error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label: "ipv4",
+			ip:    "192.0.2.1",
+		},
+		{
+			label: "ipv6",
+			ip:    "2001:db8::1",
+		},
+		{
+			label: "invalid",
+			ip:    "192.0.2.256",
+			err:   edgecontext.ErrInvalidClientIP,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			ctx := context.Background()
+			e, err := edgecontext.New(ctx, globalTestImpl, edgecontext.NewArgs{
+				ClientIP: c.ip,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			parsed := reparse(t, e)
+			if parsed.ClientIP() != c.ip {
+				t.Errorf("Expected client ip %q, got %q", c.ip, parsed.ClientIP())
+			}
+		})
+	}
+}
+
+// reparse parses the header of e back into a new EdgeRequestContext.
+func reparse(tb testing.TB, e *edgecontext.EdgeRequestContext) *edgecontext.EdgeRequestContext {
+	tb.Helper()
+	parsed, err := edgecontext.FromHeader(context.Background(), e.Header(), globalTestImpl)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return parsed
+}
+
+func TestUserAgent(t *testing.T) {
+	const userAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0"
+	e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reparse(t, e).UserAgent(); got != userAgent {
+		t.Errorf("Expected user agent %q, got %q", userAgent, got)
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	for _, c := range []struct {
+		label    string
+		timezone string
+		err      error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label:    "utc",
+			timezone: "UTC",
+		},
+		{
+			label:    "region",
+			timezone: "America/New_York",
+		},
+		{
+			label:    "nested",
+			timezone: "America/Argentina/Buenos_Aires",
+		},
+		{
+			label:    "offset",
+			timezone: "Etc/GMT+5",
+		},
+		{
+			label:    "invalid",
+			timezone: "../etc/passwd",
+			err:      edgecontext.ErrInvalidTimezone,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				Timezone: c.timezone,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			parsed := reparse(t, e)
+			if parsed.Timezone() != c.timezone {
+				t.Errorf("Expected time zone %q, got %q", c.timezone, parsed.Timezone())
+			}
+			if parsed.LocaleCode() != "" {
+				t.Errorf("Expected empty locale code, got %q", parsed.LocaleCode())
+			}
+		})
+	}
+}
+
+func TestAcceptedLocales(t *testing.T) {
+	for _, c := range []struct {
+		label   string
+		locales []string
+		err     error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label:   "valid",
+			locales: []string{"es_MX", "es", "en"},
+		},
+		{
+			label:   "invalid",
+			locales: []string{"es_MX", "ES"},
+			err:     edgecontext.ErrInvalidLocaleCode,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				LocaleCode:      "es_MX",
+				AcceptedLocales: c.locales,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			got := reparse(t, e).AcceptedLocales()
+			if len(got) != len(c.locales) {
+				t.Fatalf("Expected accepted locales %q, got %q", c.locales, got)
+			}
+			for i := range got {
+				if got[i] != c.locales[i] {
+					t.Errorf("Expected accepted locales %q, got %q", c.locales, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSubdivisionCode(t *testing.T) {
+	for _, c := range []struct {
+		label       string
+		country     string
+		subdivision string
+		err         error
+	}{
+		{
+			label:   "empty",
+			country: "US",
+		},
+		{
+			label:       "state",
+			country:     "US",
+			subdivision: "US-CA",
+		},
+		{
+			label:       "numeric",
+			country:     "FR",
+			subdivision: "FR-75",
+		},
+		{
+			label:       "no-country",
+			subdivision: "GB-ENG",
+		},
+		{
+			label:       "wrong-country",
+			country:     "CA",
+			subdivision: "US-CA",
+			err:         edgecontext.ErrInvalidSubdivisionCode,
+		},
+		{
+			label:       "invalid",
+			country:     "US",
+			subdivision: "California",
+			err:         edgecontext.ErrInvalidSubdivisionCode,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				CountryCode:     c.country,
+				SubdivisionCode: c.subdivision,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			geo := reparse(t, e).Geolocation()
+			if geo.CountryCode() != c.country {
+				t.Errorf("Expected country code %q, got %q", c.country, geo.CountryCode())
+			}
+			if geo.SubdivisionCode() != c.subdivision {
+				t.Errorf("Expected subdivision code %q, got %q", c.subdivision, geo.SubdivisionCode())
+			}
+		})
+	}
+}
+
+func TestCityAndMetroCode(t *testing.T) {
+	for _, c := range []struct {
+		label     string
+		city      string
+		metroCode int32
+		err       error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label:     "valid",
+			city:      "San Francisco",
+			metroCode: 807,
+		},
+		{
+			label: "unicode",
+			city:  "São Paulo",
+		},
+		{
+			label: "too-long",
+			city:  strings.Repeat("a", edgecontext.MaxCityLength+1),
+			err:   edgecontext.ErrInvalidCity,
+		},
+		{
+			label: "invalid-utf8",
+			city:  "\xff",
+			err:   edgecontext.ErrInvalidCity,
+		},
+		{
+			label:     "invalid-metro-code",
+			metroCode: 8070,
+			err:       edgecontext.ErrInvalidMetroCode,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				CountryCode: "US",
+				City:        c.city,
+				MetroCode:   c.metroCode,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			geo := reparse(t, e).Geolocation()
+			if geo.City() != c.city {
+				t.Errorf("Expected city %q, got %q", c.city, geo.City())
+			}
+			code, ok := geo.MetroCode()
+			if code != c.metroCode || ok != (c.metroCode != 0) {
+				t.Errorf("Expected metro code %d, got %d, %v", c.metroCode, code, ok)
+			}
+		})
+	}
+}
+
+func TestGeoProvenance(t *testing.T) {
+	for _, c := range []struct {
+		provenance edgecontext.GeoProvenance
+		err        error
+	}{
+		{
+			provenance: "",
+		},
+		{
+			provenance: edgecontext.GeoProvenanceIPLookup,
+		},
+		{
+			provenance: edgecontext.GeoProvenanceAccountSetting,
+		},
+		{
+			provenance: edgecontext.GeoProvenanceCarrier,
+		},
+		{
+			provenance: "guess",
+			err:        edgecontext.ErrInvalidGeoProvenance,
+		},
+	} {
+		t.Run(string(c.provenance), func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				CountryCode:   "US",
+				GeoProvenance: c.provenance,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := reparse(t, e).Geolocation().Provenance(); got != c.provenance {
+				t.Errorf("Expected provenance %q, got %q", c.provenance, got)
+			}
+		})
+	}
+}
+
+func TestFeatureFlagOverrides(t *testing.T) {
+	tooMany := make(map[string]string, edgecontext.MaxFeatureFlagOverrides+1)
+	for i := 0; i <= edgecontext.MaxFeatureFlagOverrides; i++ {
+		tooMany[fmt.Sprintf("flag-%d", i)] = "on"
+	}
+
+	for _, c := range []struct {
+		label     string
+		overrides map[string]string
+		err       error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label: "valid",
+			overrides: map[string]string{
+				"new_feed":    "variant_1",
+				"dark_launch": "",
+			},
+		},
+		{
+			label:     "too-many",
+			overrides: tooMany,
+			err:       edgecontext.ErrInvalidFeatureFlagOverrides,
+		},
+		{
+			label: "name-too-long",
+			overrides: map[string]string{
+				strings.Repeat("a", edgecontext.MaxFeatureFlagNameLength+1): "on",
+			},
+			err: edgecontext.ErrInvalidFeatureFlagOverrides,
+		},
+		{
+			label: "value-too-long",
+			overrides: map[string]string{
+				"new_feed": strings.Repeat("a", edgecontext.MaxFeatureFlagValueLength+1),
+			},
+			err: edgecontext.ErrInvalidFeatureFlagOverrides,
+		},
+		{
+			label: "empty-name",
+			overrides: map[string]string{
+				"": "on",
+			},
+			err: edgecontext.ErrInvalidFeatureFlagOverrides,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				FeatureFlagOverrides: c.overrides,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			parsed := reparse(t, e)
+			if got := parsed.FeatureFlagOverrides(); len(got) != len(c.overrides) {
+				t.Errorf("Expected overrides %v, got %v", c.overrides, got)
+			}
+			for name, value := range c.overrides {
+				if got, ok := parsed.FeatureFlagOverride(name); !ok || got != value {
+					t.Errorf("Expected override %q for %q, got %q, %v", value, name, got, ok)
+				}
+			}
+			if got, ok := parsed.FeatureFlagOverride("not_overridden"); ok {
+				t.Errorf("Expected no override, got %q", got)
+			}
+		})
+	}
+}
+
+func TestFeatureFlagOverridesCopied(t *testing.T) {
+	overrides := map[string]string{"new_feed": "variant_1"}
+	e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+		FeatureFlagOverrides: overrides,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	overrides["new_feed"] = "variant_2"
+	if got, _ := e.FeatureFlagOverride("new_feed"); got != "variant_1" {
+		t.Errorf("Expected override %q, got %q", "variant_1", got)
+	}
+	if got, _ := reparse(t, e).FeatureFlagOverride("new_feed"); got != "variant_1" {
+		t.Errorf("Expected parsed override %q, got %q", "variant_1", got)
+	}
+}
+
+func TestConsent(t *testing.T) {
+	for _, c := range []struct {
+		label   string
+		consent *edgecontext.Consent
+	}{
+		{
+			label: "unknown",
+		},
+		{
+			label:   "none",
+			consent: &edgecontext.Consent{},
+		},
+		{
+			label: "partial",
+			consent: &edgecontext.Consent{
+				Analytics: true,
+			},
+		},
+		{
+			label: "all",
+			consent: &edgecontext.Consent{
+				AdsPersonalization: true,
+				Analytics:          true,
+				ThirdPartySharing:  true,
+			},
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				Consent: c.consent,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			consent, ok := reparse(t, e).Consent()
+			if ok != (c.consent != nil) {
+				t.Fatalf("Expected ok %v, got %v", c.consent != nil, ok)
+			}
+			if ok && consent != *c.consent {
+				t.Errorf("Expected consent %+v, got %+v", *c.consent, consent)
+			}
+		})
+	}
+}
+
+func TestComplianceRegion(t *testing.T) {
+	for _, c := range []struct {
+		region edgecontext.ComplianceRegion
+		err    error
+	}{
+		{
+			region: "",
+		},
+		{
+			region: edgecontext.ComplianceRegionGDPR,
+		},
+		{
+			region: edgecontext.ComplianceRegionCCPA,
+		},
+		{
+			region: edgecontext.ComplianceRegionLGPD,
+		},
+		{
+			region: edgecontext.ComplianceRegionNone,
+		},
+		{
+			region: "pipeda",
+			err:    edgecontext.ErrInvalidComplianceRegion,
+		},
+	} {
+		t.Run(string(c.region), func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				ComplianceRegion: c.region,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := reparse(t, e).ComplianceRegion(); got != c.region {
+				t.Errorf("Expected compliance region %q, got %q", c.region, got)
+			}
+		})
+	}
+}
+
+func TestTeenRestricted(t *testing.T) {
+	for _, restricted := range []bool{false, true} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			TeenRestricted: restricted,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reparse(t, e).User().IsTeenRestricted(); got != restricted {
+			t.Errorf("Expected IsTeenRestricted %v, got %v", restricted, got)
+		}
+	}
+}
+
+func TestBaggage(t *testing.T) {
+	tooMany := make(map[string]string, edgecontext.MaxBaggageEntries+1)
+	for i := 0; i <= edgecontext.MaxBaggageEntries; i++ {
+		tooMany[fmt.Sprintf("key-%d", i)] = "value"
+	}
+
+	for _, c := range []struct {
+		label   string
+		baggage map[string]string
+		err     error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label: "valid",
+			baggage: map[string]string{
+				"experiment.bucket": "a",
+				"edge_version":      "",
+			},
+		},
+		{
+			label:   "too-many",
+			baggage: tooMany,
+			err:     edgecontext.ErrInvalidBaggage,
+		},
+		{
+			label: "key-too-long",
+			baggage: map[string]string{
+				strings.Repeat("a", edgecontext.MaxBaggageKeyLength+1): "value",
+			},
+			err: edgecontext.ErrInvalidBaggage,
+		},
+		{
+			label: "value-too-long",
+			baggage: map[string]string{
+				"key": strings.Repeat("a", edgecontext.MaxBaggageValueLength+1),
+			},
+			err: edgecontext.ErrInvalidBaggage,
+		},
+		{
+			label: "invalid-key",
+			baggage: map[string]string{
+				"key with spaces": "value",
+			},
+			err: edgecontext.ErrInvalidBaggage,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				Baggage: c.baggage,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			parsed := reparse(t, e)
+			if got := parsed.Baggage(); len(got) != len(c.baggage) {
+				t.Errorf("Expected baggage %v, got %v", c.baggage, got)
+			}
+			for key, value := range c.baggage {
+				if got, ok := parsed.BaggageItem(key); !ok || got != value {
+					t.Errorf("Expected baggage item %q for %q, got %q, %v", value, key, got, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	for _, c := range []struct {
+		label   string
+		traceID string
+		err     error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label:   "valid",
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			label:   "uppercase",
+			traceID: "4BF92F3577B34DA6A3CE929D0E0E4736",
+			err:     edgecontext.ErrInvalidTraceID,
+		},
+		{
+			label:   "too-short",
+			traceID: "4bf92f3577b34da6",
+			err:     edgecontext.ErrInvalidTraceID,
+		},
+		{
+			label:   "all-zeros",
+			traceID: "00000000000000000000000000000000",
+			err:     edgecontext.ErrInvalidTraceID,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				RequestID: expectedRequestID,
+				TraceID:   c.traceID,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			parsed := reparse(t, e)
+			if parsed.TraceID() != c.traceID {
+				t.Errorf("Expected trace id %q, got %q", c.traceID, parsed.TraceID())
+			}
+			if parsed.RequestID() != expectedRequestID {
+				t.Errorf("Expected request id %q, got %q", expectedRequestID, parsed.RequestID())
+			}
+			if id, err := edgecontext.RequestIDFromHeader(e.Header()); err != nil || id != expectedRequestID {
+				t.Errorf("Expected RequestIDFromHeader to return %q, got %q, %v", expectedRequestID, id, err)
+			}
+		})
+	}
+}
+
+func TestDevice(t *testing.T) {
+	for _, c := range []struct {
+		label     string
+		id        string
+		model     string
+		osName    string
+		osVersion string
+		err       error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label:     "all",
+			id:        expectedDeviceID,
+			model:     "iPhone14,2",
+			osName:    "iOS",
+			osVersion: "16.4.1",
+		},
+		{
+			label:  "no-id",
+			model:  "Pixel 7",
+			osName: "Android",
+		},
+		{
+			label: "model-too-long",
+			model: strings.Repeat("a", edgecontext.MaxDeviceFieldLength+1),
+			err:   edgecontext.ErrInvalidDevice,
+		},
+		{
+			label:     "invalid-utf8",
+			osVersion: "\xff",
+			err:       edgecontext.ErrInvalidDevice,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				DeviceID:        c.id,
+				DeviceModel:     c.model,
+				DeviceOSName:    c.osName,
+				DeviceOSVersion: c.osVersion,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			device := reparse(t, e).Device()
+			if device.ID() != c.id {
+				t.Errorf("Expected id %q, got %q", c.id, device.ID())
+			}
+			if device.Model() != c.model {
+				t.Errorf("Expected model %q, got %q", c.model, device.Model())
+			}
+			if device.OSName() != c.osName {
+				t.Errorf("Expected os name %q, got %q", c.osName, device.OSName())
+			}
+			if device.OSVersion() != c.osVersion {
+				t.Errorf("Expected os version %q, got %q", c.osVersion, device.OSVersion())
+			}
+		})
+	}
+}
+
+func TestFormFactor(t *testing.T) {
+	for _, c := range []struct {
+		formFactor edgecontext.FormFactor
+		err        error
+	}{
+		{
+			formFactor: "",
+		},
+		{
+			formFactor: edgecontext.FormFactorPhone,
+		},
+		{
+			formFactor: edgecontext.FormFactorTablet,
+		},
+		{
+			formFactor: edgecontext.FormFactorDesktop,
+		},
+		{
+			formFactor: edgecontext.FormFactorTV,
+		},
+		{
+			formFactor: edgecontext.FormFactorBot,
+		},
+		{
+			formFactor: "watch",
+			err:        edgecontext.ErrInvalidFormFactor,
+		},
+	} {
+		t.Run(string(c.formFactor), func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				FormFactor: c.formFactor,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := reparse(t, e).Device().FormFactor(); got != c.formFactor {
+				t.Errorf("Expected form factor %q, got %q", c.formFactor, got)
+			}
+		})
+	}
+}
+
+func TestSessionElevatedUntil(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	for _, c := range []struct {
+		label         string
+		elevatedUntil time.Time
+		expected      bool
+	}{
+		{
+			label: "not-elevated",
+		},
+		{
+			label:         "elevated",
+			elevatedUntil: now.Add(time.Minute),
+			expected:      true,
+		},
+		{
+			label:         "expired",
+			elevatedUntil: now.Add(-time.Minute),
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				SessionID:            expectedSessionID,
+				SessionElevatedUntil: c.elevatedUntil,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			session := reparse(t, e).Session()
+			ts, ok := session.ElevatedUntil()
+			if ok != !c.elevatedUntil.IsZero() || !ts.Equal(c.elevatedUntil) {
+				t.Errorf("Expected elevated until %v, got %v, %v", c.elevatedUntil, ts, ok)
+			}
+			if got := session.IsElevated(); got != c.expected {
+				t.Errorf("Expected IsElevated %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestOriginServiceVersion(t *testing.T) {
+	for _, c := range []struct {
+		label    string
+		name     string
+		version  string
+		deployID string
+		edgePOP  string
+	}{
+		{
+			label: "name-only",
+			name:  expectedOrigin,
+		},
+		{
+			label:    "all",
+			name:     expectedOrigin,
+			version:  "1.2.3",
+			deployID: "deploy-20230601-abcdef",
+			edgePOP:  "sjc1",
+		},
+		{
+			label:   "no-name",
+			version: "1.2.3",
+		},
+		{
+			label:   "edge-pop-only",
+			edgePOP: "iad2",
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				OriginServiceName:     c.name,
+				OriginServiceVersion:  c.version,
+				OriginServiceDeployID: c.deployID,
+				EdgePOP:               c.edgePOP,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			origin := reparse(t, e).OriginService()
+			if origin.Name() != c.name {
+				t.Errorf("Expected name %q, got %q", c.name, origin.Name())
+			}
+			if origin.Version() != c.version {
+				t.Errorf("Expected version %q, got %q", c.version, origin.Version())
+			}
+			if origin.DeployID() != c.deployID {
+				t.Errorf("Expected deploy id %q, got %q", c.deployID, origin.DeployID())
+			}
+			if origin.EdgePOP() != c.edgePOP {
+				t.Errorf("Expected edge pop %q, got %q", c.edgePOP, origin.EdgePOP())
+			}
+		})
+	}
+}
+
+func TestAttestationVerdict(t *testing.T) {
+	for _, c := range []struct {
+		verdict edgecontext.AttestationVerdict
+		trusted bool
+		err     error
+	}{
+		{
+			verdict: "",
+		},
+		{
+			verdict: edgecontext.AttestationVerdictStrong,
+			trusted: true,
+		},
+		{
+			verdict: edgecontext.AttestationVerdictDevice,
+			trusted: true,
+		},
+		{
+			verdict: edgecontext.AttestationVerdictBasic,
+		},
+		{
+			verdict: edgecontext.AttestationVerdictFailed,
+		},
+		{
+			verdict: edgecontext.AttestationVerdictUnavailable,
+		},
+		{
+			verdict: "maybe",
+			err:     edgecontext.ErrInvalidAttestationVerdict,
+		},
+	} {
+		t.Run(string(c.verdict), func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				DeviceID:           expectedDeviceID,
+				AttestationVerdict: c.verdict,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			got := reparse(t, e).Device().AttestationVerdict()
+			if got != c.verdict {
+				t.Errorf("Expected attestation verdict %q, got %q", c.verdict, got)
+			}
+			if got.IsTrusted() != c.trusted {
+				t.Errorf("Expected IsTrusted %v, got %v", c.trusted, got.IsTrusted())
+			}
+		})
+	}
+}
+
+func TestSessionMFA(t *testing.T) {
+	for _, mfa := range []bool{false, true} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			SessionID:  expectedSessionID,
+			SessionMFA: mfa,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reparse(t, e).Session().UsedMFA(); got != mfa {
+			t.Errorf("Expected UsedMFA %v, got %v", mfa, got)
+		}
+	}
+}
+
+func TestAuthMethod(t *testing.T) {
+	for _, c := range []struct {
+		method edgecontext.AuthMethod
+		err    error
+	}{
+		{
+			method: "",
+		},
+		{
+			method: edgecontext.AuthMethodPassword,
+		},
+		{
+			method: edgecontext.AuthMethodGoogleSSO,
+		},
+		{
+			method: edgecontext.AuthMethodAppleSSO,
+		},
+		{
+			method: edgecontext.AuthMethodMagicLink,
+		},
+		{
+			method: edgecontext.AuthMethodRefreshToken,
+		},
+		{
+			method: "carrier_pigeon",
+			err:    edgecontext.ErrInvalidAuthMethod,
+		},
+	} {
+		t.Run(string(c.method), func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				SessionID:  expectedSessionID,
+				AuthMethod: c.method,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := reparse(t, e).Session().AuthMethod(); got != c.method {
+				t.Errorf("Expected auth method %q, got %q", c.method, got)
+			}
+		})
+	}
+}
+
+func TestEmailVerified(t *testing.T) {
+	for _, verified := range []bool{false, true} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			EmailVerified: verified,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reparse(t, e).User().IsEmailVerified(); got != verified {
+			t.Errorf("Expected IsEmailVerified %v, got %v", verified, got)
+		}
+	}
+}
+
+func TestPremium(t *testing.T) {
+	for _, premium := range []bool{false, true} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			Premium: premium,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reparse(t, e).User().IsPremium(); got != premium {
+			t.Errorf("Expected IsPremium %v, got %v", premium, got)
+		}
+	}
+}
+
+func TestWorkloadIdentity(t *testing.T) {
+	for _, c := range []struct {
+		label string
+		id    string
+		err   error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label: "valid",
+			id:    "spiffe://reddit.com/ns/jobs/sa/backfill",
+		},
+		{
+			label: "trust-domain-only",
+			id:    "spiffe://reddit.com",
+		},
+		{
+			label: "wrong-scheme",
+			id:    "https://reddit.com/ns/jobs",
+			err:   edgecontext.ErrInvalidWorkloadIdentity,
+		},
+		{
+			label: "uppercase-trust-domain",
+			id:    "spiffe://Reddit.com/ns/jobs",
+			err:   edgecontext.ErrInvalidWorkloadIdentity,
+		},
+		{
+			label: "trailing-slash",
+			id:    "spiffe://reddit.com/ns/",
+			err:   edgecontext.ErrInvalidWorkloadIdentity,
+		},
+		{
+			label: "too-long",
+			id:    "spiffe://reddit.com/" + strings.Repeat("a", edgecontext.MaxWorkloadIdentityLength),
+			err:   edgecontext.ErrInvalidWorkloadIdentity,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				WorkloadIdentity: c.id,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			id, ok := reparse(t, e).WorkloadIdentity()
+			if id != c.id || ok != (c.id != "") {
+				t.Errorf("Expected workload identity %q, got %q, %v", c.id, id, ok)
+			}
+		})
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	t.Run("no-deadline", func(t *testing.T) {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		e = reparse(t, e)
+		if deadline, ok := e.Deadline(); ok {
+			t.Errorf("Expected no deadline, got %v", deadline)
+		}
+		ctx, cancel := e.ContextWithDeadline(context.Background())
+		defer cancel()
+		if deadline, ok := ctx.Deadline(); ok {
+			t.Errorf("Expected no context deadline, got %v", deadline)
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		expected := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			Deadline: expected,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		e = reparse(t, e)
+		if deadline, ok := e.Deadline(); !ok || !deadline.Equal(expected) {
+			t.Errorf("Expected deadline %v, got %v, %v", expected, deadline, ok)
+		}
+		ctx, cancel := e.ContextWithDeadline(context.Background())
+		defer cancel()
+		if deadline, ok := ctx.Deadline(); !ok || !deadline.Equal(expected) {
+			t.Errorf("Expected context deadline %v, got %v, %v", expected, deadline, ok)
+		}
+	})
+
+	t.Run("passed", func(t *testing.T) {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			Deadline: time.Now().Add(-time.Second),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := reparse(t, e).ContextWithDeadline(context.Background())
+		defer cancel()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			t.Errorf("Expected context error %v, got %v", context.DeadlineExceeded, ctx.Err())
+		}
+	})
+}
+
+func TestTraffic(t *testing.T) {
+	for _, c := range []struct {
+		label     string
+		shadow    bool
+		synthetic bool
+		canary    bool
+	}{
+		{
+			label: "organic",
+		},
+		{
+			label:  "shadow",
+			shadow: true,
+		},
+		{
+			label:     "synthetic",
+			synthetic: true,
+		},
+		{
+			label:     "shadow-synthetic",
+			shadow:    true,
+			synthetic: true,
+		},
+		{
+			label:  "canary",
+			canary: true,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				Shadow:    c.shadow,
+				Synthetic: c.synthetic,
+				Canary:    c.canary,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			traffic := reparse(t, e).Traffic()
+			if got := traffic.IsShadow(); got != c.shadow {
+				t.Errorf("Expected IsShadow %v, got %v", c.shadow, got)
+			}
+			if got := traffic.IsSynthetic(); got != c.synthetic {
+				t.Errorf("Expected IsSynthetic %v, got %v", c.synthetic, got)
+			}
+			if got := traffic.IsCanary(); got != c.canary {
+				t.Errorf("Expected IsCanary %v, got %v", c.canary, got)
+			}
+		})
+	}
+}
+
+func TestTenantID(t *testing.T) {
+	for _, c := range []struct {
+		label    string
+		tenantID string
+		err      error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label:    "valid",
+			tenantID: "reddit",
+		},
+		{
+			label:    "with-separators",
+			tenantID: "brand_2-eu",
+		},
+		{
+			label:    "uppercase",
+			tenantID: "Reddit",
+			err:      edgecontext.ErrInvalidTenantID,
+		},
+		{
+			label:    "leading-hyphen",
+			tenantID: "-reddit",
+			err:      edgecontext.ErrInvalidTenantID,
+		},
+		{
+			label:    "too-long",
+			tenantID: strings.Repeat("a", edgecontext.MaxTenantIDLength+1),
+			err:      edgecontext.ErrInvalidTenantID,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				TenantID: c.tenantID,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := reparse(t, e).TenantID(); got != c.tenantID {
+				t.Errorf("Expected tenant id %q, got %q", c.tenantID, got)
+			}
+		})
+	}
+}
+
+func TestReferringSurface(t *testing.T) {
+	for _, c := range []struct {
+		surface edgecontext.ReferringSurface
+		err     error
+	}{
+		{
+			surface: "",
+		},
+		{
+			surface: edgecontext.ReferringSurfaceHomeFeed,
+		},
+		{
+			surface: edgecontext.ReferringSurfacePushNotification,
+		},
+		{
+			surface: edgecontext.ReferringSurfaceEmail,
+		},
+		{
+			surface: edgecontext.ReferringSurfaceEmbed,
+		},
+		{
+			surface: edgecontext.ReferringSurfaceOther,
+		},
+		{
+			surface: "billboard",
+			err:     edgecontext.ErrInvalidReferringSurface,
+		},
+	} {
+		t.Run(string(c.surface), func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				ReferringSurface: c.surface,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := reparse(t, e).ReferringSurface(); got != c.surface {
+				t.Errorf("Expected referring surface %q, got %q", c.surface, got)
+			}
+		})
+	}
+}
+
+func TestUserID(t *testing.T) {
+	for _, c := range []struct {
+		label          string
+		args           edgecontext.NewArgs
+		expectedID     string
+		expectedSource edgecontext.UserIDSource
+	}{
+		{
+			label:          "none",
+			expectedSource: edgecontext.UserIDSourceNone,
+		},
+		{
+			label: "loid",
+			args: edgecontext.NewArgs{
+				LoID: expectedLoID,
+			},
+			expectedID:     expectedLoID,
+			expectedSource: edgecontext.UserIDSourceLoID,
+		},
+		{
+			label: "logged-in",
+			args: edgecontext.NewArgs{
+				LoID:      expectedLoID,
+				AuthToken: validToken,
+			},
+			expectedID:     "t2_example",
+			expectedSource: edgecontext.UserIDSourceToken,
+		},
+		{
+			label: "expired-token",
+			args: edgecontext.NewArgs{
+				LoID:      expectedLoID,
+				AuthToken: expiredToken,
+			},
+			expectedID:     expectedLoID,
+			expectedSource: edgecontext.UserIDSourceLoID,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, c.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			id, source := reparse(t, e).UserID()
+			if id != c.expectedID || source != c.expectedSource {
+				t.Errorf("Expected user id %q from %q, got %q from %q", c.expectedID, c.expectedSource, id, source)
+			}
+		})
+	}
+}
+
+func TestServiceAuthToken(t *testing.T) {
+	key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{
+		// The failed validations are logged.
+		Logger: log.NopWrapper,
+	})
+	signer := edgecontext.NewSigner(key, keyID)
+	signService := func(t *testing.T, expiresAt time.Time) string {
+		t.Helper()
+		token, err := signer.SignService(edgecontext.ServiceAuthenticationToken{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+			ServiceName: "foo",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	userToken, err := signer.Sign(edgecontext.AuthenticationToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "t2_example",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceToken := signService(t, time.Now().Add(time.Hour))
+
+	for _, c := range []struct {
+		label        string
+		userToken    string
+		serviceToken string
+		userErr      error
+		serviceErr   error
+	}{
+		{
+			label:      "none",
+			userErr:    edgecontext.ErrEmptyToken,
+			serviceErr: edgecontext.ErrEmptyToken,
+		},
+		{
+			label:      "user-only",
+			userToken:  userToken,
+			serviceErr: edgecontext.ErrEmptyToken,
+		},
+		{
+			label:        "service-only",
+			serviceToken: serviceToken,
+			userErr:      edgecontext.ErrEmptyToken,
+		},
+		{
+			label:        "both",
+			userToken:    userToken,
+			serviceToken: serviceToken,
+		},
+		{
+			label:        "expired-service",
+			userToken:    userToken,
+			serviceToken: signService(t, time.Now().Add(-time.Hour)),
+			serviceErr:   jwt.ErrTokenExpired,
+		},
+		{
+			label:        "user-token-as-service",
+			serviceToken: userToken,
+			userErr:      edgecontext.ErrEmptyToken,
+			serviceErr:   edgecontext.ErrMissingServiceName,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), impl, edgecontext.NewArgs{
+				AuthToken:        c.userToken,
+				ServiceAuthToken: c.serviceToken,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, err = edgecontext.FromHeader(context.Background(), e.Header(), impl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := e.ValidatedAuthToken(); !errors.Is(err, c.userErr) {
+				t.Errorf("Expected user token error %v, got %v", c.userErr, err)
+			}
+			token, err := e.ValidatedServiceAuthToken()
+			if !errors.Is(err, c.serviceErr) {
+				t.Errorf("Expected service token error %v, got %v", c.serviceErr, err)
+			}
+			if c.serviceErr == nil && token.ServiceName != "foo" {
+				t.Errorf("Expected service name %q, got %q", "foo", token.ServiceName)
+			}
+			if token != e.ServiceAuthToken() {
+				t.Error("Expected ServiceAuthToken to return the same cached token")
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package edgecontext
+
+// GeoProvenance describes how the geolocation of a request was determined.
+//
+// Services making compliance decisions based on geolocation can use it to
+// decide whether the signal is trustworthy enough.
+type GeoProvenance string
+
+// GeoProvenance values.
+const (
+	// The geolocation was looked up from the client IP address.
+	GeoProvenanceIPLookup GeoProvenance = "ip"
+
+	// The geolocation came from the settings of the user account.
+	GeoProvenanceAccountSetting GeoProvenance = "account"
+
+	// The geolocation came from a header set by the mobile carrier.
+	GeoProvenanceCarrier GeoProvenance = "carrier"
+)
+
+// IsKnown returns true if p is one of the GeoProvenance constants.
+func (p GeoProvenance) IsKnown() bool {
+	switch p {
+	case GeoProvenanceIPLookup, GeoProvenanceAccountSetting, GeoProvenanceCarrier:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package edgecontext
+
+import (
+	"hash/maphash"
+	"sync"
+
+	"github.com/reddit/baseplate.go/timebp"
+)
+
+// headerCache memoizes the serialized headers of NewArgs used by New.
+//
+// It's a direct-mapped cache: each NewArgs hashes to exactly one slot,
+// and a new entry simply replaces the old one in the same slot.
+// This keeps both lookups and insertions cheap,
+// at the cost of some extra misses on hash collisions.
+type headerCache struct {
+	seed maphash.Seed
+
+	lock  sync.RWMutex
+	slots []headerCacheEntry
+}
+
+type headerCacheEntry struct {
+	ok     bool
+	args   NewArgs
+	header string
+}
+
+func newHeaderCache(size int) *headerCache {
+	return &headerCache{
+		seed:  maphash.MakeSeed(),
+		slots: make([]headerCacheEntry, size),
+	}
+}
+
+// normalize returns a copy of args with only the parts that matter to the
+// serialized header,
+// so that args only differ in things like time zones or monotonic clock
+// readings of the timestamps are considered equal.
+func (c *headerCache) normalize(args NewArgs) NewArgs {
+	args.LoIDCreatedAt = timebp.MillisecondsToTime(timebp.TimeToMilliseconds(args.LoIDCreatedAt))
+	args.SessionCreatedAt = timebp.MillisecondsToTime(timebp.TimeToMilliseconds(args.SessionCreatedAt))
+	args.SessionElevatedUntil = timebp.MillisecondsToTime(timebp.TimeToMilliseconds(args.SessionElevatedUntil))
+	args.Deadline = timebp.MillisecondsToTime(timebp.TimeToMilliseconds(args.Deadline))
+	return args
+}
+
+func (c *headerCache) slot(args *NewArgs) *headerCacheEntry {
+	var h maphash.Hash
+	h.SetSeed(c.seed)
+	for _, s := range [...]string{
+		args.LoID,
+		args.SessionID,
+		string(args.SessionType),
+		string(args.AuthMethod),
+		args.DeviceID,
+		args.DeviceModel,
+		args.DeviceOSName,
+		args.DeviceOSVersion,
+		string(args.FormFactor),
+		string(args.AttestationVerdict),
+		args.AuthToken,
+		args.ServiceAuthToken,
+		args.OriginServiceName,
+		args.OriginServiceVersion,
+		args.OriginServiceDeployID,
+		args.EdgePOP,
+		args.CountryCode,
+		args.SubdivisionCode,
+		args.City,
+		string(args.GeoProvenance),
+		string(args.ComplianceRegion),
+		args.WorkloadIdentity,
+		args.TenantID,
+		string(args.ReferringSurface),
+		args.RequestID,
+		args.TraceID,
+		args.LocaleCode,
+		args.ClientIP,
+		args.UserAgent,
+		args.ClientVersion.Version,
+		string(args.Platform),
+		args.Timezone,
+	} {
+		h.WriteString(s)
+		h.WriteByte(0)
+	}
+	for _, s := range args.AcceptedLocales {
+		h.WriteString(s)
+		h.WriteByte(0)
+	}
+	for _, s := range args.Hops {
+		h.WriteString(s)
+		h.WriteByte(0)
+	}
+	// Maps are not hashed as their iteration order is random,
+	// they are still compared in argsEqual.
+	return &c.slots[h.Sum64()%uint64(len(c.slots))]
+}
+
+// get returns the cached header for args, if any.
+func (c *headerCache) get(args NewArgs) (header string, ok bool) {
+	args = c.normalize(args)
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	entry := c.slot(&args)
+	if entry.ok && argsEqual(&entry.args, &args) {
+		return entry.header, true
+	}
+	return "", false
+}
+
+// add adds the serialized header of args into the cache.
+func (c *headerCache) add(args NewArgs, header string) {
+	// Make sure later changes to the caller's slices don't affect the cache.
+	args = c.normalize(args).clone()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	*c.slot(&args) = headerCacheEntry{
+		ok:     true,
+		args:   args,
+		header: header,
+	}
+}
+
+// argsEqual returns true if normalized a and b would be serialized into the
+// same header.
+//
+// It must compare all the fields of NewArgs,
+// TestArgsEqualCoversAllFields makes sure that no field is missing.
+func argsEqual(a, b *NewArgs) bool {
+	return a.LoID == b.LoID &&
+		a.LoIDCreatedAt == b.LoIDCreatedAt &&
+		a.SessionID == b.SessionID &&
+		a.SessionCreatedAt == b.SessionCreatedAt &&
+		a.SessionType == b.SessionType &&
+		a.SessionElevatedUntil == b.SessionElevatedUntil &&
+		a.SessionMFA == b.SessionMFA &&
+		a.AuthMethod == b.AuthMethod &&
+		a.DeviceID == b.DeviceID &&
+		a.DeviceModel == b.DeviceModel &&
+		a.DeviceOSName == b.DeviceOSName &&
+		a.DeviceOSVersion == b.DeviceOSVersion &&
+		a.FormFactor == b.FormFactor &&
+		a.AttestationVerdict == b.AttestationVerdict &&
+		a.AuthToken == b.AuthToken &&
+		a.ServiceAuthToken == b.ServiceAuthToken &&
+		a.OriginServiceName == b.OriginServiceName &&
+		a.OriginServiceVersion == b.OriginServiceVersion &&
+		a.OriginServiceDeployID == b.OriginServiceDeployID &&
+		a.EdgePOP == b.EdgePOP &&
+		a.CountryCode == b.CountryCode &&
+		a.SubdivisionCode == b.SubdivisionCode &&
+		a.City == b.City &&
+		a.MetroCode == b.MetroCode &&
+		a.GeoProvenance == b.GeoProvenance &&
+		a.RequestID == b.RequestID &&
+		a.TraceID == b.TraceID &&
+		a.LocaleCode == b.LocaleCode &&
+		a.ClientIP == b.ClientIP &&
+		a.UserAgent == b.UserAgent &&
+		a.ClientVersion == b.ClientVersion &&
+		a.Platform == b.Platform &&
+		a.InternalNetwork == b.InternalNetwork &&
+		a.LimitAdTracking == b.LimitAdTracking &&
+		a.AnonymizingProxy == b.AnonymizingProxy &&
+		a.ClientASN == b.ClientASN &&
+		a.Timezone == b.Timezone &&
+		stringsEqual(a.AcceptedLocales, b.AcceptedLocales) &&
+		stringMapsEqual(a.FeatureFlagOverrides, b.FeatureFlagOverrides) &&
+		consentsEqual(a.Consent, b.Consent) &&
+		a.ComplianceRegion == b.ComplianceRegion &&
+		a.WorkloadIdentity == b.WorkloadIdentity &&
+		a.TenantID == b.TenantID &&
+		a.ReferringSurface == b.ReferringSurface &&
+		a.Deadline == b.Deadline &&
+		a.Shadow == b.Shadow &&
+		a.Synthetic == b.Synthetic &&
+		a.Canary == b.Canary &&
+		a.TeenRestricted == b.TeenRestricted &&
+		a.EmailVerified == b.EmailVerified &&
+		a.Premium == b.Premium &&
+		stringMapsEqual(a.Baggage, b.Baggage) &&
+		stringsEqual(a.Hops, b.Hops)
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func stringMapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if other, ok := b[k]; !ok || other != v {
+			return false
+		}
+	}
+	return true
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func consentsEqual(a, b *Consent) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
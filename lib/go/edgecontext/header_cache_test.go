@@ -0,0 +1,108 @@
+package edgecontext
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHeaderCache(t *testing.T) {
+	c := newHeaderCache(16)
+	args := NewArgs{
+		LoID:          "t2_deadbeef",
+		LoIDCreatedAt: time.Unix(100, 0),
+		SessionID:     "beefdead",
+	}
+	if _, ok := c.get(args); ok {
+		t.Fatal("Expected miss on empty cache")
+	}
+	c.add(args, "header")
+
+	same := args
+	same.LoIDCreatedAt = time.Unix(100, 0).In(time.FixedZone("test", 3600))
+	if header, ok := c.get(same); !ok || header != "header" {
+		t.Errorf("Expected hit with header %q, got %q, %v", "header", header, ok)
+	}
+
+	different := args
+	different.SessionID = "deadbeef"
+	if header, ok := c.get(different); ok {
+		t.Errorf("Expected miss, got %q", header)
+	}
+	different = args
+	different.LoIDCreatedAt = time.Unix(101, 0)
+	if header, ok := c.get(different); ok {
+		t.Errorf("Expected miss, got %q", header)
+	}
+
+	locales := []string{"en_US", "en"}
+	withLocales := args
+	withLocales.AcceptedLocales = locales
+	c.add(withLocales, "header-with-locales")
+	locales[1] = "es"
+	if header, ok := c.get(withLocales); ok {
+		t.Errorf("Expected miss after the slice is modified, got %q", header)
+	}
+	withLocales.AcceptedLocales = []string{"en_US", "en"}
+	if header, ok := c.get(withLocales); !ok || header != "header-with-locales" {
+		t.Errorf("Expected hit with header %q, got %q, %v", "header-with-locales", header, ok)
+	}
+}
+
+// TestArgsEqualCoversAllFields makes sure that argsEqual is updated when new
+// fields are added to NewArgs.
+func TestArgsEqualCoversAllFields(t *testing.T) {
+	var base NewArgs
+	typ := reflect.TypeOf(base)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		t.Run(field.Name, func(t *testing.T) {
+			changed := base
+			v := reflect.ValueOf(&changed).Elem().Field(i)
+			setNonZero(t, v)
+			if argsEqual(&base, &changed) {
+				t.Errorf("argsEqual does not compare NewArgs.%s", field.Name)
+			}
+		})
+	}
+}
+
+// setNonZero sets v to an arbitrary non-zero value.
+func setNonZero(t *testing.T, v reflect.Value) {
+	t.Helper()
+	switch v.Kind() {
+	default:
+		t.Fatalf("Unsupported kind %v of type %v", v.Kind(), v.Type())
+	case reflect.String:
+		v.SetString("a")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		setNonZero(t, s.Index(0))
+		v.Set(s)
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		setNonZero(t, v.Elem())
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		key := reflect.New(v.Type().Key()).Elem()
+		setNonZero(t, key)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		setNonZero(t, elem)
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(time.Unix(1, 0)))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			setNonZero(t, v.Field(i))
+		}
+	}
+}
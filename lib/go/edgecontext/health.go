@@ -0,0 +1,97 @@
+package edgecontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrKeysStale is an error returned by Impl.Healthy indicates that the public
+// keys for jwt tokens were not refreshed within Config.MaxKeyStaleness.
+var ErrKeysStale = errors.New("edgecontext: public keys are stale")
+
+// markKeysRefreshed records a successful refresh of the default keys,
+// even when they are unchanged.
+func (impl *Impl) markKeysRefreshed() {
+	impl.keysRefreshed.Store(time.Now())
+	atomic.StoreInt32(&impl.staleWarned, 0)
+}
+
+// keyStaleness returns the duration since the last successful refresh of the
+// default keys, or false when they were never loaded.
+//
+// When the keys exceeded Config.MaxKeyStaleness, a warning is logged once
+// until the next refresh.
+func (impl *Impl) keyStaleness(ctx context.Context) (time.Duration, bool) {
+	refreshed, ok := impl.keysRefreshed.Load().(time.Time)
+	if !ok {
+		return 0, false
+	}
+	staleness := time.Since(refreshed)
+	if impl.maxKeyStaleness > 0 &&
+		staleness > impl.maxKeyStaleness &&
+		atomic.CompareAndSwapInt32(&impl.staleWarned, 0, 1) {
+		impl.logger.Log(ctx, fmt.Sprintf(
+			"Public keys for jwt tokens were last refreshed %v ago, exceeding MaxKeyStaleness %v, the keys source could be failing silently.",
+			staleness.Round(time.Second),
+			impl.maxKeyStaleness,
+		))
+	}
+	return staleness, true
+}
+
+// Healthy returns nil when impl is able to validate jwt tokens:
+// the public keys are loaded (or it's in dev mode),
+// and they were refreshed within Config.MaxKeyStaleness when it's set.
+//
+// It's suitable for the readiness probe of the service.
+func (impl *Impl) Healthy(ctx context.Context) error {
+	if _, err := impl.loadKeys(); err != nil {
+		return err
+	}
+	if impl.maxKeyStaleness <= 0 {
+		return nil
+	}
+	staleness, ok := impl.keyStaleness(ctx)
+	if !ok {
+		// Dev mode without public keys.
+		return nil
+	}
+	if staleness > impl.maxKeyStaleness {
+		return fmt.Errorf(
+			"%w: last refreshed %v ago, max staleness %v",
+			ErrKeysStale,
+			staleness.Round(time.Millisecond),
+			impl.maxKeyStaleness,
+		)
+	}
+	return nil
+}
+
+// IsHealthy is Healthy in the form of baseplate.HealthChecker.
+func (impl *Impl) IsHealthy(ctx context.Context) bool {
+	return impl.Healthy(ctx) == nil
+}
+
+// KeyStalenessCollector returns a prometheus collector exporting the seconds
+// since the last successful refresh of the public keys for jwt tokens as the
+// edgecontext_keys_staleness_seconds gauge, computed on every scrape.
+//
+// It's not registered automatically, register it once per Impl, e.g.:
+//
+//	prometheus.MustRegister(impl.KeyStalenessCollector())
+func (impl *Impl) KeyStalenessCollector() prometheus.Collector {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "edgecontext",
+		Subsystem: "keys",
+		Name:      "staleness_seconds",
+		Help:      "Seconds since the last successful refresh of the public keys for jwt tokens",
+	}, func() float64 {
+		staleness, _ := impl.keyStaleness(context.Background())
+		return staleness.Seconds()
+	})
+}
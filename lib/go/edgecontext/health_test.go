@@ -0,0 +1,71 @@
+package edgecontext_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/reddit/baseplate.go/ecinterface"
+	"github.com/reddit/baseplate.go/log"
+	"github.com/reddit/baseplate.go/secrets"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestHealthy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("loaded", func(t *testing.T) {
+		impl := newTestImplWithSecrets(t, edgecontext.Config{
+			MaxKeyStaleness: time.Hour,
+		}, map[string]secrets.GenericSecret{})
+		if err := impl.Healthy(ctx); err != nil {
+			t.Errorf("Healthy: %v", err)
+		}
+		if !impl.IsHealthy(ctx) {
+			t.Error("IsHealthy got false, want true")
+		}
+	})
+
+	t.Run("not-loaded", func(t *testing.T) {
+		impl := edgecontext.Init(edgecontext.Config{
+			Logger: log.NopWrapper,
+		})
+		t.Cleanup(func() {
+			ecinterface.Set(globalTestImpl)
+		})
+		if err := impl.Healthy(ctx); !errors.Is(err, edgecontext.ErrNoPublicKeysLoaded) {
+			t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrNoPublicKeysLoaded, err)
+		}
+		if impl.IsHealthy(ctx) {
+			t.Error("IsHealthy got true, want false")
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		impl := newTestImplWithSecrets(t, edgecontext.Config{
+			// The stale keys are logged.
+			Logger:          log.NopWrapper,
+			MaxKeyStaleness: time.Millisecond,
+		}, map[string]secrets.GenericSecret{})
+		time.Sleep(5 * time.Millisecond)
+		if err := impl.Healthy(ctx); !errors.Is(err, edgecontext.ErrKeysStale) {
+			t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrKeysStale, err)
+		}
+	})
+
+	t.Run("dev-mode", func(t *testing.T) {
+		impl := edgecontext.Init(edgecontext.Config{
+			Logger:                    log.NopWrapper,
+			InsecureDevModeHMACSecret: []byte("secret"),
+			MaxKeyStaleness:           time.Millisecond,
+		})
+		t.Cleanup(func() {
+			ecinterface.Set(globalTestImpl)
+		})
+		if err := impl.Healthy(ctx); err != nil {
+			t.Errorf("Healthy: %v", err)
+		}
+	})
+}
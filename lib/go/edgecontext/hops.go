@@ -0,0 +1,37 @@
+package edgecontext
+
+import (
+	"regexp"
+)
+
+// Limits of NewArgs.Hops.
+const (
+	MaxHops      = 16
+	MaxHopLength = 64
+)
+
+// HopRegex validates the service names in NewArgs.Hops.
+var HopRegex = regexp.MustCompile(`^[a-zA-Z\d_.-]+$`)
+
+// WithHop returns a copy of e with service appended to its hops.
+//
+// The hop list is bounded:
+// when it already has MaxHops entries, the oldest ones are dropped.
+// Services should call it before forwarding the edge context,
+// so that the hop list serves as a chain-of-custody when debugging.
+func (e *EdgeRequestContext) WithHop(service string) (*EdgeRequestContext, error) {
+	return e.Derive(func(args *NewArgs) {
+		args.Hops = appendHop(args.Hops, service)
+	})
+}
+
+// appendHop appends service to hops without modifying the underlying array of
+// hops, dropping the oldest hops to keep at most MaxHops entries.
+func appendHop(hops []string, service string) []string {
+	if len(hops) >= MaxHops {
+		hops = hops[len(hops)-MaxHops+1:]
+	}
+	result := make([]string, 0, len(hops)+1)
+	result = append(result, hops...)
+	return append(result, service)
+}
@@ -0,0 +1,123 @@
+package edgecontext_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestHops(t *testing.T) {
+	tooMany := make([]string, edgecontext.MaxHops+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("service-%d", i)
+	}
+	for _, c := range []struct {
+		label string
+		hops  []string
+		err   error
+	}{
+		{
+			label: "empty",
+		},
+		{
+			label: "valid",
+			hops:  []string{"edge", "graphql", "comments"},
+		},
+		{
+			label: "too-many",
+			hops:  tooMany,
+			err:   edgecontext.ErrInvalidHops,
+		},
+		{
+			label: "too-long",
+			hops:  []string{strings.Repeat("a", edgecontext.MaxHopLength+1)},
+			err:   edgecontext.ErrInvalidHops,
+		},
+		{
+			label: "invalid-name",
+			hops:  []string{"foo bar"},
+			err:   edgecontext.ErrInvalidHops,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				Hops: c.hops,
+			})
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := reparse(t, e).Hops(); !stringSlicesEqual(got, c.hops) {
+				t.Errorf("Expected hops %q, got %q", c.hops, got)
+			}
+		})
+	}
+}
+
+func TestWithHop(t *testing.T) {
+	e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+		RequestID: expectedRequestID,
+		Hops:      []string{"edge"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forwarded, err := e.WithHop("graphql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"edge", "graphql"}
+	if got := reparse(t, forwarded).Hops(); !stringSlicesEqual(got, expected) {
+		t.Errorf("Expected hops %q, got %q", expected, got)
+	}
+	if got := forwarded.RequestID(); got != expectedRequestID {
+		t.Errorf("Expected request id %q, got %q", expectedRequestID, got)
+	}
+	if got := e.Hops(); !stringSlicesEqual(got, []string{"edge"}) {
+		t.Errorf("Expected original hops to be unchanged, got %q", got)
+	}
+
+	if _, err := e.WithHop("foo bar"); !errors.Is(err, edgecontext.ErrInvalidHops) {
+		t.Errorf("Expected error %v, got %v", edgecontext.ErrInvalidHops, err)
+	}
+
+	t.Run("bounded", func(t *testing.T) {
+		hops := make([]string, edgecontext.MaxHops)
+		for i := range hops {
+			hops[i] = fmt.Sprintf("service-%d", i)
+		}
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			Hops: hops,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		forwarded, err := e.WithHop("last")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := append(append([]string(nil), hops[1:]...), "last")
+		if got := reparse(t, forwarded).Hops(); !stringSlicesEqual(got, expected) {
+			t.Errorf("Expected hops %q, got %q", expected, got)
+		}
+	})
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -2,9 +2,16 @@ package edgecontext_test
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/reddit/baseplate.go/ecinterface"
 	"github.com/reddit/baseplate.go/log"
 	"github.com/reddit/baseplate.go/secrets"
 
@@ -26,3 +33,73 @@ func TestMain(m *testing.M) {
 	globalTestImpl = edgecontext.Init(edgecontext.Config{Store: store})
 	os.Exit(m.Run())
 }
+
+// newTestImpl initializes a new Impl with the given cfg and its own secrets
+// store, for tests that need a non-default Config.
+//
+// If cfg.Logger is nil, it will be set to log.TestWrapper(t).
+func newTestImpl(t testing.TB, cfg edgecontext.Config) *edgecontext.Impl {
+	t.Helper()
+	return newTestImplWithSecrets(t, cfg, make(map[string]secrets.GenericSecret))
+}
+
+// encodePublicKey encodes a public key into PEM format,
+// to be used in the secrets store.
+func encodePublicKey(t testing.TB, key interface{}) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: der,
+	}))
+}
+
+// encodeCertificate encodes a self-signed x509 certificate of the key pair
+// into PEM format, to be used in the secrets store.
+func encodeCertificate(t testing.TB, pub, priv interface{}) string {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "edgecontext test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: der,
+	}))
+}
+
+// newTestImplWithSecrets is like newTestImpl,
+// but with the given raw secrets in the secrets store.
+//
+// If raw does not have secrets.JWTPubKeyPath,
+// secrets.TestJWTPubKeySecret will be used.
+func newTestImplWithSecrets(t testing.TB, cfg edgecontext.Config, raw map[string]secrets.GenericSecret) *edgecontext.Impl {
+	t.Helper()
+
+	store, _, err := secrets.NewTestSecrets(context.Background(), raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		store.Close()
+		// Init sets the global ecinterface implementation, restore it.
+		ecinterface.Set(globalTestImpl)
+	})
+
+	cfg.Store = store
+	if cfg.Logger == nil {
+		cfg.Logger = log.TestWrapper(t)
+	}
+	return edgecontext.Init(cfg)
+}
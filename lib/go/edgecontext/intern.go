@@ -0,0 +1,46 @@
+package edgecontext
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Limits of the strings interned by intern.
+//
+// Only short, enumerated low cardinality fields (country codes, locale codes,
+// origin service names, etc.) should be interned, as interned strings are
+// never evicted.
+// High cardinality or client controlled fields must not be interned,
+// the cap is only a safeguard against a client sending random values growing
+// the table unboundedly.
+const (
+	maxInternedLen   = 64
+	maxInternedCount = 4096
+)
+
+var (
+	interned      sync.Map // map[string]string
+	internedCount int64
+)
+
+// intern returns a canonical copy of s,
+// so that the same values decoded from different headers share the same
+// backing memory instead of each holding onto their own copies.
+//
+// Once the cap is reached, new values are returned as-is.
+func intern(s string) string {
+	if s == "" || len(s) > maxInternedLen {
+		return s
+	}
+	if v, ok := interned.Load(s); ok {
+		return v.(string)
+	}
+	if atomic.LoadInt64(&internedCount) >= maxInternedCount {
+		return s
+	}
+	v, loaded := interned.LoadOrStore(s, s)
+	if !loaded {
+		atomic.AddInt64(&internedCount, 1)
+	}
+	return v.(string)
+}
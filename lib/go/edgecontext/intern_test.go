@@ -0,0 +1,59 @@
+package edgecontext
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	ecthrift "github.com/reddit/edgecontext/lib/go/internal/reddit/edgecontext"
+)
+
+func stringData(s string) uintptr {
+	return (*[2]uintptr)(unsafe.Pointer(&s))[0]
+}
+
+func TestIntern(t *testing.T) {
+	a := string([]byte("en_US"))
+	b := string([]byte("en_US"))
+	if stringData(a) == stringData(b) {
+		t.Fatal("Expected different backing memory before interning")
+	}
+	if got := intern(a); got != a {
+		t.Errorf("Expected %q, got %q", a, got)
+	}
+	if stringData(intern(a)) != stringData(intern(b)) {
+		t.Error("Expected interned strings to share backing memory")
+	}
+
+	long := strings.Repeat("a", maxInternedLen+1)
+	if stringData(intern(long)) != stringData(long) {
+		t.Error("Expected long string to be returned as-is")
+	}
+}
+
+func TestArgsFromRequestIntern(t *testing.T) {
+	request := func() *ecthrift.Request {
+		city := string([]byte("San Francisco"))
+		return &ecthrift.Request{
+			Geolocation: &ecthrift.Geolocation{
+				CountryCode: ecthrift.CountryCode([]byte("US")),
+				City:        &city,
+			},
+			Baggage: map[string]string{
+				string([]byte("key")): string([]byte("value")),
+			},
+		}
+	}
+	a := argsFromRequest(request())
+	b := argsFromRequest(request())
+
+	if stringData(a.CountryCode) != stringData(b.CountryCode) {
+		t.Error("Expected country codes to be interned")
+	}
+	if stringData(a.City) == stringData(b.City) {
+		t.Error("Expected cities not to be interned")
+	}
+	if stringData(a.Baggage["key"]) == stringData(b.Baggage["key"]) {
+		t.Error("Expected baggage values not to be interned")
+	}
+}
@@ -0,0 +1,159 @@
+package edgecontext
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInactiveToken is an error returned by ValidateToken indicates that the
+// TokenIntrospector reported the opaque token as not active.
+var ErrInactiveToken = errors.New("edgecontext.ValidateToken: token is not active")
+
+// TokenIntrospector introspects opaque auth tokens, see
+// Config.TokenIntrospector.
+type TokenIntrospector interface {
+	// Introspect returns the claims of an active token,
+	// or ErrInactiveToken if the token is not active.
+	//
+	// The exp and nbf claims of the returned token are validated by the
+	// caller.
+	Introspect(ctx context.Context, token string) (*AuthenticationToken, error)
+}
+
+// DefaultIntrospectionTimeout is the default HTTPIntrospector.Timeout.
+const DefaultIntrospectionTimeout = time.Second
+
+// HTTPIntrospector is a TokenIntrospector calling an OAuth 2.0 token
+// introspection endpoint (RFC 7662).
+//
+// The members of the introspection response are mapped to the claims of the
+// same names, with the exception that the space separated "scope" member is
+// mapped to the scopes claim.
+type HTTPIntrospector struct {
+	// The URL of the introspection endpoint.
+	URL string
+
+	// Optional, the http client used to call the endpoint.
+	// When it's nil, http.DefaultClient will be used.
+	Client *http.Client
+
+	// Optional, the additional headers of the requests to the endpoint,
+	// e.g. Authorization header to authenticate the caller.
+	Header http.Header
+
+	// Optional, the timeout of each introspection request.
+	// When it's non-positive, DefaultIntrospectionTimeout will be used.
+	Timeout time.Duration
+}
+
+var _ TokenIntrospector = HTTPIntrospector{}
+
+// Introspect implements TokenIntrospector.
+func (hi HTTPIntrospector) Introspect(ctx context.Context, token string) (*AuthenticationToken, error) {
+	timeout := hi.Timeout
+	if timeout <= 0 {
+		timeout = DefaultIntrospectionTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		hi.URL,
+		strings.NewReader(url.Values{"token": {token}}.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range hi.Header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := hi.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("edgecontext.HTTPIntrospector: unexpected http status %q", resp.Status)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("edgecontext.HTTPIntrospector: malformed response: %w", err)
+	}
+	return introspectionClaims(raw)
+}
+
+// introspectionClaims maps an introspection response into AuthenticationToken.
+func introspectionClaims(raw map[string]json.RawMessage) (*AuthenticationToken, error) {
+	var active bool
+	if v, ok := raw["active"]; ok {
+		if err := json.Unmarshal(v, &active); err != nil {
+			return nil, fmt.Errorf("edgecontext.HTTPIntrospector: malformed active member: %w", err)
+		}
+	}
+	if !active {
+		return nil, ErrInactiveToken
+	}
+	delete(raw, "active")
+
+	if v, ok := raw["scope"]; ok {
+		if _, exists := raw["scopes"]; !exists {
+			var scope string
+			if err := json.Unmarshal(v, &scope); err != nil {
+				return nil, fmt.Errorf("edgecontext.HTTPIntrospector: malformed scope member: %w", err)
+			}
+			scopes, err := json.Marshal(strings.Fields(scope))
+			if err != nil {
+				return nil, err
+			}
+			raw["scopes"] = scopes
+		}
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	claims := new(AuthenticationToken)
+	if err := json.Unmarshal(data, claims); err != nil {
+		return nil, fmt.Errorf("edgecontext.HTTPIntrospector: malformed response: %w", err)
+	}
+	return claims, nil
+}
+
+// isJWT returns true if the token looks like a JWT in JWS compact
+// serialization, which has exactly 3 dot separated segments.
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// introspect validates an opaque token with the TokenIntrospector.
+func (impl *Impl) introspect(token string, now time.Time) (*AuthenticationToken, error) {
+	claims, err := impl.introspector.Introspect(context.Background(), token)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTimeClaims(claims, now); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
@@ -0,0 +1,164 @@
+package edgecontext_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/log"
+	"github.com/reddit/baseplate.go/secrets"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestTokenIntrospection(t *testing.T) {
+	const secret = "introspection-secret"
+
+	var calls int64
+	responses := map[string]map[string]interface{}{
+		"active": {
+			"active":    true,
+			"sub":       "t2_user",
+			"roles":     []string{"admin"},
+			"client_id": "client",
+			"scope":     "identity read",
+			"exp":       time.Now().Add(time.Hour).Unix(),
+		},
+		"inactive": {
+			"active": false,
+		},
+		"expired": {
+			"active": true,
+			"sub":    "t2_user",
+			"exp":    time.Now().Add(-time.Hour).Unix(),
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer "+secret {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resp, ok := responses[r.PostFormValue("token")]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	impl := newTestImpl(t, edgecontext.Config{
+		TokenCacheSize: 10,
+		TokenIntrospector: edgecontext.HTTPIntrospector{
+			URL:    server.URL,
+			Client: server.Client(),
+			Header: http.Header{
+				"Authorization": {"Bearer " + secret},
+			},
+		},
+	})
+
+	t.Run("active", func(t *testing.T) {
+		before := atomic.LoadInt64(&calls)
+		for i := 0; i < 3; i++ {
+			token, err := impl.ValidateToken("active")
+			if err != nil {
+				t.Fatalf("ValidateToken returned error: %v", err)
+			}
+			if got := token.Subject(); got != "t2_user" {
+				t.Errorf("Expected subject %q, got %q", "t2_user", got)
+			}
+			if !token.HasRole("admin") {
+				t.Errorf("Expected HasRole(%q) to be true, got false", "admin")
+			}
+			if !token.HasScope("read") {
+				t.Errorf("Expected HasScope(%q) to be true, got false", "read")
+			}
+			if token.OAuthClientID != "client" {
+				t.Errorf("Expected client id %q, got %q", "client", token.OAuthClientID)
+			}
+			if _, ok := token.RawClaims()["active"]; ok {
+				t.Error("Expected active member to be removed from the claims")
+			}
+		}
+		if got := atomic.LoadInt64(&calls) - before; got != 1 {
+			t.Errorf("Expected token to be introspected once, got %d", got)
+		}
+	})
+
+	for _, c := range []struct {
+		label    string
+		token    string
+		expected error
+	}{
+		{
+			label:    "inactive",
+			token:    "inactive",
+			expected: edgecontext.ErrInactiveToken,
+		},
+		{
+			label:    "expired",
+			token:    "expired",
+			expected: jwt.ErrTokenExpired,
+		},
+		{
+			// Tokens looking like JWT are never introspected.
+			label:    "jwt",
+			token:    "foo.bar.baz",
+			expected: jwt.ErrTokenMalformed,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			if _, err := impl.ValidateToken(c.token); !errors.Is(err, c.expected) {
+				t.Errorf("Expected error %v, got %v", c.expected, err)
+			}
+		})
+	}
+
+	t.Run("server-error", func(t *testing.T) {
+		if _, err := impl.ValidateToken("unknown"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestTokenIntrospectionWithoutJWTKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"active": true,
+			"sub":    "t2_user",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	impl := newTestImplWithSecrets(t, edgecontext.Config{
+		// The missing jwt keys are logged.
+		Logger:           log.NopWrapper,
+		PubKeySecretPath: "secret/authentication/missing-public-key",
+		TokenIntrospector: edgecontext.HTTPIntrospector{
+			URL:    server.URL,
+			Client: server.Client(),
+		},
+	}, map[string]secrets.GenericSecret{})
+
+	token, err := impl.ValidateToken("opaque")
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if got := token.Subject(); got != "t2_user" {
+		t.Errorf("Expected subject %q, got %q", "t2_user", got)
+	}
+	if _, err := impl.ValidateToken(validToken); !errors.Is(err, edgecontext.ErrNoPublicKeysLoaded) {
+		t.Errorf("Expected error %v for jwt token, got %v", edgecontext.ErrNoPublicKeysLoaded, err)
+	}
+}
@@ -0,0 +1,105 @@
+package edgecontext
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/secrets"
+)
+
+// IssuerConfig is the configuration of a trusted token issuer,
+// see Config.Issuers.
+type IssuerConfig struct {
+	// The iss claim of the tokens minted by the issuer.
+	Issuer string
+
+	// The path of the versioned secret in the secrets store holding the
+	// public keys of the issuer, in the same format as the default keys.
+	PubKeySecretPath string
+
+	// Optional, the signing methods accepted for the issuer,
+	// see Config.JWTAlgorithms.
+	JWTAlgorithms []string
+}
+
+// issuer is a trusted issuer configured by IssuerConfig.
+type issuer struct {
+	cfg    IssuerConfig
+	parser *jwt.Parser
+
+	// keysValue holds the *keysType loaded from cfg.PubKeySecretPath.
+	keysValue atomic.Value
+}
+
+func newIssuers(ctx context.Context, cfg Config) map[string]*issuer {
+	if len(cfg.Issuers) == 0 {
+		return nil
+	}
+	issuers := make(map[string]*issuer, len(cfg.Issuers))
+	for _, ic := range cfg.Issuers {
+		if ic.Issuer == "" || ic.PubKeySecretPath == "" {
+			cfg.Logger.Log(ctx, fmt.Sprintf("Issuer %q without public key secret path ignored.", ic.Issuer))
+			continue
+		}
+		issuers[ic.Issuer] = &issuer{
+			cfg: ic,
+			parser: newJWTParser(
+				supportedAlgs(ctx, cfg.Logger, ic.JWTAlgorithms, nil),
+				cfg.JWTParserOptions,
+			),
+		}
+	}
+	return issuers
+}
+
+// issuerPeeker decodes the jwt tokens without verifying them,
+// to find their issuers.
+var issuerPeeker = jwt.NewParser()
+
+// issuerOf returns the configured issuer of the jwt token,
+// or nil if it's not minted by any of them.
+func (impl *Impl) issuerOf(token string) *issuer {
+	if len(impl.issuers) == 0 {
+		return nil
+	}
+	var claims jwt.RegisteredClaims
+	if _, _, err := issuerPeeker.ParseUnverified(token, &claims); err != nil {
+		// Let the default path report the error.
+		return nil
+	}
+	return impl.issuers[claims.Issuer]
+}
+
+func (iss *issuer) parseJWT(impl *Impl, token string) (*AuthenticationToken, error) {
+	keys, ok := iss.keysValue.Load().(*keysType)
+	if !ok {
+		return nil, fmt.Errorf("%w: issuer %q", ErrNoPublicKeysLoaded, iss.cfg.Issuer)
+	}
+	return impl.parseJWT(iss.parser, keys, token)
+}
+
+func (impl *Impl) updateIssuerKeys(sec *secrets.Secrets) {
+	ctx := context.Background()
+	for _, iss := range impl.issuers {
+		versioned, err := sec.GetVersionedSecret(iss.cfg.PubKeySecretPath)
+		if err != nil {
+			impl.logger.Log(ctx, fmt.Sprintf(
+				"Failed to get secrets %q for issuer %q: %v",
+				iss.cfg.PubKeySecretPath,
+				iss.cfg.Issuer,
+				err,
+			))
+			continue
+		}
+		if keys := parseVersionedKeys(ctx, versioned, impl.logger); keys != nil {
+			keys.mergeAlgs(impl.keyAlgs)
+			old, _ := iss.keysValue.Load().(*keysType)
+			iss.keysValue.Store(keys)
+			if !keys.equal(old) {
+				impl.purgeTokenCache()
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package edgecontext_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/ecinterface"
+	"github.com/reddit/baseplate.go/log"
+	"github.com/reddit/baseplate.go/secrets"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestIssuers(t *testing.T) {
+	const (
+		newIssuer     = "https://auth.reddit.com"
+		newSecretPath = "secret/authentication/new-public-key"
+	)
+
+	legacyKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impl := newTestImplWithSecrets(t, edgecontext.Config{
+		// Accept ES256 for the default issuer as well,
+		// so the new key is rejected for not being a default key.
+		ExtraJWTAlgorithms: []string{"ES256"},
+		Issuers: []edgecontext.IssuerConfig{
+			{
+				Issuer:           newIssuer,
+				PubKeySecretPath: newSecretPath,
+				JWTAlgorithms:    []string{"ES256"},
+			},
+		},
+	}, map[string]secrets.GenericSecret{
+		secrets.JWTPubKeyPath: {
+			Type:    "versioned",
+			Current: encodePublicKey(t, &legacyKey.PublicKey),
+		},
+		newSecretPath: {
+			Type:    "versioned",
+			Current: encodePublicKey(t, &newKey.PublicKey),
+		},
+	})
+
+	sign := func(t *testing.T, method jwt.SigningMethod, key interface{}, iss string) string {
+		t.Helper()
+
+		signed, err := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+			Issuer:    iss,
+			Subject:   "t2_example",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	for _, c := range []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{
+			name:  "legacy",
+			token: sign(t, jwt.SigningMethodRS256, legacyKey, ""),
+		},
+		{
+			name:  "legacy-unknown-issuer",
+			token: sign(t, jwt.SigningMethodRS256, legacyKey, "https://example.com"),
+		},
+		{
+			name:  "new",
+			token: sign(t, jwt.SigningMethodES256, newKey, newIssuer),
+		},
+		{
+			name:  "new-issuer-legacy-key",
+			token: sign(t, jwt.SigningMethodRS256, legacyKey, newIssuer),
+			want:  jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:  "new-issuer-other-key",
+			token: sign(t, jwt.SigningMethodES256, otherKey, newIssuer),
+			want:  jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:  "new-key-without-issuer",
+			token: sign(t, jwt.SigningMethodES256, newKey, ""),
+			want:  jwt.ErrTokenUnverifiable,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			token, err := impl.ValidateToken(c.token)
+			if !errors.Is(err, c.want) {
+				t.Fatalf("error mismatch: want %v, got %v", c.want, err)
+			}
+			if err == nil && token.Subject() != "t2_example" {
+				t.Errorf("subject expected %q, got %q", "t2_example", token.Subject())
+			}
+		})
+	}
+}
+
+func TestIssuerKeysRotation(t *testing.T) {
+	const (
+		issuer     = "https://auth.reddit.com"
+		secretPath = "secret/authentication/new-public-key"
+	)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := func(key *ecdsa.PrivateKey) map[string]secrets.GenericSecret {
+		return map[string]secrets.GenericSecret{
+			secrets.JWTPubKeyPath: secrets.TestJWTPubKeySecret,
+			secretPath: {
+				Type:    "versioned",
+				Current: encodePublicKey(t, &key.PublicKey),
+			},
+		}
+	}
+
+	store, fw, err := secrets.NewTestSecrets(context.Background(), raw(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		store.Close()
+		ecinterface.Set(globalTestImpl)
+	})
+	impl := edgecontext.Init(edgecontext.Config{
+		Store:          store,
+		Logger:         log.TestWrapper(t),
+		TokenCacheSize: 10,
+		Issuers: []edgecontext.IssuerConfig{
+			{
+				Issuer:           issuer,
+				PubKeySecretPath: secretPath,
+				JWTAlgorithms:    []string{"ES256"},
+			},
+		},
+	})
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   "t2_example",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := impl.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Refreshing the same keys keeps the cached tokens.
+	if err := secrets.UpdateTestSecrets(fw, raw(key)); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := impl.ValidateToken(token); err != nil || got != cached {
+		t.Errorf("Expected the cached token %p, got %p, %v", cached, got, err)
+	}
+
+	if err := secrets.UpdateTestSecrets(fw, raw(otherKey)); err != nil {
+		t.Fatal(err)
+	}
+	// The cached token signed by the rotated out key is no longer valid.
+	if _, err := impl.ValidateToken(token); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("error mismatch: want %v, got %v", jwt.ErrTokenSignatureInvalid, err)
+	}
+}
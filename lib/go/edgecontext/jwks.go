@@ -0,0 +1,270 @@
+package edgecontext
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Default values of JWKSConfig.
+const (
+	DefaultJWKSRefreshInterval = 5 * time.Minute
+	DefaultJWKSTimeout         = 5 * time.Second
+	DefaultJWKSMaxBackoff      = time.Minute
+)
+
+// jwksMinBackoff is the first backoff after a failed JWKS refresh,
+// doubled on each consecutive failure up to JWKSConfig.MaxBackoff.
+const jwksMinBackoff = time.Second
+
+// maxJWKSSize is the max size of the JWKS response body.
+const maxJWKSSize = 1 << 20
+
+// JWKSConfig is the configuration of fetching the public keys for jwt tokens
+// from a JWK Set (RFC 7517 section 5) endpoint, see Config.JWKS.
+type JWKSConfig struct {
+	// The URL of the JWKS endpoint.
+	URL string
+
+	// Optional, the http client used to call the endpoint.
+	// When it's nil, http.DefaultClient will be used.
+	Client *http.Client
+
+	// Optional, the interval between refreshes, with +/-10% jitter.
+	// When it's non-positive, DefaultJWKSRefreshInterval will be used.
+	RefreshInterval time.Duration
+
+	// Optional, the timeout of each request to the endpoint.
+	// When it's non-positive, DefaultJWKSTimeout will be used.
+	Timeout time.Duration
+
+	// Optional, the max backoff between retries after failed refreshes,
+	// which starts at 1 second and doubles on each consecutive failure.
+	// When it's non-positive, DefaultJWKSMaxBackoff will be used.
+	MaxBackoff time.Duration
+}
+
+// jwks is the json format of a JWK Set.
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// jwksFetcher fetches the JWK Set configured by JWKSConfig.
+type jwksFetcher struct {
+	cfg JWKSConfig
+
+	// etag is the ETag of the last successful response,
+	// only accessed by the refresh goroutine after Init.
+	etag string
+}
+
+// fetch fetches the JWK Set.
+//
+// It returns nil set when the endpoint responded with 304 Not Modified.
+func (f *jwksFetcher) fetch(ctx context.Context) (*jwks, error) {
+	timeout := f.cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultJWKSTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.cfg.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if f.etag != "" {
+		req.Header.Set("If-None-Match", f.etag)
+	}
+
+	client := f.cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	switch resp.StatusCode {
+	default:
+		return nil, fmt.Errorf("unexpected http status %q", resp.Status)
+	case http.StatusNotModified:
+		return nil, nil
+	case http.StatusOK:
+	}
+
+	var set jwks
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("malformed response: %w", err)
+	}
+	f.etag = resp.Header.Get("ETag")
+	return &set, nil
+}
+
+// refreshJWKS fetches the JWK Set and stores the keys,
+// unless it's not modified.
+func (impl *Impl) refreshJWKS(ctx context.Context, f *jwksFetcher) error {
+	set, err := f.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("edgecontext: failed to fetch JWKS %q: %w", f.cfg.URL, err)
+	}
+	if set == nil {
+		impl.markKeysRefreshed()
+		return nil
+	}
+	keys := impl.parseJWKS(ctx, set)
+	if keys == nil {
+		return fmt.Errorf("edgecontext: no valid keys in JWKS %q", f.cfg.URL)
+	}
+	impl.storeKeys(keys)
+	return nil
+}
+
+// parseJWKS converts the signing keys in the JWK Set into keysType.
+func (impl *Impl) parseJWKS(ctx context.Context, set *jwks) *keysType {
+	keys := &keysType{
+		m: make(map[string]crypto.PublicKey, len(set.Keys)),
+	}
+	for i, k := range set.Keys {
+		if err := keys.addJWK(k); err != nil {
+			impl.logger.Log(ctx, fmt.Sprintf("Failed to parse JWKS key #%d %q: %v", i, k.Kid, err))
+		}
+	}
+	if len(keys.all) == 0 {
+		return nil
+	}
+	keys.mergeAlgs(impl.keyAlgs)
+	return keys
+}
+
+// addJWK adds the public key of k to the key set,
+// unless it's not a signing key.
+//
+// The key is identified by the kid member, or the fingerprint when absent,
+// and the alg member is enforced the same way as Config.KeyAlgorithms.
+func (kt *keysType) addJWK(k jwk) error {
+	if k.Use != "" && k.Use != "sig" {
+		return nil
+	}
+	key, err := k.publicKey()
+	if err != nil {
+		return err
+	}
+	kid := k.Kid
+	if kid == "" {
+		kid, _ = PublicKeyFingerprint(key)
+	}
+	if kid != "" {
+		kt.m[kid] = key
+		if supportedJWTAlgs[k.Alg] {
+			if kt.algs == nil {
+				kt.algs = make(map[string]string)
+			}
+			kt.algs[kid] = k.Alg
+		}
+	}
+	kt.all = append(kt.all, key)
+	kt.kids = append(kt.kids, kid)
+	return nil
+}
+
+// parseJWKJSON parses a JWK or a JWK Set.
+func parseJWKJSON(data []byte) ([]jwk, error) {
+	var set struct {
+		jwk
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	if set.Keys != nil {
+		return set.Keys, nil
+	}
+	return []jwk{set.jwk}, nil
+}
+
+// startJWKSRefresh fetches the JWK Set configured by cfg,
+// then keeps refreshing it in a background goroutine until impl is closed.
+func (impl *Impl) startJWKSRefresh(cfg JWKSConfig) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	impl.closers = append(impl.closers, func() {
+		cancel()
+		<-done
+	})
+
+	f := &jwksFetcher{cfg: cfg}
+	var failures int
+	if err := impl.refreshJWKS(ctx, f); err != nil {
+		impl.logger.Log(ctx, err.Error())
+		failures++
+	}
+	go func() {
+		defer close(done)
+		impl.runJWKSRefresh(ctx, f, failures)
+	}()
+}
+
+func (impl *Impl) runJWKSRefresh(ctx context.Context, f *jwksFetcher, failures int) {
+	interval := f.cfg.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultJWKSRefreshInterval
+	}
+	maxBackoff := f.cfg.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultJWKSMaxBackoff
+	}
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for {
+		wait := interval
+		if failures > 0 {
+			wait = jwksBackoff(failures, maxBackoff)
+		}
+		// +/-10% jitter, so the instances of a service don't refresh in lockstep.
+		if jitter := int64(wait / 5); jitter > 0 {
+			wait += time.Duration(rnd.Int63n(jitter)) - wait/10
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := impl.refreshJWKS(ctx, f); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			impl.logger.Log(ctx, err.Error())
+			failures++
+		} else {
+			failures = 0
+		}
+	}
+}
+
+// jwksBackoff returns the backoff after the given number of consecutive
+// failures.
+func jwksBackoff(failures int, max time.Duration) time.Duration {
+	backoff := jwksMinBackoff
+	for i := 1; i < failures && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
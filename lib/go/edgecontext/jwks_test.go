@@ -0,0 +1,184 @@
+package edgecontext_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/ecinterface"
+	"github.com/reddit/baseplate.go/log"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+// testJWKSServer serves a JWK Set with ETag support.
+type testJWKSServer struct {
+	mu      sync.Mutex
+	version int
+	keys    []map[string]string
+
+	requests    int64
+	notModified int64
+}
+
+func (s *testJWKSServer) setKeys(keys ...map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+	s.keys = keys
+}
+
+func (s *testJWKSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt64(&s.requests, 1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	etag := strconv.Quote(strconv.Itoa(s.version))
+	if r.Header.Get("If-None-Match") == etag {
+		atomic.AddInt64(&s.notModified, 1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+}
+
+func rsaJWK(t testing.TB, key *rsa.PublicKey, kid string) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwksServer := &testJWKSServer{}
+	jwksServer.setKeys(rsaJWK(t, &key1.PublicKey, "key-1"))
+	server := httptest.NewServer(jwksServer)
+	defer server.Close()
+
+	impl := edgecontext.Init(edgecontext.Config{
+		Logger: log.TestWrapper(t),
+		JWKS: edgecontext.JWKSConfig{
+			URL:             server.URL,
+			RefreshInterval: 10 * time.Millisecond,
+		},
+	})
+	t.Cleanup(func() {
+		impl.Close()
+		ecinterface.Set(globalTestImpl)
+	})
+
+	sign := func(t *testing.T, key *rsa.PrivateKey, kid string) string {
+		t.Helper()
+		token, err := edgecontext.NewSigner(key, kid).Sign(edgecontext.AuthenticationToken{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "t2_example",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	waitFor := func(t *testing.T, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatal("Timed out")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	if _, err := impl.ValidateToken(sign(t, key1, "key-1")); err != nil {
+		t.Fatalf("Token signed by key-1: %v", err)
+	}
+	// The alg member of the jwk is enforced.
+	token := jwt.NewWithClaims(jwt.SigningMethodPS256, jwt.RegisteredClaims{Subject: "t2_example"})
+	token.Header[edgecontext.JWTHeaderKeyID] = "key-1"
+	signed, err := token.SignedString(key1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := impl.ValidateToken(signed); !errors.Is(err, edgecontext.ErrWrongAlgorithm) {
+		t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrWrongAlgorithm, err)
+	}
+
+	t.Run("not-modified", func(t *testing.T) {
+		waitFor(t, func() bool {
+			return atomic.LoadInt64(&jwksServer.notModified) >= 2
+		})
+		if _, err := impl.ValidateToken(sign(t, key1, "key-1")); err != nil {
+			t.Errorf("Token signed by key-1: %v", err)
+		}
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		jwksServer.setKeys(rsaJWK(t, &key2.PublicKey, "key-2"))
+		token := sign(t, key2, "key-2")
+		waitFor(t, func() bool {
+			_, err := impl.ValidateToken(token)
+			return err == nil
+		})
+		if _, err := impl.ValidateToken(sign(t, key1, "key-1")); err == nil {
+			t.Error("Token signed by the rotated out key-1 is still valid")
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		impl.Close()
+		requests := atomic.LoadInt64(&jwksServer.requests)
+		time.Sleep(50 * time.Millisecond)
+		if got := atomic.LoadInt64(&jwksServer.requests); got != requests {
+			t.Errorf("JWKS fetched %d times after Close", got-requests)
+		}
+	})
+}
+
+func TestJWKSUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	impl := edgecontext.Init(edgecontext.Config{
+		Logger: log.NopWrapper,
+		JWKS: edgecontext.JWKSConfig{
+			URL: server.URL,
+		},
+	})
+	t.Cleanup(func() {
+		impl.Close()
+		ecinterface.Set(globalTestImpl)
+	})
+
+	if _, err := impl.ValidateToken(validToken); !errors.Is(err, edgecontext.ErrNoPublicKeysLoaded) {
+		t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrNoPublicKeysLoaded, err)
+	}
+}
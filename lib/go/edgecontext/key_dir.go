@@ -0,0 +1,167 @@
+package edgecontext
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/reddit/baseplate.go/secrets"
+	"gopkg.in/fsnotify.v1"
+)
+
+// DefaultKeyDirPollingInterval is the default value of
+// KeyDirConfig.PollingInterval.
+const DefaultKeyDirPollingInterval = 30 * time.Second
+
+// KeyDirConfig is the configuration of loading the public keys for jwt tokens
+// from a directory, see Config.KeyDir.
+//
+// Every regular file in the directory can contain one or more PEM encoded
+// public keys or x509 certificates, or a JWK or JWK Set in JSON.
+// Hidden files are ignored, which includes the "..data" links of the
+// directories mounted from Kubernetes ConfigMaps and Secrets.
+type KeyDirConfig struct {
+	// The path of the directory.
+	Path string
+
+	// Optional, the interval to re-scan the directory in addition to the file
+	// system notifications, which could be missed on some mounts.
+	// When it's zero, DefaultKeyDirPollingInterval will be used.
+	// When it's negative, the directory is only re-scanned on notifications.
+	PollingInterval time.Duration
+}
+
+// keyDirWatcher reloads the keys from KeyDirConfig.
+type keyDirWatcher struct {
+	cfg KeyDirConfig
+
+	// digest is the digest of the files of the last load,
+	// only accessed by the watching goroutine after Init.
+	digest [sha256.Size]byte
+}
+
+// readKeyDir reads the key files in the directory, and returns them with the
+// digest of their names and contents.
+func readKeyDir(dir string) ([]secrets.Secret, [sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, digest, err
+	}
+	h := sha256.New()
+	var all []secrets.Secret
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		// Use os.Stat instead of entry.Info to follow the symlinks.
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, digest, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, digest, err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(data))
+		h.Write(data)
+		all = append(all, splitPEMBlocks(bytes.TrimSpace(data))...)
+	}
+	copy(digest[:], h.Sum(nil))
+	return all, digest, nil
+}
+
+// reloadKeyDir reloads the keys from the directory,
+// unless the files are unchanged since the last successful load.
+func (impl *Impl) reloadKeyDir(ctx context.Context, w *keyDirWatcher) {
+	all, digest, err := readKeyDir(w.cfg.Path)
+	if err != nil {
+		impl.logger.Log(ctx, fmt.Sprintf("Failed to read key directory %q: %v", w.cfg.Path, err))
+		return
+	}
+	if digest == w.digest {
+		impl.markKeysRefreshed()
+		return
+	}
+
+	keys := parseKeys(ctx, all, w.cfg.Path, impl.logger)
+	if keys == nil {
+		// Keep the previous digest, so the broken files are retried (and
+		// the keys become stale) instead of being treated as unchanged.
+		return
+	}
+	keys.mergeAlgs(impl.keyAlgs)
+	impl.storeKeys(keys)
+	w.digest = digest
+}
+
+// startKeyDirWatcher loads the keys from the directory configured by cfg,
+// then keeps watching it in a background goroutine until impl is closed.
+func (impl *Impl) startKeyDirWatcher(cfg KeyDirConfig) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	impl.closers = append(impl.closers, func() {
+		cancel()
+		<-done
+	})
+
+	w := &keyDirWatcher{cfg: cfg}
+	impl.reloadKeyDir(ctx, w)
+
+	// The notifications are best effort, as the directory is still re-scanned
+	// periodically when they are unavailable.
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		impl.logger.Log(ctx, fmt.Sprintf("Failed to watch key directory %q: %v", cfg.Path, err))
+	} else if err := watcher.Add(cfg.Path); err != nil {
+		impl.logger.Log(ctx, fmt.Sprintf("Failed to watch key directory %q: %v", cfg.Path, err))
+		watcher.Close()
+		watcher = nil
+	}
+	go func() {
+		defer close(done)
+		impl.runKeyDirWatcher(ctx, w, watcher)
+	}()
+}
+
+func (impl *Impl) runKeyDirWatcher(ctx context.Context, w *keyDirWatcher, watcher *fsnotify.Watcher) {
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	if watcher != nil {
+		defer watcher.Close()
+		events = watcher.Events
+		errs = watcher.Errors
+	}
+	var tick <-chan time.Time
+	if interval := w.cfg.PollingInterval; interval >= 0 {
+		if interval == 0 {
+			interval = DefaultKeyDirPollingInterval
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-events:
+			impl.reloadKeyDir(ctx, w)
+		case err := <-errs:
+			impl.logger.Log(ctx, fmt.Sprintf("Error watching key directory %q: %v", w.cfg.Path, err))
+		case <-tick:
+			impl.reloadKeyDir(ctx, w)
+		}
+	}
+}
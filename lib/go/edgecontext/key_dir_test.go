@@ -0,0 +1,192 @@
+package edgecontext_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/ecinterface"
+	"github.com/reddit/baseplate.go/log"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+// writeConfigMapDir writes the files the same way as Kubernetes updates a
+// mounted ConfigMap: into a new hidden directory, which then atomically
+// replaces the "..data" link that the visible files link through.
+func writeConfigMapDir(t *testing.T, dir, version string, files map[string]string) {
+	t.Helper()
+	versionDir := filepath.Join(dir, "..ver_"+version)
+	if err := os.Mkdir(versionDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(versionDir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); err == nil {
+			continue
+		}
+		if err := os.Symlink(filepath.Join("..data", name), link); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(filepath.Base(versionDir), tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeyDir(t *testing.T) {
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(t *testing.T, key *rsa.PrivateKey) string {
+		t.Helper()
+		keyID, err := edgecontext.PublicKeyFingerprint(&key.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := edgecontext.NewSigner(key, keyID).Sign(edgecontext.AuthenticationToken{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "t2_example",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	for _, c := range []struct {
+		label    string
+		interval time.Duration
+	}{
+		{
+			label:    "polling",
+			interval: 10 * time.Millisecond,
+		},
+		{
+			label:    "notification",
+			interval: -1,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigMapDir(t, dir, "1", map[string]string{
+				"key.pem": encodePublicKey(t, &key1.PublicKey),
+			})
+
+			impl := edgecontext.Init(edgecontext.Config{
+				Logger: log.TestWrapper(t),
+				KeyDir: edgecontext.KeyDirConfig{
+					Path:            dir,
+					PollingInterval: c.interval,
+				},
+			})
+			t.Cleanup(func() {
+				impl.Close()
+				ecinterface.Set(globalTestImpl)
+			})
+
+			if _, err := impl.ValidateToken(sign(t, key1)); err != nil {
+				t.Fatalf("Token signed by key1: %v", err)
+			}
+
+			writeConfigMapDir(t, dir, "2", map[string]string{
+				"key.pem": encodePublicKey(t, &key2.PublicKey),
+			})
+			token := sign(t, key2)
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				if _, err := impl.ValidateToken(token); err == nil {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("Timed out waiting for the rotated key2")
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+			if _, err := impl.ValidateToken(sign(t, key1)); err == nil {
+				t.Error("Token signed by the rotated out key1 is still valid")
+			}
+		})
+	}
+}
+
+func TestKeyDirMissing(t *testing.T) {
+	impl := edgecontext.Init(edgecontext.Config{
+		Logger: log.NopWrapper,
+		KeyDir: edgecontext.KeyDirConfig{
+			Path: filepath.Join(t.TempDir(), "missing"),
+		},
+	})
+	t.Cleanup(func() {
+		impl.Close()
+		ecinterface.Set(globalTestImpl)
+	})
+
+	if _, err := impl.ValidateToken(validToken); !errors.Is(err, edgecontext.ErrNoPublicKeysLoaded) {
+		t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrNoPublicKeysLoaded, err)
+	}
+}
+
+func TestKeyDirBroken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	writeConfigMapDir(t, dir, "1", map[string]string{
+		"key.pem": encodePublicKey(t, &key.PublicKey),
+	})
+
+	impl := edgecontext.Init(edgecontext.Config{
+		// The broken key files are logged.
+		Logger:          log.NopWrapper,
+		MaxKeyStaleness: 50 * time.Millisecond,
+		KeyDir: edgecontext.KeyDirConfig{
+			Path:            dir,
+			PollingInterval: 5 * time.Millisecond,
+		},
+	})
+	t.Cleanup(func() {
+		impl.Close()
+		ecinterface.Set(globalTestImpl)
+	})
+	ctx := context.Background()
+	if err := impl.Healthy(ctx); err != nil {
+		t.Fatalf("Healthy: %v", err)
+	}
+
+	writeConfigMapDir(t, dir, "2", map[string]string{
+		"key.pem": "not a key",
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if err := impl.Healthy(ctx); errors.Is(err, edgecontext.ErrKeysStale) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the broken keys to become stale")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,76 @@
+package edgecontext
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/reddit/baseplate.go/secrets"
+)
+
+// LocalKeysConfig is the configuration of loading the public keys for jwt
+// tokens from local files or an environment variable, see Config.LocalKeys.
+//
+// The files and the environment variable can contain one or more PEM encoded
+// public keys or x509 certificates, or a JWK or JWK Set in JSON.
+type LocalKeysConfig struct {
+	// The paths of the key files.
+	Files []string
+
+	// The name of the environment variable containing the keys,
+	// e.g. "EDGECONTEXT_PUBLIC_KEYS".
+	EnvVar string
+}
+
+func (cfg LocalKeysConfig) configured() bool {
+	return len(cfg.Files) > 0 || cfg.EnvVar != ""
+}
+
+// loadLocalKeys loads the keys configured by cfg and stores them.
+func (impl *Impl) loadLocalKeys(cfg LocalKeysConfig) {
+	ctx := context.Background()
+	var all []secrets.Secret
+	for _, path := range cfg.Files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			impl.logger.Log(ctx, fmt.Sprintf("Failed to read key file %q: %v", path, err))
+			continue
+		}
+		all = append(all, splitPEMBlocks(data)...)
+	}
+	if cfg.EnvVar != "" {
+		if data := os.Getenv(cfg.EnvVar); data != "" {
+			all = append(all, splitPEMBlocks([]byte(data))...)
+		} else {
+			impl.logger.Log(ctx, fmt.Sprintf("Environment variable %q for keys is empty.", cfg.EnvVar))
+		}
+	}
+
+	keys := parseKeys(ctx, all, "local key files", impl.logger)
+	if keys != nil {
+		keys.mergeAlgs(impl.keyAlgs)
+		impl.storeKeys(keys)
+	}
+}
+
+// splitPEMBlocks splits data with multiple PEM blocks into separate secrets,
+// so they can be parsed by parseKeys.
+//
+// data is returned as-is when it's not PEM encoded, e.g. a JWK Set.
+func splitPEMBlocks(data []byte) []secrets.Secret {
+	var blocks []secrets.Secret
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, pem.EncodeToMemory(block))
+	}
+	if len(blocks) == 0 {
+		return []secrets.Secret{data}
+	}
+	return blocks
+}
@@ -0,0 +1,116 @@
+package edgecontext_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/ecinterface"
+	"github.com/reddit/baseplate.go/log"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestLocalKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A bundle of a public key and a certificate.
+	path := filepath.Join(t.TempDir(), "public-keys.pem")
+	bundle := encodePublicKey(t, &rsaKey.PublicKey) + encodeCertificate(t, &ecKey.PublicKey, ecKey)
+	if err := os.WriteFile(path, []byte(bundle), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	const envVar = "EDGECONTEXT_TEST_PUBLIC_KEYS"
+	t.Setenv(envVar, encodePublicKey(t, &envKey.PublicKey))
+
+	impl := edgecontext.Init(edgecontext.Config{
+		Logger:             log.TestWrapper(t),
+		ExtraJWTAlgorithms: []string{"ES256"},
+		LocalKeys: edgecontext.LocalKeysConfig{
+			Files:  []string{path},
+			EnvVar: envVar,
+		},
+	})
+	t.Cleanup(func() {
+		ecinterface.Set(globalTestImpl)
+	})
+
+	for _, c := range []struct {
+		name   string
+		method jwt.SigningMethod
+		pub    interface{}
+		key    interface{}
+	}{
+		{
+			name:   "file-public-key",
+			method: jwt.SigningMethodRS256,
+			pub:    &rsaKey.PublicKey,
+			key:    rsaKey,
+		},
+		{
+			name:   "file-certificate",
+			method: jwt.SigningMethodES256,
+			pub:    &ecKey.PublicKey,
+			key:    ecKey,
+		},
+		{
+			name:   "env",
+			method: jwt.SigningMethodRS256,
+			pub:    &envKey.PublicKey,
+			key:    envKey,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			keyID, err := edgecontext.PublicKeyFingerprint(c.pub)
+			if err != nil {
+				t.Fatal(err)
+			}
+			token := jwt.NewWithClaims(c.method, jwt.RegisteredClaims{
+				Subject:   "t2_example",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			})
+			token.Header[edgecontext.JWTHeaderKeyID] = keyID
+			signed, err := token.SignedString(c.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := impl.ValidateToken(signed); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestLocalKeysMissing(t *testing.T) {
+	impl := edgecontext.Init(edgecontext.Config{
+		Logger: log.NopWrapper,
+		LocalKeys: edgecontext.LocalKeysConfig{
+			Files: []string{filepath.Join(t.TempDir(), "missing.pem")},
+		},
+	})
+	t.Cleanup(func() {
+		ecinterface.Set(globalTestImpl)
+	})
+
+	if _, err := impl.ValidateToken(validToken); !errors.Is(err, edgecontext.ErrNoPublicKeysLoaded) {
+		t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrNoPublicKeysLoaded, err)
+	}
+}
@@ -1,8 +1,6 @@
 package edgecontext
 
 import (
-	"strings"
-
 	"github.com/reddit/baseplate.go/experiments"
 )
 
@@ -23,19 +21,13 @@ func (o OAuthClient) ID() string {
 //
 // For example, use:
 //
-//     if client.IsType("third_party")
+//     if client.IsType(edgecontext.OAuthClientTypeThirdParty)
 //
 // Instead of:
 //
-//     if !client.IsType("first_party")
+//     if !client.IsType(edgecontext.OAuthClientTypeFirstParty)
 func (o OAuthClient) IsType(types ...string) bool {
-	clientType := AuthenticationToken(o).OAuthClientType
-	for _, t := range types {
-		if clientType == strings.ToLower(t) {
-			return true
-		}
-	}
-	return false
+	return AuthenticationToken(o).IsOAuthClientType(types...)
 }
 
 // UpdateExperimentEvent updates the passed in experiment event with oauth
@@ -0,0 +1,314 @@
+package edgecontext
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/secrets"
+)
+
+// PASETOv4PublicPrefix is the prefix of PASETO v4 public tokens.
+//
+// ValidateToken validates tokens with this prefix as PASETO tokens instead of
+// JWT.
+const PASETOv4PublicPrefix = "v4.public."
+
+// DefaultPASETOPubKeySecretPath is the path of the versioned secret in the
+// secrets store holding the PEM encoded Ed25519 public keys for PASETO tokens,
+// used when Config.PASETOPubKeySecretPath is empty.
+//
+// It's optional.
+// When it's absent from the secrets store, all PASETO tokens are rejected
+// with ErrNoPASETOKeysLoaded.
+const DefaultPASETOPubKeySecretPath = "secret/authentication/paseto-public-key"
+
+// ErrNoPASETOKeysLoaded is an error returned by ValidateToken indicates that
+// a PASETO token is validated but no PASETO public keys are loaded from
+// secrets.
+var ErrNoPASETOKeysLoaded = errors.New("edgecontext.ValidateToken: no PASETO public keys loaded")
+
+// pasetoTimeClaims are the claims that are RFC 3339 strings in PASETO tokens,
+// but NumericDate in JWT.
+var pasetoTimeClaims = []string{"exp", "nbf", "iat"}
+
+// pasetoFooter is the optional JSON footer of PASETO tokens.
+type pasetoFooter struct {
+	// The fingerprint (see PublicKeyFingerprint) of the signing key.
+	KeyID string `json:"kid"`
+}
+
+func (impl *Impl) updatePASETOKeys(sec *secrets.Secrets) {
+	keys := &keysType{
+		m: make(map[string]crypto.PublicKey),
+	}
+	defer func() {
+		old, _ := impl.pasetoKeysValue.Load().(*keysType)
+		impl.pasetoKeysValue.Store(keys)
+		if !keys.equal(old) {
+			impl.purgeTokenCache()
+		}
+	}()
+
+	path := impl.pasetoPubKeySecretPath
+	if path == "" {
+		path = DefaultPASETOPubKeySecretPath
+	}
+	versioned, err := sec.GetVersionedSecret(path)
+	if err != nil {
+		var notFound secrets.SecretNotFoundError
+		if !errors.As(err, &notFound) {
+			impl.logger.Log(context.Background(), fmt.Sprintf(
+				"Failed to get secrets %q: %v",
+				path,
+				err,
+			))
+		}
+		return
+	}
+
+	for i, v := range versioned.GetAll() {
+		key, err := parseEd25519PublicKey([]byte(v))
+		if err != nil {
+			impl.logger.Log(context.Background(), fmt.Sprintf(
+				"Failed to parse PASETO key #%d: %v",
+				i,
+				err,
+			))
+			continue
+		}
+		fingerprint, _ := PublicKeyFingerprint(key)
+		if fingerprint != "" {
+			keys.m[fingerprint] = key
+		}
+		keys.all = append(keys.all, key)
+		keys.kids = append(keys.kids, fingerprint)
+	}
+	if len(keys.all) == 0 {
+		impl.logger.Log(context.Background(), "No valid PASETO keys in secrets store.")
+	}
+	keys.mergeAlgs(impl.keyAlgs)
+}
+
+// pasetoKeys returns the Ed25519 keys to verify a PASETO token with the kid
+// in its footer.
+//
+// The kid is handled the same way as getKey for jwt tokens,
+// including Config.StrictKeyID and Config.KeyAlgorithms,
+// except that all the usable keys are tried when falling back.
+func (kt *keysType) pasetoKeys(kid string, strict bool) ([]ed25519.PublicKey, error) {
+	method := jwt.SigningMethodEdDSA
+	key, err := kt.getKey(kid, method, true)
+	if err == nil {
+		return []ed25519.PublicKey{key.(ed25519.PublicKey)}, nil
+	}
+	if strict || errors.Is(err, ErrKeyAlgorithmMismatch) {
+		return nil, err
+	}
+
+	var keys []ed25519.PublicKey
+	for i, key := range kt.all {
+		if kt.allows(kt.kids[i], method) && keyMatchesMethod(key, method) {
+			keys = append(keys, key.(ed25519.PublicKey))
+		}
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoPASETOKeysLoaded
+	}
+	reason := keyIDFallbackMismatch
+	switch {
+	case kid == "":
+		reason = keyIDFallbackMissing
+	case kt.m[kid] == nil:
+		reason = keyIDFallbackUnknown
+	}
+	keyIDFallbacks.WithLabelValues(reason).Inc()
+	return keys, nil
+}
+
+func parseEd25519PublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("key must be PEM encoded")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("key is not an Ed25519 public key: %T", parsed)
+	}
+	return key, nil
+}
+
+// parsePASETO parses and validates a PASETO v4 public token.
+//
+// Same as JWT, the exp and nbf claims are validated if present,
+// and the claims of expired tokens are returned along with the error.
+// Tokens with the iss claim of a configured issuer are only verified with the
+// Ed25519 keys of that issuer.
+// Note that Config.JWTParserOptions and IssuerConfig.JWTAlgorithms are not
+// applied to PASETO tokens.
+func (impl *Impl) parsePASETO(token string, now time.Time) (*AuthenticationToken, error) {
+	body := token[len(PASETOv4PublicPrefix):]
+	var footer []byte
+	if i := strings.IndexByte(body, '.'); i >= 0 {
+		var err error
+		footer, err = base64.RawURLEncoding.DecodeString(body[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed paseto footer: %v", jwt.ErrTokenMalformed, err)
+		}
+		body = body[:i]
+	}
+	data, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed paseto payload: %v", jwt.ErrTokenMalformed, err)
+	}
+	if len(data) < ed25519.SignatureSize {
+		return nil, fmt.Errorf("%w: paseto payload too short", jwt.ErrTokenMalformed)
+	}
+	message := data[:len(data)-ed25519.SignatureSize]
+	sig := data[len(data)-ed25519.SignatureSize:]
+
+	// The claims are decoded before the verification to find the issuer,
+	// but not trusted until verified.
+	claims, err := decodePASETOClaims(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", jwt.ErrTokenMalformed, err)
+	}
+	var kid string
+	if len(footer) > 0 && footer[0] == '{' {
+		// A tampered footer fails the signature verification below instead.
+		var f pasetoFooter
+		if json.Unmarshal(footer, &f) == nil {
+			kid = f.KeyID
+		}
+	}
+	keySet, err := impl.pasetoKeySet(claims.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := keySet.pasetoKeys(kid, impl.strictKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := pasetoPAE([]byte(PASETOv4PublicPrefix), message, footer, nil)
+	verified := false
+	for _, key := range keys {
+		if ed25519.Verify(key, signed, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+	if err := validateTimeClaims(claims, now); errors.Is(err, jwt.ErrTokenExpired) {
+		// Verified but expired, see ValidateToken.
+		return claims, err
+	} else if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// pasetoKeySet returns the keys of the configured issuer iss,
+// or the keys from Config.PASETOPubKeySecretPath when iss is not configured.
+func (impl *Impl) pasetoKeySet(iss string) (*keysType, error) {
+	if issuer := impl.issuers[iss]; issuer != nil {
+		keys, ok := issuer.keysValue.Load().(*keysType)
+		if !ok {
+			return nil, fmt.Errorf("%w: issuer %q", ErrNoPublicKeysLoaded, iss)
+		}
+		return keys, nil
+	}
+	keys, ok := impl.pasetoKeysValue.Load().(*keysType)
+	if !ok || len(keys.all) == 0 {
+		return nil, ErrNoPASETOKeysLoaded
+	}
+	return keys, nil
+}
+
+// validateTimeClaims validates the exp and nbf claims of the non-JWT tokens,
+// if present.
+func validateTimeClaims(claims *AuthenticationToken, now time.Time) error {
+	// nbf is checked first, so jwt.ErrTokenExpired is only returned when all
+	// the other claims are valid.
+	if nbf := claims.RegisteredClaims.NotBefore; nbf != nil && now.Before(nbf.Time) {
+		return jwt.ErrTokenNotValidYet
+	}
+	if exp := claims.RegisteredClaims.ExpiresAt; exp != nil && !now.Before(exp.Time) {
+		return jwt.ErrTokenExpired
+	}
+	return nil
+}
+
+// decodePASETOClaims decodes the claims of a PASETO token into
+// AuthenticationToken,
+// converting the RFC 3339 time claims to NumericDate first.
+func decodePASETOClaims(message []byte) (*AuthenticationToken, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(message, &raw); err != nil {
+		return nil, err
+	}
+	for _, name := range pasetoTimeClaims {
+		v, ok := raw[name]
+		if !ok {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return nil, fmt.Errorf("%s claim should be a RFC 3339 string: %w", name, err)
+		}
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("%s claim should be a RFC 3339 string: %w", name, err)
+		}
+		raw[name] = json.RawMessage(strconv.FormatInt(ts.Unix(), 10))
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	claims := new(AuthenticationToken)
+	if err := json.Unmarshal(data, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// pasetoPAE implements the Pre-Authentication Encoding of PASETO.
+func pasetoPAE(pieces ...[]byte) []byte {
+	size := 8
+	for _, p := range pieces {
+		size += 8 + len(p)
+	}
+	buf := make([]byte, 0, size)
+	buf = appendLE64(buf, len(pieces))
+	for _, p := range pieces {
+		buf = appendLE64(buf, len(p))
+		buf = append(buf, p...)
+	}
+	return buf
+}
+
+// appendLE64 appends n as unsigned 64-bit little endian integer with the most
+// significant bit cleared, as required by PASETO.
+func appendLE64(buf []byte, n int) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(n)&^(1<<63))
+	return append(buf, b[:]...)
+}
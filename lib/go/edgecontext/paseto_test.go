@@ -0,0 +1,403 @@
+package edgecontext_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/ecinterface"
+	"github.com/reddit/baseplate.go/log"
+	"github.com/reddit/baseplate.go/secrets"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+// signPASETO signs a PASETO v4 public token with the claims and footer.
+func signPASETO(t testing.TB, key ed25519.PrivateKey, claims map[string]interface{}, footer string) string {
+	t.Helper()
+
+	message, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pae := func(pieces ...[]byte) []byte {
+		var buf []byte
+		le64 := func(n int) {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], uint64(n))
+			buf = append(buf, b[:]...)
+		}
+		le64(len(pieces))
+		for _, p := range pieces {
+			le64(len(p))
+			buf = append(buf, p...)
+		}
+		return buf
+	}
+	sig := ed25519.Sign(key, pae([]byte(edgecontext.PASETOv4PublicPrefix), message, []byte(footer), nil))
+	token := edgecontext.PASETOv4PublicPrefix + base64.RawURLEncoding.EncodeToString(append(message, sig...))
+	if footer != "" {
+		token += "." + base64.RawURLEncoding.EncodeToString([]byte(footer))
+	}
+	return token
+}
+
+func TestValidatePASETOToken(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Public key of the official PASETO test vector 4-S-1.
+	vectorPub, err := hex.DecodeString("1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	impl := newTestImplWithSecrets(t, edgecontext.Config{}, map[string]secrets.GenericSecret{
+		edgecontext.DefaultPASETOPubKeySecretPath: {
+			Type:     "versioned",
+			Current:  encodePublicKey(t, pub),
+			Previous: encodePublicKey(t, ed25519.PublicKey(vectorPub)),
+		},
+	})
+
+	now := time.Now()
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"sub":   "t2_user",
+			"roles": []string{"admin"},
+			"exp":   now.Add(time.Hour).Format(time.RFC3339),
+			"iat":   now.Format(time.RFC3339),
+		}
+	}
+
+	for _, c := range []struct {
+		label    string
+		token    string
+		expected error
+	}{
+		{
+			label: "valid",
+			token: signPASETO(t, key, validClaims(), ""),
+		},
+		{
+			label: "valid-with-footer",
+			token: signPASETO(t, key, validClaims(), `{"kid":"foo"}`),
+		},
+		{
+			label: "no-exp",
+			token: signPASETO(t, key, map[string]interface{}{
+				"sub":   "t2_user",
+				"roles": []string{"admin"},
+			}, ""),
+		},
+		{
+			label: "expired",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = now.Add(-time.Hour).Format(time.RFC3339)
+				return signPASETO(t, key, claims, "")
+			}(),
+			expected: jwt.ErrTokenExpired,
+		},
+		{
+			label: "not-valid-yet",
+			token: func() string {
+				claims := validClaims()
+				claims["nbf"] = now.Add(time.Hour).Format(time.RFC3339)
+				return signPASETO(t, key, claims, "")
+			}(),
+			expected: jwt.ErrTokenNotValidYet,
+		},
+		{
+			label: "numeric-exp",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = now.Add(time.Hour).Unix()
+				return signPASETO(t, key, claims, "")
+			}(),
+			expected: jwt.ErrTokenMalformed,
+		},
+		{
+			label:    "unknown-key",
+			token:    signPASETO(t, otherKey, validClaims(), ""),
+			expected: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			label: "tampered-footer",
+			token: signPASETO(t, key, validClaims(), `{"kid":"foo"}`) +
+				base64.RawURLEncoding.EncodeToString([]byte("bar")),
+			expected: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			label:    "malformed",
+			token:    edgecontext.PASETOv4PublicPrefix + "foo!",
+			expected: jwt.ErrTokenMalformed,
+		},
+		{
+			label:    "too-short",
+			token:    edgecontext.PASETOv4PublicPrefix + "Zm9v",
+			expected: jwt.ErrTokenMalformed,
+		},
+		{
+			// Official PASETO test vector 4-S-1, which expired in 2022.
+			label:    "test-vector",
+			token:    "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA",
+			expected: jwt.ErrTokenExpired,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			token, err := impl.ValidateToken(c.token)
+			if !errors.Is(err, c.expected) {
+				t.Fatalf("Expected error %v, got %v", c.expected, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := token.Subject(); got != "t2_user" {
+				t.Errorf("Expected subject %q, got %q", "t2_user", got)
+			}
+			if !token.HasRole("admin") {
+				t.Errorf("Expected HasRole(%q) to be true, got false", "admin")
+			}
+		})
+	}
+
+	t.Run("time-claims", func(t *testing.T) {
+		token, err := impl.ValidateToken(signPASETO(t, key, validClaims(), ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp, ok := token.ExpiresAt()
+		if !ok || !exp.Equal(now.Add(time.Hour).Truncate(time.Second)) {
+			t.Errorf("ExpiresAt got %v, %v, want %v", exp, ok, now.Add(time.Hour).Truncate(time.Second))
+		}
+	})
+}
+
+func TestValidatePASETOTokenNoKeys(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := signPASETO(t, key, map[string]interface{}{"sub": "t2_user"}, "")
+	if _, err := globalTestImpl.ValidateToken(token); !errors.Is(err, edgecontext.ErrNoPASETOKeysLoaded) {
+		t.Errorf("Expected ErrNoPASETOKeysLoaded, got %v", err)
+	}
+}
+
+func TestValidatePASETOTokenPolicies(t *testing.T) {
+	const (
+		path       = "secret/staging/authentication/paseto-public-key"
+		issuerPath = "secret/authentication/partner-public-key"
+		issuerName = "https://partner.example.com"
+	)
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID, err := edgecontext.PublicKeyFingerprint(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuerPub, issuerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := map[string]secrets.GenericSecret{
+		path: {
+			Type:    "versioned",
+			Current: encodePublicKey(t, pub),
+		},
+		issuerPath: {
+			Type:    "versioned",
+			Current: encodePublicKey(t, issuerPub),
+		},
+	}
+	claims := func(iss string) map[string]interface{} {
+		return map[string]interface{}{
+			"sub": "t2_user",
+			"iss": iss,
+			"exp": time.Now().Add(time.Hour).Format(time.RFC3339),
+		}
+	}
+	kidFooter := `{"kid":"` + keyID + `"}`
+
+	for _, c := range []struct {
+		label    string
+		cfg      edgecontext.Config
+		token    string
+		expected error
+	}{
+		{
+			label: "secret-path",
+			token: signPASETO(t, key, claims(""), ""),
+		},
+		{
+			label: "strict-kid",
+			cfg: edgecontext.Config{
+				StrictKeyID: true,
+			},
+			token: signPASETO(t, key, claims(""), kidFooter),
+		},
+		{
+			label: "strict-missing-kid",
+			cfg: edgecontext.Config{
+				StrictKeyID: true,
+			},
+			token:    signPASETO(t, key, claims(""), ""),
+			expected: edgecontext.ErrMissingKeyID,
+		},
+		{
+			label: "strict-unknown-kid",
+			cfg: edgecontext.Config{
+				StrictKeyID: true,
+			},
+			token:    signPASETO(t, key, claims(""), `{"kid":"SHA256:unknown"}`),
+			expected: edgecontext.ErrUnknownKeyID,
+		},
+		{
+			label: "key-algorithm-mismatch",
+			cfg: edgecontext.Config{
+				KeyAlgorithms: map[string]string{keyID: "RS256"},
+			},
+			token:    signPASETO(t, key, claims(""), kidFooter),
+			expected: edgecontext.ErrWrongAlgorithm,
+		},
+		{
+			label: "issuer",
+			cfg: edgecontext.Config{
+				Issuers: []edgecontext.IssuerConfig{{
+					Issuer:           issuerName,
+					PubKeySecretPath: issuerPath,
+				}},
+			},
+			token: signPASETO(t, issuerKey, claims(issuerName), ""),
+		},
+		{
+			label: "issuer-default-key",
+			cfg: edgecontext.Config{
+				Issuers: []edgecontext.IssuerConfig{{
+					Issuer:           issuerName,
+					PubKeySecretPath: issuerPath,
+				}},
+			},
+			token:    signPASETO(t, key, claims(issuerName), ""),
+			expected: edgecontext.ErrBadSignature,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			cfg := c.cfg
+			cfg.PASETOPubKeySecretPath = path
+			impl := newTestImplWithSecrets(t, cfg, raw)
+			if _, err := impl.ValidateToken(c.token); !errors.Is(err, c.expected) {
+				t.Errorf("Expected error %v, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidatePASETOTokenRotation(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, fw, err := secrets.NewTestSecrets(context.Background(), map[string]secrets.GenericSecret{
+		edgecontext.DefaultPASETOPubKeySecretPath: {
+			Type:    "versioned",
+			Current: encodePublicKey(t, pub),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		store.Close()
+		ecinterface.Set(globalTestImpl)
+	})
+	impl := edgecontext.Init(edgecontext.Config{
+		Store:          store,
+		Logger:         log.TestWrapper(t),
+		TokenCacheSize: 10,
+	})
+
+	token := signPASETO(t, key, map[string]interface{}{
+		"sub": "t2_user",
+		"exp": time.Now().Add(time.Hour).Format(time.RFC3339),
+	}, "")
+	cached, err := impl.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Refreshing the same keys keeps the cached tokens.
+	if err := secrets.UpdateTestSecrets(fw, map[string]secrets.GenericSecret{
+		edgecontext.DefaultPASETOPubKeySecretPath: {
+			Type:    "versioned",
+			Current: encodePublicKey(t, pub),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := impl.ValidateToken(token); err != nil || got != cached {
+		t.Errorf("Expected the cached token %p, got %p, %v", cached, got, err)
+	}
+
+	if err := secrets.UpdateTestSecrets(fw, map[string]secrets.GenericSecret{
+		edgecontext.DefaultPASETOPubKeySecretPath: {
+			Type:    "versioned",
+			Current: encodePublicKey(t, otherPub),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// The cached token signed by the removed key is no longer valid.
+	if _, err := impl.ValidateToken(token); !errors.Is(err, edgecontext.ErrBadSignature) {
+		t.Errorf("Expected ErrBadSignature, got %v", err)
+	}
+}
+
+func TestValidatePASETOTokenWithoutJWTKeys(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impl := newTestImplWithSecrets(t, edgecontext.Config{
+		// The missing jwt keys are logged.
+		Logger:           log.NopWrapper,
+		PubKeySecretPath: "secret/authentication/missing-public-key",
+	}, map[string]secrets.GenericSecret{
+		edgecontext.DefaultPASETOPubKeySecretPath: {
+			Type:    "versioned",
+			Current: encodePublicKey(t, pub),
+		},
+	})
+
+	token := signPASETO(t, key, map[string]interface{}{
+		"sub": "t2_user",
+		"exp": time.Now().Add(time.Hour).Format(time.RFC3339),
+	}, "")
+	if _, err := impl.ValidateToken(token); err != nil {
+		t.Errorf("Expected PASETO token to be valid, got %v", err)
+	}
+	if _, err := impl.ValidateToken(validToken); !errors.Is(err, edgecontext.ErrNoPublicKeysLoaded) {
+		t.Errorf("Expected error %v for jwt token, got %v", edgecontext.ErrNoPublicKeysLoaded, err)
+	}
+}
@@ -0,0 +1,229 @@
+package edgecontext
+
+import "errors"
+
+// ErrMalformedHeader is returned by RequestIDFromHeader and LoIDFromHeader
+// when the header is not a valid thrift binary payload.
+var ErrMalformedHeader = errors.New("edgecontext: malformed header")
+
+// Thrift field ids of the fields in the Request struct peeked by
+// RequestIDFromHeader and LoIDFromHeader.
+const (
+	loidFieldID      = 1
+	requestIDFieldID = 7
+)
+
+// Thrift binary protocol type ids.
+//
+// See https://github.com/apache/thrift/blob/master/doc/specs/thrift-binary-protocol.md
+const (
+	typeStop   = 0
+	typeBool   = 2
+	typeByte   = 3
+	typeDouble = 4
+	typeI16    = 6
+	typeI32    = 8
+	typeI64    = 10
+	typeString = 11
+	typeStruct = 12
+	typeMap    = 13
+	typeSet    = 14
+	typeList   = 15
+)
+
+// maxPeekDepth limits the nesting of structs and containers headerScanner
+// would skip over.
+const maxPeekDepth = 64
+
+// RequestIDFromHeader extracts only the readable request id from an edge
+// context header, without decoding the rest of the header.
+//
+// It's intended for logging and metrics middlewares that only need this single
+// field at very high throughput:
+// it does not allocate and does not validate the auth token.
+// It returns an empty string and nil error if the header is empty or does not
+// have a request id.
+func RequestIDFromHeader(header string) (string, error) {
+	return peekNestedString(header, requestIDFieldID)
+}
+
+// LoIDFromHeader is the same as RequestIDFromHeader,
+// but extracts the LoID instead.
+func LoIDFromHeader(header string) (string, error) {
+	return peekNestedString(header, loidFieldID)
+}
+
+// peekNestedString returns field 1 (a string) of the struct at fieldID of the
+// top level Request struct encoded in header.
+//
+// Both Loid and RequestId structs store the value we want in field 1.
+func peekNestedString(header string, fieldID int16) (string, error) {
+	if header == "" {
+		return "", nil
+	}
+	s := headerScanner{buf: header}
+	for {
+		typ, id, ok := s.fieldHeader()
+		if !ok {
+			return "", ErrMalformedHeader
+		}
+		if typ == typeStop {
+			return "", nil
+		}
+		if id != fieldID || typ != typeStruct {
+			if !s.skip(typ, 0) {
+				return "", ErrMalformedHeader
+			}
+			continue
+		}
+
+		for {
+			typ, id, ok := s.fieldHeader()
+			if !ok {
+				return "", ErrMalformedHeader
+			}
+			if typ == typeStop {
+				return "", nil
+			}
+			if id == 1 && typ == typeString {
+				v, ok := s.string()
+				if !ok {
+					return "", ErrMalformedHeader
+				}
+				return v, nil
+			}
+			if !s.skip(typ, 0) {
+				return "", ErrMalformedHeader
+			}
+		}
+	}
+}
+
+// headerScanner is a minimal, allocation free reader of thrift binary protocol
+// payloads.
+//
+// The strings returned by it are substrings of buf.
+type headerScanner struct {
+	buf string
+}
+
+func (s *headerScanner) next(n int) (string, bool) {
+	if n < 0 || n > len(s.buf) {
+		return "", false
+	}
+	v := s.buf[:n]
+	s.buf = s.buf[n:]
+	return v, true
+}
+
+func (s *headerScanner) byte() (byte, bool) {
+	v, ok := s.next(1)
+	if !ok {
+		return 0, false
+	}
+	return v[0], true
+}
+
+func (s *headerScanner) i32() (int32, bool) {
+	v, ok := s.next(4)
+	if !ok {
+		return 0, false
+	}
+	return int32(uint32(v[0])<<24 | uint32(v[1])<<16 | uint32(v[2])<<8 | uint32(v[3])), true
+}
+
+func (s *headerScanner) string() (string, bool) {
+	n, ok := s.i32()
+	if !ok {
+		return "", false
+	}
+	return s.next(int(n))
+}
+
+// fieldHeader reads the type and the id of the next field.
+//
+// For the stop field, id is always 0.
+func (s *headerScanner) fieldHeader() (typ byte, id int16, ok bool) {
+	typ, ok = s.byte()
+	if !ok || typ == typeStop {
+		return typ, 0, ok
+	}
+	v, ok := s.next(2)
+	if !ok {
+		return 0, 0, false
+	}
+	return typ, int16(uint16(v[0])<<8 | uint16(v[1])), true
+}
+
+// skip skips over a value of type typ.
+func (s *headerScanner) skip(typ byte, depth int) bool {
+	if depth > maxPeekDepth {
+		return false
+	}
+	switch typ {
+	default:
+		return false
+	case typeBool, typeByte:
+		_, ok := s.next(1)
+		return ok
+	case typeI16:
+		_, ok := s.next(2)
+		return ok
+	case typeI32:
+		_, ok := s.next(4)
+		return ok
+	case typeDouble, typeI64:
+		_, ok := s.next(8)
+		return ok
+	case typeString:
+		_, ok := s.string()
+		return ok
+	case typeStruct:
+		for {
+			typ, _, ok := s.fieldHeader()
+			if !ok {
+				return false
+			}
+			if typ == typeStop {
+				return true
+			}
+			if !s.skip(typ, depth+1) {
+				return false
+			}
+		}
+	case typeMap:
+		ktype, ok := s.byte()
+		if !ok {
+			return false
+		}
+		vtype, ok := s.byte()
+		if !ok {
+			return false
+		}
+		n, ok := s.i32()
+		if !ok || n < 0 {
+			return false
+		}
+		for i := int32(0); i < n; i++ {
+			if !s.skip(ktype, depth+1) || !s.skip(vtype, depth+1) {
+				return false
+			}
+		}
+		return true
+	case typeSet, typeList:
+		etype, ok := s.byte()
+		if !ok {
+			return false
+		}
+		n, ok := s.i32()
+		if !ok || n < 0 {
+			return false
+		}
+		for i := int32(0); i < n; i++ {
+			if !s.skip(etype, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,61 @@
+package edgecontext_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestRequestIDFromHeader(t *testing.T) {
+	for _, c := range []struct {
+		label     string
+		header    string
+		requestID string
+		loid      string
+		err       error
+	}{
+		{
+			label:     "valid-auth",
+			header:    headerWithValidAuth,
+			requestID: expectedRequestID,
+			loid:      expectedLoID,
+		},
+		{
+			label:  "no-request-id",
+			header: headerWithNoAuth,
+			loid:   expectedLoID,
+		},
+		{
+			label:     "readable-request-id",
+			header:    headerWithReadableRequestID,
+			requestID: expectedRequestID,
+		},
+		{
+			label: "empty",
+		},
+		{
+			label:  "truncated",
+			header: "\x0c\x00\x01\x0b\x00\x01\x00\x00",
+			err:    edgecontext.ErrMalformedHeader,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			requestID, err := edgecontext.RequestIDFromHeader(c.header)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if requestID != c.requestID {
+				t.Errorf("Expected request id %q, got %q", c.requestID, requestID)
+			}
+
+			loid, err := edgecontext.LoIDFromHeader(c.header)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Expected error %v, got %v", c.err, err)
+			}
+			if loid != c.loid {
+				t.Errorf("Expected loid %q, got %q", c.loid, loid)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package edgecontext
+
+// ReferringSurface is the product surface that initiated the request.
+//
+// Attribution and ranking services can use it instead of ad-hoc query
+// parameters.
+type ReferringSurface string
+
+// ReferringSurface values.
+const (
+	ReferringSurfaceHomeFeed         ReferringSurface = "home_feed"
+	ReferringSurfacePushNotification ReferringSurface = "push_notification"
+	ReferringSurfaceEmail            ReferringSurface = "email"
+	ReferringSurfaceEmbed            ReferringSurface = "embed"
+
+	// The request was initiated by a surface without its own constant.
+	ReferringSurfaceOther ReferringSurface = "other"
+)
+
+// IsKnown returns true if s is one of the ReferringSurface constants.
+func (s ReferringSurface) IsKnown() bool {
+	switch s {
+	case ReferringSurfaceHomeFeed, ReferringSurfacePushNotification, ReferringSurfaceEmail, ReferringSurfaceEmbed, ReferringSurfaceOther:
+		return true
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/reddit/baseplate.go/experiments"
@@ -14,13 +15,24 @@ import (
 type EdgeRequestContext struct {
 	impl *Impl
 
-	// header and raw should always be set during initialization
-	header string
-	raw    NewArgs
+	// header should always be set during initialization,
+	// unless derived is true, in which case it will be serialized from raw on
+	// first use.
+	// Always use Header() instead of accessing header directly.
+	derived    bool
+	headerOnce sync.Once
+	header     string
 
-	// token will be validated on first use
-	tokenOnce sync.Once
-	token     *AuthenticationToken
+	// raw should always be set during initialization, unless lazy is true,
+	// in which case it will be decoded from header on first use.
+	// Always use args() instead of accessing raw directly.
+	lazy    bool
+	rawOnce sync.Once
+	raw     NewArgs
+
+	// tokens will be validated on first use
+	token        lazyToken
+	serviceToken lazyServiceToken
 
 	// ctx is only used in error logging in AuthToken and UpdateExperimentEvent
 	// functions.
@@ -40,23 +52,100 @@ func (e *EdgeRequestContext) getCtx() context.Context {
 	return context.Background()
 }
 
+// args returns the decoded fields of this edge request context,
+// decoding the header first if it's not decoded yet.
+func (e *EdgeRequestContext) args() *NewArgs {
+	if e.lazy {
+		e.rawOnce.Do(func() {
+			raw, err := e.impl.parseHeader(e.getCtx(), e.header, false)
+			if err != nil {
+				e.impl.logger.Log(e.getCtx(), "Failed to parse edge context header: "+err.Error())
+				return
+			}
+			e.raw = raw
+		})
+	}
+	return &e.raw
+}
+
 // AuthToken either validates the raw auth token and cache it,
 // or return the cached token.
 //
 // If the validation failed, the error will be logged.
 func (e *EdgeRequestContext) AuthToken() *AuthenticationToken {
-	e.tokenOnce.Do(func() {
-		if token, err := e.impl.ValidateToken(e.raw.AuthToken); err != nil {
+	token, _ := e.ValidatedAuthToken()
+	return token
+}
+
+// ValidatedAuthToken is like AuthToken,
+// but also returns the error if the validation failed.
+//
+// The raw auth token is validated at most once per EdgeRequestContext,
+// concurrent and subsequent calls return the cached token and error.
+// When there's no auth token in the request, the error is ErrEmptyToken.
+func (e *EdgeRequestContext) ValidatedAuthToken() (*AuthenticationToken, error) {
+	return e.token.get(e, e.args().AuthToken)
+}
+
+// ServiceAuthToken is like AuthToken,
+// but for the auth token of the service initiating the request on behalf of
+// the user.
+func (e *EdgeRequestContext) ServiceAuthToken() *ServiceAuthenticationToken {
+	token, _ := e.ValidatedServiceAuthToken()
+	return token
+}
+
+// ValidatedServiceAuthToken is like ValidatedAuthToken,
+// but for the auth token of the service initiating the request on behalf of
+// the user, validated by Impl.ValidateServiceToken.
+func (e *EdgeRequestContext) ValidatedServiceAuthToken() (*ServiceAuthenticationToken, error) {
+	return e.serviceToken.get(e, e.args().ServiceAuthToken)
+}
+
+// lazyToken validates a raw auth token at most once.
+type lazyToken struct {
+	once  sync.Once
+	token *AuthenticationToken
+	err   error
+}
+
+func (t *lazyToken) get(e *EdgeRequestContext, raw string) (*AuthenticationToken, error) {
+	t.once.Do(func() {
+		token, err := e.impl.ValidateToken(raw)
+		if err != nil {
 			// empty jwt token is considered "normal", no need to spam them in logs.
 			if !errors.Is(err, ErrEmptyToken) {
 				e.impl.logger.Log(e.getCtx(), "token validation failed: "+err.Error())
 			}
-			e.token = nil
+			t.err = err
 		} else {
-			e.token = token
+			t.token = token
 		}
 	})
-	return e.token
+	return t.token, t.err
+}
+
+// lazyServiceToken is like lazyToken, but for service tokens.
+type lazyServiceToken struct {
+	once  sync.Once
+	token *ServiceAuthenticationToken
+	err   error
+}
+
+func (t *lazyServiceToken) get(e *EdgeRequestContext, raw string) (*ServiceAuthenticationToken, error) {
+	t.once.Do(func() {
+		token, err := e.impl.ValidateServiceToken(raw)
+		if err != nil {
+			// empty jwt token is considered "normal", no need to spam them in logs.
+			if !errors.Is(err, ErrEmptyToken) {
+				e.impl.logger.Log(e.getCtx(), "service token validation failed: "+err.Error())
+			}
+			t.err = err
+		} else {
+			t.token = token
+		}
+	})
+	return t.token, t.err
 }
 
 // Header returns the raw, underlying edge request context header that was
@@ -64,18 +153,119 @@ func (e *EdgeRequestContext) AuthToken() *AuthenticationToken {
 //
 // This is not really intended to be used directly but to allow us to propogate
 // the header between services.
+//
+// For an EdgeRequestContext created by Derive,
+// the header is serialized on the first call.
 func (e *EdgeRequestContext) Header() string {
+	if e.derived {
+		e.headerOnce.Do(func() {
+			header, err := e.impl.serialize(e.getCtx(), e.raw)
+			if err != nil {
+				e.impl.logger.Log(e.getCtx(), "Failed to serialize edge context header: "+err.Error())
+				return
+			}
+			e.header = header
+		})
+	}
 	return e.header
 }
 
+// Derive creates a modified copy of this EdgeRequestContext.
+//
+// update is called with a copy of the fields of e to make the modifications,
+// e itself is never changed.
+// The modified fields go through the same validations as New,
+// but unlike New, the header of the returned copy is only serialized when its
+// Header method is called.
+//
+// For example, to add a locale:
+//
+//	derived, err := ec.Derive(func(args *edgecontext.NewArgs) {
+//	  args.LocaleCode = "en_US"
+//	})
+func (e *EdgeRequestContext) Derive(update func(args *NewArgs)) (*EdgeRequestContext, error) {
+	args := e.args().clone()
+	update(&args)
+	if err := args.validate(); err != nil {
+		return nil, err
+	}
+	return &EdgeRequestContext{
+		impl:    e.impl,
+		derived: true,
+		raw:     args,
+		ctx:     e.ctx,
+	}, nil
+}
+
+// clone returns a deep copy of args,
+// so modifying the maps, slices and Consent of it doesn't change args.
+func (args NewArgs) clone() NewArgs {
+	args.AcceptedLocales = append([]string(nil), args.AcceptedLocales...)
+	args.Hops = append([]string(nil), args.Hops...)
+	args.FeatureFlagOverrides = copyStringMap(args.FeatureFlagOverrides)
+	args.Baggage = copyStringMap(args.Baggage)
+	if args.Consent != nil {
+		consent := *args.Consent
+		args.Consent = &consent
+	}
+	return args
+}
+
+// IsLoggedIn returns true if this request has a valid auth token of a logged
+// in user.
+//
+// It's a shortcut to e.User().IsLoggedIn().
+func (e *EdgeRequestContext) IsLoggedIn() bool {
+	return e.User().IsLoggedIn()
+}
+
+// UserID resolves the effective user id of this request.
+//
+// The precedence is:
+//
+//  1. The subject of the validated auth token, if the user is logged in.
+//  2. The LoID from the header.
+//  3. The LoID from the validated auth token.
+//
+// source tells which one is used, it's UserIDSourceNone and id is empty when
+// none of them is available.
+func (e *EdgeRequestContext) UserID() (id string, source UserIDSource) {
+	return e.User().resolveID()
+}
+
+// IsServiceRequest returns true if this request has a valid auth token of a
+// service instead of a user,
+// in which case internal APIs should apply service ACLs instead of user
+// authorization.
+//
+// Use Service to get the name of the service.
+func (e *EdgeRequestContext) IsServiceRequest() bool {
+	token := e.AuthToken()
+	return token != nil && token.IsService()
+}
+
 // SessionID returns the session id of this request.
 func (e *EdgeRequestContext) SessionID() string {
-	return e.raw.SessionID
+	return e.args().SessionID
 }
 
 // DeviceID returns the device id of this request.
 func (e *EdgeRequestContext) DeviceID() string {
-	return e.raw.DeviceID
+	return e.args().DeviceID
+}
+
+// Session returns the info about the session of this request.
+func (e *EdgeRequestContext) Session() Session {
+	return Session{
+		raw: e.args(),
+	}
+}
+
+// Device returns the info about the device making this request.
+func (e *EdgeRequestContext) Device() Device {
+	return Device{
+		raw: e.args(),
+	}
 }
 
 // User returns the info about the user of this request.
@@ -88,18 +278,223 @@ func (e *EdgeRequestContext) User() User {
 // CountryCode returns the two-character ISO 3166-1 country code where the
 // request orginated from.
 func (e *EdgeRequestContext) CountryCode() string {
-	return e.raw.CountryCode
+	return e.args().CountryCode
+}
+
+// FeatureFlagOverrides returns the per-request feature flag overrides set by
+// the edge, keyed by feature flag name.
+//
+// The returned map should be treated as read-only.
+func (e *EdgeRequestContext) FeatureFlagOverrides() map[string]string {
+	return e.args().FeatureFlagOverrides
+}
+
+// FeatureFlagOverride returns the override of the named feature flag.
+//
+// ok will be false if the feature flag is not overridden in this request.
+func (e *EdgeRequestContext) FeatureFlagOverride(name string) (value string, ok bool) {
+	value, ok = e.args().FeatureFlagOverrides[name]
+	return
+}
+
+// Consent returns the privacy consents given by the user.
+//
+// ok will be false if the edge did not record the consents,
+// in which case services should treat all the consents as not given.
+func (e *EdgeRequestContext) Consent() (consent Consent, ok bool) {
+	if c := e.args().Consent; c != nil {
+		return *c, true
+	}
+	return
+}
+
+// ComplianceRegion returns the data protection regime that applies to this
+// request, as computed by the edge.
+//
+// It's empty when the edge did not compute it,
+// and could be a value not in the ComplianceRegion constants if the header was
+// created by a newer version of this library.
+func (e *EdgeRequestContext) ComplianceRegion() ComplianceRegion {
+	return e.args().ComplianceRegion
+}
+
+// WorkloadIdentity returns the SPIFFE ID of the calling workload.
+//
+// ok will be false for user traffic,
+// and true for requests initiated by a service, e.g. backfills and internal
+// jobs.
+func (e *EdgeRequestContext) WorkloadIdentity() (id string, ok bool) {
+	id = e.args().WorkloadIdentity
+	return id, id != ""
+}
+
+// TenantID returns the id of the tenant the request is served for.
+//
+// It's empty in single-tenant deployments.
+func (e *EdgeRequestContext) TenantID() string {
+	return e.args().TenantID
+}
+
+// ReferringSurface returns the product surface that initiated the request.
+//
+// It's empty when the edge did not record it,
+// and could be a value not in the ReferringSurface constants if the header was
+// created by a newer version of this library.
+func (e *EdgeRequestContext) ReferringSurface() ReferringSurface {
+	return e.args().ReferringSurface
+}
+
+// Deadline returns the absolute client-facing deadline of this request,
+// set by the edge.
+//
+// ok will be false if the edge did not set a deadline.
+func (e *EdgeRequestContext) Deadline() (deadline time.Time, ok bool) {
+	deadline = e.args().Deadline
+	return deadline, !deadline.IsZero()
+}
+
+// ContextWithDeadline returns a copy of ctx that's canceled when the
+// client-facing deadline of this request is reached,
+// so that work that can no longer make it back to the client can be stopped.
+//
+// If the request does not have a deadline, or ctx already has an earlier
+// deadline, the returned context only gets canceled when ctx is done or cancel
+// is called.
+func (e *EdgeRequestContext) ContextWithDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := e.Deadline(); ok {
+		return context.WithDeadline(ctx, deadline)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Baggage returns the generic metadata set by the edge.
+//
+// The returned map should be treated as read-only.
+func (e *EdgeRequestContext) Baggage() map[string]string {
+	return e.args().Baggage
+}
+
+// BaggageItem returns the value of key in the baggage.
+//
+// ok will be false if key is not in the baggage.
+func (e *EdgeRequestContext) BaggageItem(key string) (value string, ok bool) {
+	value, ok = e.args().Baggage[key]
+	return
+}
+
+// Traffic returns the classification of the traffic this request belongs to.
+func (e *EdgeRequestContext) Traffic() Traffic {
+	return Traffic{
+		raw: e.args(),
+	}
+}
+
+// Hops returns the names of the services that forwarded this edge context,
+// with the most recent hops last.
+//
+// The returned slice should be treated as read-only.
+func (e *EdgeRequestContext) Hops() []string {
+	return e.args().Hops
+}
+
+// Geolocation returns the info about the geographic location of the client.
+func (e *EdgeRequestContext) Geolocation() Geolocation {
+	return Geolocation{
+		raw: e.args(),
+	}
 }
 
 // LocaleCode returns the IETF language code for the client
 func (e *EdgeRequestContext) LocaleCode() string {
-	return e.raw.LocaleCode
+	return e.args().LocaleCode
+}
+
+// AcceptedLocales returns the full list of IETF language codes accepted by the
+// client, in order of preference.
+//
+// The returned slice should be treated as read-only.
+func (e *EdgeRequestContext) AcceptedLocales() []string {
+	return e.args().AcceptedLocales
+}
+
+// Timezone returns the IANA time zone database name of the client's time zone,
+// e.g. America/New_York.
+func (e *EdgeRequestContext) Timezone() string {
+	return e.args().Timezone
+}
+
+// Location loads the time.Location of the client's time zone.
+//
+// ok will be false if the request does not have a time zone,
+// or the time zone is unknown to the time zone database of this host.
+func (e *EdgeRequestContext) Location() (loc *time.Location, ok bool) {
+	tz := e.Timezone()
+	if tz == "" {
+		return nil, false
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, false
+	}
+	return loc, true
+}
+
+// ClientIP returns the IP address of the client, as seen by the edge.
+func (e *EdgeRequestContext) ClientIP() string {
+	return e.args().ClientIP
+}
+
+// UserAgent returns the User-Agent of the client, as seen by the edge.
+func (e *EdgeRequestContext) UserAgent() string {
+	return e.args().UserAgent
+}
+
+// ClientVersion returns the version of the client application.
+func (e *EdgeRequestContext) ClientVersion() ClientVersion {
+	return e.args().ClientVersion
+}
+
+// Platform returns the platform of the client.
+//
+// It could be a value not in the Platform constants if the header was created
+// by a newer version of this library.
+func (e *EdgeRequestContext) Platform() Platform {
+	return e.args().Platform
+}
+
+// IsInternalNetwork returns true if the request originates from the corporate
+// network (VPN) or an employee device, as seen by the edge.
+//
+// It can be used to gate staging features and admin tooling.
+func (e *EdgeRequestContext) IsInternalNetwork() bool {
+	return e.args().InternalNetwork
+}
+
+// ClientASN returns the autonomous system number of the client IP, as computed
+// by the edge.
+//
+// ok will be false if the ASN is unknown.
+func (e *EdgeRequestContext) ClientASN() (asn uint32, ok bool) {
+	asn = e.args().ClientASN
+	return asn, asn != 0
+}
+
+// IsAnonymizingProxy returns true if the edge determined the client IP belongs
+// to a VPN or an anonymizing proxy.
+func (e *EdgeRequestContext) IsAnonymizingProxy() bool {
+	return e.args().AnonymizingProxy
+}
+
+// LimitAdTracking returns true if the client asked to limit ad tracking,
+// normalized by the edge from the platform-specific signals.
+func (e *EdgeRequestContext) LimitAdTracking() bool {
+	return e.args().LimitAdTracking
 }
 
 // OriginService returns the info about the origin of this request.
 func (e *EdgeRequestContext) OriginService() OriginService {
 	return OriginService{
-		raw: e.raw,
+		raw: e.args(),
 	}
 }
 
@@ -162,7 +557,7 @@ func (e *EdgeRequestContext) UpdateExperimentEvent(ee *experiments.ExperimentEve
 
 // OriginService holds metadata about the origin of the request.
 type OriginService struct {
-	raw NewArgs
+	raw *NewArgs
 }
 
 // Name returns the name of the service that serves as the origin of the request.
@@ -170,7 +565,201 @@ func (os OriginService) Name() string {
 	return os.raw.OriginServiceName
 }
 
+// Version returns the version of the origin service.
+func (os OriginService) Version() string {
+	return os.raw.OriginServiceVersion
+}
+
+// DeployID returns the id of the deployment of the origin service.
+func (os OriginService) DeployID() string {
+	return os.raw.OriginServiceDeployID
+}
+
+// EdgePOP returns the edge POP or region that terminated the client
+// connection, e.g. "sjc1".
+func (os OriginService) EdgePOP() string {
+	return os.raw.EdgePOP
+}
+
+// Session holds the info about the session of the request.
+type Session struct {
+	raw *NewArgs
+}
+
+// ID returns the session id, same as EdgeRequestContext.SessionID.
+func (s Session) ID() string {
+	return s.raw.SessionID
+}
+
+// CreatedAt returns the time the session was created.
+//
+// ok will be false if the edge did not record it.
+func (s Session) CreatedAt() (ts time.Time, ok bool) {
+	ts = s.raw.SessionCreatedAt
+	return ts, !ts.IsZero()
+}
+
+// Type returns the type of the session.
+//
+// It's empty when the edge did not record it,
+// and could be a value not in the SessionType constants if the header was
+// created by a newer version of this library.
+func (s Session) Type() SessionType {
+	return s.raw.SessionType
+}
+
+// ElevatedUntil returns the time the step-up authentication of the session
+// expires.
+//
+// ok will be false if the session did not complete step-up authentication.
+func (s Session) ElevatedUntil() (ts time.Time, ok bool) {
+	ts = s.raw.SessionElevatedUntil
+	return ts, !ts.IsZero()
+}
+
+// UsedMFA returns true if the session was authenticated with multi-factor
+// authentication.
+func (s Session) UsedMFA() bool {
+	return s.raw.SessionMFA
+}
+
+// AuthMethod returns how the user authenticated.
+//
+// It's empty when the edge did not record it,
+// and could be a value not in the AuthMethod constants if the header was
+// created by a newer version of this library.
+func (s Session) AuthMethod() AuthMethod {
+	return s.raw.AuthMethod
+}
+
+// IsElevated returns true if the session completed step-up authentication and
+// it's not expired yet.
+func (s Session) IsElevated() bool {
+	ts, ok := s.ElevatedUntil()
+	return ok && time.Now().Before(ts)
+}
+
+// Device holds the info about the device making the request.
+type Device struct {
+	raw *NewArgs
+}
+
+// ID returns the device id, same as EdgeRequestContext.DeviceID.
+func (d Device) ID() string {
+	return d.raw.DeviceID
+}
+
+// Model returns the model of the device, e.g. "iPhone14,2".
+func (d Device) Model() string {
+	return d.raw.DeviceModel
+}
+
+// OSName returns the name of the operating system of the device, e.g. "iOS".
+func (d Device) OSName() string {
+	return d.raw.DeviceOSName
+}
+
+// OSVersion returns the version of the operating system of the device,
+// e.g. "16.4.1".
+func (d Device) OSVersion() string {
+	return d.raw.DeviceOSVersion
+}
+
+// FormFactor returns the coarse form factor of the device.
+//
+// It's empty when the edge did not record it,
+// and could be a value not in the FormFactor constants if the header was
+// created by a newer version of this library.
+func (d Device) FormFactor() FormFactor {
+	return d.raw.FormFactor
+}
+
+// AttestationVerdict returns the verdict of the mobile device attestation
+// evaluated at the edge.
+//
+// It's empty when the edge did not evaluate it,
+// and could be a value not in the AttestationVerdict constants if the header
+// was created by a newer version of this library.
+func (d Device) AttestationVerdict() AttestationVerdict {
+	return d.raw.AttestationVerdict
+}
+
+// Traffic holds the classification of the traffic a request belongs to.
+type Traffic struct {
+	raw *NewArgs
+}
+
+// IsShadow returns true if the request is replayed or shadowed (dark launches,
+// traffic mirroring).
+//
+// Services should suppress side effects like notifications and billing for
+// shadow requests.
+func (t Traffic) IsShadow() bool {
+	return t.raw.Shadow
+}
+
+// IsSynthetic returns true if the request is generated by a synthetic probe or
+// a load test.
+//
+// Metrics pipelines should exclude synthetic requests.
+func (t Traffic) IsSynthetic() bool {
+	return t.raw.Synthetic
+}
+
+// IsCanary returns true if the request belongs to the canary (early rollout)
+// cohort chosen by the edge.
+//
+// Services should route canary requests to their canary code paths,
+// so that the same request takes canary code paths in every service.
+func (t Traffic) IsCanary() bool {
+	return t.raw.Canary
+}
+
+// Geolocation holds the info about the geographic location of the client.
+type Geolocation struct {
+	raw *NewArgs
+}
+
+// CountryCode returns the two-character ISO 3166-1 country code where the
+// request orginated from.
+func (g Geolocation) CountryCode() string {
+	return g.raw.CountryCode
+}
+
+// SubdivisionCode returns the ISO 3166-2 subdivision code (e.g. state or
+// province) where the request originated from, e.g. US-CA.
+func (g Geolocation) SubdivisionCode() string {
+	return g.raw.SubdivisionCode
+}
+
+// City returns the name of the city where the request originated from.
+func (g Geolocation) City() string {
+	return g.raw.City
+}
+
+// MetroCode returns the Nielsen DMA code where the request originated from.
+//
+// ok will be false if the request does not have a metro code.
+func (g Geolocation) MetroCode() (code int32, ok bool) {
+	return g.raw.MetroCode, g.raw.MetroCode != 0
+}
+
+// Provenance returns how the country code was determined.
+//
+// It's empty when the edge did not record it,
+// and could be a value not in the GeoProvenance constants if the header was
+// created by a newer version of this library.
+func (g Geolocation) Provenance() GeoProvenance {
+	return g.raw.GeoProvenance
+}
+
 // RequestID is the id of this request.
 func (e *EdgeRequestContext) RequestID() string {
-	return e.raw.RequestID
+	return e.args().RequestID
+}
+
+// TraceID returns the W3C Trace Context trace id of the distributed trace this
+// request belongs to.
+func (e *EdgeRequestContext) TraceID() string {
+	return e.args().TraceID
 }
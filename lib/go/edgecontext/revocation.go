@@ -0,0 +1,41 @@
+package edgecontext
+
+import (
+	"errors"
+)
+
+// ErrTokenRevoked is an error returned by ValidateToken indicates that the
+// RevocationChecker reported the token as revoked.
+var ErrTokenRevoked = errors.New("edgecontext.ValidateToken: token is revoked")
+
+// RevocationChecker checks whether a token is revoked before its natural
+// expiry, e.g. by force-logout or compromised token events.
+//
+// It's invoked after the token is validated, on every ValidateToken call,
+// so it should be backed by a local deny-list instead of a remote call.
+type RevocationChecker interface {
+	// IsRevoked returns true if the token is revoked.
+	//
+	// Implementations usually check the jti (token.ID) against revoked
+	// tokens, and the issued at time (token.IssuedAt) against the force-logout
+	// time of the subject.
+	IsRevoked(token *AuthenticationToken) bool
+}
+
+// RevocationCheckerFunc is an adapter to allow the use of ordinary functions
+// as RevocationChecker.
+type RevocationCheckerFunc func(token *AuthenticationToken) bool
+
+var _ RevocationChecker = RevocationCheckerFunc(nil)
+
+// IsRevoked implements RevocationChecker.
+func (f RevocationCheckerFunc) IsRevoked(token *AuthenticationToken) bool {
+	return f(token)
+}
+
+func (impl *Impl) checkRevocation(token *AuthenticationToken) (*AuthenticationToken, error) {
+	if impl.revocationChecker != nil && impl.revocationChecker.IsRevoked(token) {
+		return nil, ErrTokenRevoked
+	}
+	return token, nil
+}
@@ -0,0 +1,113 @@
+package edgecontext_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+// denyList is a RevocationChecker for tests.
+type denyList struct {
+	lock sync.Mutex
+	// revoked jti
+	jti map[string]bool
+	// force-logout time of subjects
+	logout map[string]time.Time
+}
+
+func (d *denyList) IsRevoked(token *edgecontext.AuthenticationToken) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.jti[token.ID] {
+		return true
+	}
+	if ts, ok := d.logout[token.Subject()]; ok && token.IssuedAt != nil {
+		return !token.IssuedAt.After(ts)
+	}
+	return false
+}
+
+func (d *denyList) revoke(jti string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.jti[jti] = true
+}
+
+func (d *denyList) forceLogout(subject string, ts time.Time) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.logout[subject] = ts
+}
+
+func TestRevocationChecker(t *testing.T) {
+	deny := &denyList{
+		jti:    make(map[string]bool),
+		logout: make(map[string]time.Time),
+	}
+	key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{
+		TokenCacheSize:    10,
+		RevocationChecker: deny,
+	})
+	signer := edgecontext.NewSigner(key, keyID)
+
+	now := time.Now()
+	sign := func(t *testing.T, jti, subject string, issuedAt time.Time) string {
+		t.Helper()
+
+		signed, err := signer.Sign(edgecontext.AuthenticationToken{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        jti,
+				Subject:   subject,
+				IssuedAt:  jwt.NewNumericDate(issuedAt),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	t.Run("jti", func(t *testing.T) {
+		token := sign(t, "jti-1", "t2_foo", now)
+		if _, err := impl.ValidateToken(token); err != nil {
+			t.Fatalf("ValidateToken returned error: %v", err)
+		}
+		deny.revoke("jti-1")
+		// The token is already in the cache.
+		if _, err := impl.ValidateToken(token); !errors.Is(err, edgecontext.ErrTokenRevoked) {
+			t.Errorf("Expected ErrTokenRevoked, got %v", err)
+		}
+		if _, err := impl.ValidateToken(sign(t, "jti-2", "t2_foo", now)); err != nil {
+			t.Errorf("Expected other tokens to be valid, got %v", err)
+		}
+	})
+
+	t.Run("force-logout", func(t *testing.T) {
+		old := sign(t, "jti-3", "t2_bar", now.Add(-time.Minute))
+		deny.forceLogout("t2_bar", now.Add(-time.Second))
+		if _, err := impl.ValidateToken(old); !errors.Is(err, edgecontext.ErrTokenRevoked) {
+			t.Errorf("Expected ErrTokenRevoked, got %v", err)
+		}
+		if _, err := impl.ValidateToken(sign(t, "jti-4", "t2_bar", now)); err != nil {
+			t.Errorf("Expected tokens issued after force-logout to be valid, got %v", err)
+		}
+	})
+}
+
+func TestRevocationCheckerFunc(t *testing.T) {
+	checker := edgecontext.RevocationCheckerFunc(func(token *edgecontext.AuthenticationToken) bool {
+		return token.ID == "revoked"
+	})
+	if !checker.IsRevoked(&edgecontext.AuthenticationToken{RegisteredClaims: jwt.RegisteredClaims{ID: "revoked"}}) {
+		t.Error("Expected token to be revoked")
+	}
+	if checker.IsRevoked(&edgecontext.AuthenticationToken{}) {
+		t.Error("Expected token not to be revoked")
+	}
+}
@@ -0,0 +1,43 @@
+package edgecontext
+
+import (
+	"strings"
+)
+
+// Well-known roles of AuthenticationToken.Roles.
+const (
+	RoleAdmin          = "admin"
+	RoleEmployee       = "employee"
+	RoleModeratorTools = "moderator-tools"
+)
+
+// NormalizeRoles returns the normalized copy of roles:
+// lowercased, with leading and trailing whitespaces trimmed,
+// and empty and duplicated roles removed.
+//
+// The order of the first occurrences are kept.
+func NormalizeRoles(roles []string) []string {
+	if len(roles) == 0 {
+		return nil
+	}
+	normalized := make([]string, 0, len(roles))
+	for _, role := range roles {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if role == "" || containsString(normalized, role) {
+			continue
+		}
+		normalized = append(normalized, role)
+	}
+	return normalized
+}
+
+// containsString is used instead of a set by NormalizeRoles,
+// as in most cases the roles slice would be quite small.
+func containsString(s []string, target string) bool {
+	for _, v := range s {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
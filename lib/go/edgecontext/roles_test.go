@@ -0,0 +1,45 @@
+package edgecontext_test
+
+import (
+	"testing"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestNormalizeRoles(t *testing.T) {
+	for _, c := range []struct {
+		label    string
+		roles    []string
+		expected []string
+	}{
+		{
+			label: "nil",
+		},
+		{
+			label:    "normalized",
+			roles:    []string{edgecontext.RoleAdmin, edgecontext.RoleEmployee},
+			expected: []string{edgecontext.RoleAdmin, edgecontext.RoleEmployee},
+		},
+		{
+			label:    "case-and-whitespace",
+			roles:    []string{" Admin", "EMPLOYEE\t"},
+			expected: []string{edgecontext.RoleAdmin, edgecontext.RoleEmployee},
+		},
+		{
+			label:    "duplicates",
+			roles:    []string{"moderator-tools", "admin", "Moderator-Tools", "", " "},
+			expected: []string{edgecontext.RoleModeratorTools, edgecontext.RoleAdmin},
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			original := append([]string(nil), c.roles...)
+			got := edgecontext.NormalizeRoles(c.roles)
+			if !stringSlicesEqual(got, c.expected) {
+				t.Errorf("Expected %q, got %q", c.expected, got)
+			}
+			if !stringSlicesEqual(c.roles, original) {
+				t.Errorf("Expected input to be unchanged, got %q", c.roles)
+			}
+		})
+	}
+}
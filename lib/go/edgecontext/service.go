@@ -1,9 +1,5 @@
 package edgecontext
 
-import (
-	"strings"
-)
-
 const servicePrefix = "service/"
 
 // A Service wraps AuthenticationToken and provides info about an authenticated
@@ -15,9 +11,9 @@ type Service AuthenticationToken
 // If it's not coming from an authenticated service,
 // ("", false) will be returned.
 func (s Service) Name() (name string, ok bool) {
-	subject := AuthenticationToken(s).Subject()
-	if strings.HasPrefix(subject, servicePrefix) {
-		return subject[len(servicePrefix):], true
+	token := AuthenticationToken(s)
+	if !token.IsService() {
+		return
 	}
-	return
+	return token.Subject()[len(servicePrefix):], true
 }
@@ -0,0 +1,70 @@
+package edgecontext
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrMissingServiceName is an error returned by ValidateServiceToken indicates
+// that the token does not have the service name claim.
+var ErrMissingServiceName = errors.New("edgecontext.ValidateServiceToken: missing service name")
+
+// ServiceAuthenticationToken defines the json format of the authentication
+// token issued to a service, as opposed to AuthenticationToken for users.
+type ServiceAuthenticationToken struct {
+	jwt.RegisteredClaims
+
+	// ServiceName is the name of the service the token is issued to.
+	ServiceName string `json:"service_name"`
+
+	// Environment is the environment the service is running in,
+	// for example "production" or "staging".
+	Environment string `json:"environment,omitempty"`
+
+	// AllowedAudiences are the names of the services the token is allowed to
+	// be used to talk to.
+	AllowedAudiences []string `json:"allowed_audiences,omitempty"`
+}
+
+// AllowsAudience returns true if the token is allowed to be used to talk to
+// the given service.
+func (t *ServiceAuthenticationToken) AllowsAudience(service string) bool {
+	return containsString(t.AllowedAudiences, service)
+}
+
+// ValidateServiceToken parses and validates a service jwt token, and return
+// the decoded ServiceAuthenticationToken.
+//
+// The token goes through the same validations as ValidateToken,
+// including the policies in Config (e.g. TokenTypeHeader, Audiences, Issuers,
+// RequiredClaims and RevocationChecker) and the token cache.
+func (impl *Impl) ValidateServiceToken(token string) (*ServiceAuthenticationToken, error) {
+	claims, err := impl.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return newServiceAuthenticationToken(claims)
+}
+
+// newServiceAuthenticationToken converts the claims validated by
+// ValidateToken into ServiceAuthenticationToken.
+func newServiceAuthenticationToken(claims *AuthenticationToken) (*ServiceAuthenticationToken, error) {
+	t := &ServiceAuthenticationToken{
+		RegisteredClaims: claims.RegisteredClaims,
+	}
+	t.ServiceName, _ = claims.StringClaim("service_name")
+	if t.ServiceName == "" {
+		return nil, ErrMissingServiceName
+	}
+	t.Environment, _ = claims.StringClaim("environment")
+	if audiences, ok := claims.claims["allowed_audiences"].([]interface{}); ok {
+		t.AllowedAudiences = make([]string, 0, len(audiences))
+		for _, aud := range audiences {
+			if s, ok := aud.(string); ok {
+				t.AllowedAudiences = append(t.AllowedAudiences, s)
+			}
+		}
+	}
+	return t, nil
+}
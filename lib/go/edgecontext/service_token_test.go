@@ -0,0 +1,126 @@
+package edgecontext_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestValidateServiceToken(t *testing.T) {
+	key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{})
+	signer := edgecontext.NewSigner(key, keyID)
+
+	expiresAt := jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+	t.Run("valid", func(t *testing.T) {
+		signed, err := signer.SignService(edgecontext.ServiceAuthenticationToken{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: expiresAt,
+			},
+			ServiceName:      "foo",
+			Environment:      "production",
+			AllowedAudiences: []string{"bar", "baz"},
+		})
+		if err != nil {
+			t.Fatalf("SignService returned error: %v", err)
+		}
+		token, err := impl.ValidateServiceToken(signed)
+		if err != nil {
+			t.Fatalf("ValidateServiceToken returned error: %v", err)
+		}
+		if token.ServiceName != "foo" {
+			t.Errorf("ServiceName got %q, want %q", token.ServiceName, "foo")
+		}
+		if token.Environment != "production" {
+			t.Errorf("Environment got %q, want %q", token.Environment, "production")
+		}
+		if !token.AllowsAudience("baz") {
+			t.Errorf("Expected AllowsAudience(%q) to be true, got false", "baz")
+		}
+		if token.AllowsAudience("qux") {
+			t.Errorf("Expected AllowsAudience(%q) to be false, got true", "qux")
+		}
+	})
+
+	t.Run("missing-service-name", func(t *testing.T) {
+		signed, err := signer.SignService(edgecontext.ServiceAuthenticationToken{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: expiresAt,
+			},
+		})
+		if err != nil {
+			t.Fatalf("SignService returned error: %v", err)
+		}
+		if _, err := impl.ValidateServiceToken(signed); !errors.Is(err, edgecontext.ErrMissingServiceName) {
+			t.Errorf("Expected ErrMissingServiceName, got %v", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed, err := signer.SignService(edgecontext.ServiceAuthenticationToken{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+			},
+			ServiceName: "foo",
+		})
+		if err != nil {
+			t.Fatalf("SignService returned error: %v", err)
+		}
+		if _, err := impl.ValidateServiceToken(signed); !errors.Is(err, jwt.ErrTokenExpired) {
+			t.Errorf("Expected jwt.ErrTokenExpired, got %v", err)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if _, err := impl.ValidateServiceToken(""); !errors.Is(err, edgecontext.ErrEmptyToken) {
+			t.Errorf("Expected ErrEmptyToken, got %v", err)
+		}
+	})
+}
+
+func TestValidateServiceTokenPolicies(t *testing.T) {
+	sign := func(t *testing.T, signer *edgecontext.Signer, audience ...string) string {
+		t.Helper()
+		signed, err := signer.SignService(edgecontext.ServiceAuthenticationToken{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "revoked",
+				Audience:  audience,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+			ServiceName: "foo",
+		})
+		if err != nil {
+			t.Fatalf("SignService returned error: %v", err)
+		}
+		return signed
+	}
+
+	t.Run("revoked", func(t *testing.T) {
+		key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{
+			RevocationChecker: edgecontext.RevocationCheckerFunc(func(token *edgecontext.AuthenticationToken) bool {
+				return token.ID == "revoked"
+			}),
+		})
+		signed := sign(t, edgecontext.NewSigner(key, keyID))
+		if _, err := impl.ValidateServiceToken(signed); !errors.Is(err, edgecontext.ErrTokenRevoked) {
+			t.Errorf("Expected ErrTokenRevoked, got %v", err)
+		}
+	})
+
+	t.Run("audiences", func(t *testing.T) {
+		key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{
+			Audiences: []string{"bar"},
+		})
+		signer := edgecontext.NewSigner(key, keyID)
+		if _, err := impl.ValidateServiceToken(sign(t, signer, "baz")); !errors.Is(err, jwt.ErrTokenInvalidAudience) {
+			t.Errorf("Expected jwt.ErrTokenInvalidAudience, got %v", err)
+		}
+		if _, err := impl.ValidateServiceToken(sign(t, signer, "bar")); err != nil {
+			t.Errorf("ValidateServiceToken returned error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package edgecontext
+
+// SessionType is the type of the session of the request.
+type SessionType string
+
+// SessionType values.
+const (
+	// The session is tracked by a web browser cookie.
+	SessionTypeWebCookie SessionType = "cookie"
+
+	// The session belongs to a native mobile app.
+	SessionTypeMobile SessionType = "mobile"
+
+	// The session belongs to an API client.
+	SessionTypeAPI SessionType = "api"
+)
+
+// IsKnown returns true if t is one of the SessionType constants.
+func (t SessionType) IsKnown() bool {
+	switch t {
+	case SessionTypeWebCookie, SessionTypeMobile, SessionTypeAPI:
+		return true
+	}
+	return false
+}
+
+// AuthMethod is how the user of the request authenticated.
+type AuthMethod string
+
+// AuthMethod values.
+const (
+	AuthMethodPassword     AuthMethod = "password"
+	AuthMethodGoogleSSO    AuthMethod = "google"
+	AuthMethodAppleSSO     AuthMethod = "apple"
+	AuthMethodMagicLink    AuthMethod = "magic_link"
+	AuthMethodRefreshToken AuthMethod = "refresh"
+)
+
+// IsKnown returns true if m is one of the AuthMethod constants.
+func (m AuthMethod) IsKnown() bool {
+	switch m {
+	case AuthMethodPassword, AuthMethodGoogleSSO, AuthMethodAppleSSO, AuthMethodMagicLink, AuthMethodRefreshToken:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package edgecontext
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrNoSigningKey is returned by Signer.Sign when the Signer does not have a
+// private key.
+var ErrNoSigningKey = errors.New("edgecontext: no signing key")
+
+// Signer mints RS256 signed auth tokens with AuthenticationToken claims,
+// that can be validated by Impl.ValidateToken with the corresponding public
+// key.
+//
+// It's intended for the auth edge service and integration tests.
+// Signers created by NewInsecureDevSigner mint HS256 tokens for dev mode
+// instead.
+type Signer struct {
+	method jwt.SigningMethod
+	key    crypto.PrivateKey
+	keyID  string
+}
+
+// NewSigner creates a Signer with the private key.
+//
+// keyID is optional.
+// When it's non-empty, it's set as the JWTHeaderKeyID header of the minted
+// tokens, which should be the fingerprint of the corresponding public key
+// (see RSAPublicKeyFingerprint).
+func NewSigner(key *rsa.PrivateKey, keyID string) *Signer {
+	s := &Signer{
+		method: jwt.GetSigningMethod(jwtAlg),
+		keyID:  keyID,
+	}
+	if key != nil {
+		s.key = key
+	}
+	return s
+}
+
+// NewInsecureDevSigner creates a Signer minting HS256 tokens with the shared
+// secret, to be validated in dev mode (see Config.InsecureDevModeHMACSecret).
+//
+// It's only intended for local development.
+func NewInsecureDevSigner(secret []byte) *Signer {
+	s := &Signer{
+		method: jwt.SigningMethodHS256,
+	}
+	if len(secret) > 0 {
+		s.key = secret
+	}
+	return s
+}
+
+// Sign mints a signed token with the given claims.
+func (s *Signer) Sign(claims AuthenticationToken) (string, error) {
+	return s.sign(claims)
+}
+
+// SignService mints a signed service token with the given claims,
+// that can be validated by Impl.ValidateServiceToken.
+func (s *Signer) SignService(claims ServiceAuthenticationToken) (string, error) {
+	return s.sign(claims)
+}
+
+func (s *Signer) sign(claims jwt.Claims) (string, error) {
+	if s == nil || s.key == nil {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.keyID != "" {
+		token.Header[JWTHeaderKeyID] = s.keyID
+	}
+	return token.SignedString(s.key)
+}
@@ -0,0 +1,82 @@
+package edgecontext_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/secrets"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+// newSignerTestImpl generates a new private key,
+// and initializes a new Impl with cfg trusting its public key.
+//
+// It returns the private key, the key id of it, and the Impl.
+func newSignerTestImpl(t testing.TB, cfg edgecontext.Config) (*rsa.PrivateKey, string, *edgecontext.Impl) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impl := newTestImplWithSecrets(t, cfg, map[string]secrets.GenericSecret{
+		secrets.JWTPubKeyPath: {
+			Type:    "versioned",
+			Current: encodePublicKey(t, &key.PublicKey),
+		},
+	})
+	keyID, err := edgecontext.RSAPublicKeyFingerprint(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, keyID, impl
+}
+
+func TestSigner(t *testing.T) {
+	key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{})
+
+	claims := edgecontext.AuthenticationToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "t2_example",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: []string{"admin"},
+	}
+
+	for _, c := range []struct {
+		label string
+		keyID string
+	}{
+		{label: "with-key-id", keyID: keyID},
+		{label: "without-key-id"},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			signed, err := edgecontext.NewSigner(key, c.keyID).Sign(claims)
+			if err != nil {
+				t.Fatalf("Sign returned error: %v", err)
+			}
+			token, err := impl.ValidateToken(signed)
+			if err != nil {
+				t.Fatalf("ValidateToken returned error: %v", err)
+			}
+			if got, want := token.Subject(), claims.RegisteredClaims.Subject; got != want {
+				t.Errorf("Subject() got %q, want %q", got, want)
+			}
+			if !token.HasRole("admin") {
+				t.Errorf("Expected HasRole(%q) to be true, got false", "admin")
+			}
+		})
+	}
+
+	t.Run("no-key", func(t *testing.T) {
+		_, err := edgecontext.NewSigner(nil, "").Sign(claims)
+		if !errors.Is(err, edgecontext.ErrNoSigningKey) {
+			t.Errorf("Expected ErrNoSigningKey, got %v", err)
+		}
+	})
+}
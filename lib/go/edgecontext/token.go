@@ -1,15 +1,26 @@
 package edgecontext
 
 import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/reddit/baseplate.go/timebp"
 )
 
 // AuthenticationToken defines the json format of the authentication token.
+//
+// Both the v1 schema, with user attributes as top level claims,
+// and the v2 schema, with user attributes nested under the "user" claim,
+// are supported transparently:
+// the user attributes of v2 tokens are decoded into the same fields.
 type AuthenticationToken struct {
 	jwt.RegisteredClaims
 
-	// NOTE: Subject field is in StandardClaims.
+	// NOTE: Subject and ExpiresAt fields are in RegisteredClaims.
 
 	Roles []string `json:"roles,omitempty"`
 
@@ -21,9 +32,397 @@ type AuthenticationToken struct {
 		ID        string                      `json:"id,omitempty"`
 		CreatedAt timebp.TimestampMillisecond `json:"created_ms,omitempty"`
 	} `json:"loid,omitempty"`
+
+	// Confirmation is the "cnf" claim (RFC 7800), binding the token to the
+	// device or the DPoP key it's issued to.
+	// It's nil for bearer tokens that are not bound.
+	Confirmation *TokenConfirmation `json:"cnf,omitempty"`
+
+	// claims holds all the raw claims of the token, including the ones
+	// without a dedicated field above.
+	claims map[string]interface{}
+
+	version int
+
+	// actor is the subject of the delegation claim.
+	actor string
+}
+
+// delegationClaim is the "act" (RFC 8693) or "obo" claim,
+// identifying the actor behind an impersonated subject.
+type delegationClaim struct {
+	Subject string `json:"sub"`
+}
+
+// Versions of the token claims schema, returned by
+// AuthenticationToken.ClaimsVersion.
+const (
+	ClaimsVersion1 = 1
+	ClaimsVersion2 = 2
+)
+
+// v2UserClaims is the nested "user" claim of the v2 schema.
+type v2UserClaims struct {
+	ID    string   `json:"id,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+
+	LoID struct {
+		ID        string                      `json:"id,omitempty"`
+		CreatedAt timebp.TimestampMillisecond `json:"created_ms,omitempty"`
+	} `json:"loid,omitempty"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// Besides the fields, it also keeps all the raw claims to be used by
+// RawClaims, StringClaim, and Int64Claim.
+//
+// For v2 tokens, the attributes in the nested "user" claim are used when the
+// corresponding top level claims are absent.
+func (t *AuthenticationToken) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = AuthenticationToken{}
+	var (
+		user     *v2UserClaims
+		act, obo *delegationClaim
+	)
+	fields := map[string]interface{}{
+		"iss":         &t.RegisteredClaims.Issuer,
+		"sub":         &t.RegisteredClaims.Subject,
+		"aud":         &t.RegisteredClaims.Audience,
+		"exp":         &t.RegisteredClaims.ExpiresAt,
+		"nbf":         &t.RegisteredClaims.NotBefore,
+		"iat":         &t.RegisteredClaims.IssuedAt,
+		"jti":         &t.RegisteredClaims.ID,
+		"roles":       &t.Roles,
+		"client_id":   &t.OAuthClientID,
+		"client_type": &t.OAuthClientType,
+		"scopes":      &t.Scopes,
+		"loid":        &t.LoID,
+		"cnf":         &t.Confirmation,
+		"user":        &user,
+		"act":         &act,
+		"obo":         &obo,
+	}
+	t.claims = make(map[string]interface{}, len(raw))
+	for name, value := range raw {
+		if field, ok := fields[name]; ok {
+			if err := json.Unmarshal(value, field); err != nil {
+				return err
+			}
+		}
+		claim, err := decodeClaim(value)
+		if err != nil {
+			return err
+		}
+		t.claims[name] = claim
+	}
+
+	switch {
+	case act != nil:
+		t.actor = act.Subject
+	case obo != nil:
+		t.actor = obo.Subject
+	}
+	t.version = ClaimsVersion1
+	if user != nil {
+		t.version = ClaimsVersion2
+		if t.RegisteredClaims.Subject == "" {
+			t.RegisteredClaims.Subject = user.ID
+		}
+		if len(t.Roles) == 0 {
+			t.Roles = user.Roles
+		}
+		if t.LoID.ID == "" {
+			t.LoID = user.LoID
+		}
+	}
+	return nil
+}
+
+// decodeClaim decodes the raw value of a claim, with numbers represented as
+// json.Number.
+func decodeClaim(value json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	var claim interface{}
+	err := decoder.Decode(&claim)
+	return claim, err
+}
+
+// ClaimsVersion returns the schema version of the token claims,
+// either ClaimsVersion1 or ClaimsVersion2.
+//
+// It's 0 if the token was not decoded from JSON.
+func (t AuthenticationToken) ClaimsVersion() int {
+	return t.version
+}
+
+// RawClaims returns all the claims of the token, including the ones without a
+// dedicated field in AuthenticationToken,
+// for experimenting with new claims.
+//
+// JSON numbers are represented as json.Number.
+// It's nil if the token was not decoded from JSON.
+// The returned map should be treated as read-only,
+// as the token could be shared by multiple requests.
+func (t AuthenticationToken) RawClaims() map[string]interface{} {
+	return t.claims
+}
+
+// StringClaim returns the named claim if it's a string.
+//
+// ok will be false if the claim does not exist or is not a string.
+func (t AuthenticationToken) StringClaim(name string) (value string, ok bool) {
+	value, ok = t.claims[name].(string)
+	return
+}
+
+// Int64Claim returns the named claim if it's an integer.
+//
+// ok will be false if the claim does not exist or is not an integer that fits
+// in int64.
+func (t AuthenticationToken) Int64Claim(name string) (value int64, ok bool) {
+	n, ok := t.claims[name].(json.Number)
+	if !ok {
+		return
+	}
+	value, err := n.Int64()
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// HasClaim returns true if the token has the named claim with a non-zero
+// value, e.g. not null, empty string, empty array or empty object.
+//
+// For v2 tokens, the sub, roles and loid claims nested under the "user" claim
+// are also considered.
+func (t AuthenticationToken) HasClaim(name string) bool {
+	switch name {
+	case "sub":
+		return t.Subject() != ""
+	case "roles":
+		return len(t.Roles) > 0
+	case "loid":
+		return t.LoID.ID != ""
+	}
+	switch v := t.claims[name].(type) {
+	default:
+		return true
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+}
+
+// Known OAuth client types, to be used with IsOAuthClientType and
+// OAuthClient.IsType.
+const (
+	OAuthClientTypeFirstParty = "first_party"
+	OAuthClientTypeThirdParty = "third_party"
+	OAuthClientTypeScript     = "script"
+)
+
 // Subject returns the subject field of the token.
 func (t AuthenticationToken) Subject() string {
 	return t.RegisteredClaims.Subject
 }
+
+// EffectiveSubject returns the subject the token acts as,
+// which is the same as Subject.
+//
+// For delegated tokens, it's the impersonated subject,
+// use ActingSubject to get the real actor.
+func (t AuthenticationToken) EffectiveSubject() string {
+	return t.Subject()
+}
+
+// ActingSubject returns the subject of the real actor behind the effective
+// subject, from the "act" (RFC 8693) or "obo" delegation claim.
+//
+// ok will be false if the token is not delegated.
+func (t AuthenticationToken) ActingSubject() (subject string, ok bool) {
+	return t.actor, t.actor != ""
+}
+
+// IsDelegated returns true if the token is issued to an actor acting on behalf
+// of the subject.
+func (t AuthenticationToken) IsDelegated() bool {
+	return t.actor != ""
+}
+
+// ExpiresAt returns the expiration time of the token.
+//
+// ok will be false if the token does not expire.
+func (t AuthenticationToken) ExpiresAt() (ts time.Time, ok bool) {
+	if t.RegisteredClaims.ExpiresAt == nil {
+		return
+	}
+	return t.RegisteredClaims.ExpiresAt.Time, true
+}
+
+// IsExpired returns true if the token is already expired,
+// or will expire within leeway.
+//
+// A positive leeway can be used by services holding long-lived contexts
+// (websockets, workers) to detect stale identities proactively,
+// a negative leeway tolerates clock skews.
+// Tokens without expiration time never expire.
+func (t AuthenticationToken) IsExpired(leeway time.Duration) bool {
+	ts, ok := t.ExpiresAt()
+	return ok && !time.Now().Add(leeway).Before(ts)
+}
+
+// TimeToExpiry returns the duration until the token expires,
+// which is negative if it's already expired.
+//
+// ok will be false if the token does not expire.
+func (t AuthenticationToken) TimeToExpiry() (d time.Duration, ok bool) {
+	ts, ok := t.ExpiresAt()
+	if !ok {
+		return
+	}
+	return time.Until(ts), true
+}
+
+// String implements fmt.Stringer.
+//
+// It only prints the subject, roles, OAuth client type and expiration time of
+// the token, never the raw token or other claims,
+// so it's safe to be logged.
+func (t AuthenticationToken) String() string {
+	var sb strings.Builder
+	sb.WriteString("AuthenticationToken{sub=")
+	sb.WriteString(strconv.Quote(t.Subject()))
+	sb.WriteString(" roles=[")
+	sb.WriteString(strings.Join(t.Roles, " "))
+	sb.WriteString("] client_type=")
+	sb.WriteString(strconv.Quote(t.OAuthClientType))
+	sb.WriteString(" exp=")
+	sb.WriteString(t.expiresAtString())
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// expiresAtString formats the expiration time of the token for String and
+// LogValue.
+func (t AuthenticationToken) expiresAtString() string {
+	ts, ok := t.ExpiresAt()
+	if !ok {
+		return "never"
+	}
+	return ts.UTC().Format(time.RFC3339)
+}
+
+// LoIDClaim returns the id and the creation time of the LoID claim of the
+// token.
+//
+// ok will be false if the token does not have a LoID claim.
+func (t AuthenticationToken) LoIDClaim() (id string, createdAt time.Time, ok bool) {
+	return t.LoID.ID, t.LoID.CreatedAt.ToTime(), t.LoID.ID != ""
+}
+
+// ReconcileLoID reconciles the LoID claim of the token with headerLoID,
+// the LoID from the edge context header.
+//
+// The header LoID is preferred when it's non-empty,
+// otherwise the LoID claim is used.
+// mismatch will be true when both of them are non-empty but differ.
+func (t AuthenticationToken) ReconcileLoID(headerLoID string) (loid string, mismatch bool) {
+	claim := t.LoID.ID
+	if headerLoID == "" {
+		return claim, false
+	}
+	return headerLoID, claim != "" && claim != headerLoID
+}
+
+// IsLoggedIn returns true if the subject of the token is a user account
+// (with "t2_" prefix).
+//
+// Tokens issued to logged out users only carry the LoID claim without a
+// subject, and tokens issued to services have "service/" prefixed subjects,
+// both of them are not logged in.
+func (t AuthenticationToken) IsLoggedIn() bool {
+	return strings.HasPrefix(t.Subject(), userPrefix)
+}
+
+// IsService returns true if the subject of the token is a service
+// (with "service/" prefix).
+func (t AuthenticationToken) IsService() bool {
+	return strings.HasPrefix(t.Subject(), servicePrefix)
+}
+
+// HasRole returns true if the token has the specific role.
+//
+// Roles are compared case-insensitively, ignoring leading and trailing
+// whitespaces.
+func (t AuthenticationToken) HasRole(role string) bool {
+	role = strings.TrimSpace(role)
+	// Since in most cases the roles slice would be quite small,
+	// it's better to iterate them than converting the slice into a set.
+	for _, r := range t.Roles {
+		if strings.EqualFold(role, strings.TrimSpace(r)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOAuthClientType checks if the OAuth client type of the token matches any of
+// the given types, case-insensitively.
+//
+// See OAuthClient.IsType for the recommended way of checking client types.
+func (t AuthenticationToken) IsOAuthClientType(types ...string) bool {
+	for _, typ := range types {
+		if strings.EqualFold(t.OAuthClientType, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeWildcard is the suffix of wildcard scopes.
+const scopeWildcard = "*"
+
+// HasScope returns true if the token is granted the specific OAuth scope.
+//
+// Besides exact matches, wildcards are only expanded on the granted side:
+//
+//   - A granted "*" scope matches any scope.
+//   - A granted "identity.*" scope matches "identity.read", "identity.write",
+//     etc.
+//   - Checking "identity.*" only returns true if the token is granted
+//     "identity.*" itself (or "*"), not when it's only granted some scopes
+//     under "identity.".
+func (t AuthenticationToken) HasScope(scope string) bool {
+	for _, granted := range t.Scopes {
+		if scopeMatches(granted, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeMatches returns true if pattern, which could be a wildcard scope,
+// matches scope.
+func scopeMatches(pattern, scope string) bool {
+	if pattern == scope {
+		return scope != ""
+	}
+	if !strings.HasSuffix(pattern, scopeWildcard) {
+		return false
+	}
+	prefix := pattern[:len(pattern)-len(scopeWildcard)]
+	return len(scope) > len(prefix) && strings.HasPrefix(scope, prefix)
+}
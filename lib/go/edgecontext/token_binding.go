@@ -0,0 +1,71 @@
+package edgecontext
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrDeviceMismatch is an error returned by VerifyDevice and
+// VerifyDeviceBinding indicates that the token is bound to a different device,
+// which usually means that the token was stolen and replayed.
+var ErrDeviceMismatch = errors.New("edgecontext: token is bound to a different device")
+
+// TokenConfirmation is the "cnf" claim of AuthenticationToken.
+type TokenConfirmation struct {
+	// DeviceID is the id of the device the token is issued to.
+	DeviceID string `json:"device_id,omitempty"`
+
+	// JWKThumbprint is the JWK SHA-256 thumbprint (RFC 7638) of the DPoP
+	// (RFC 9449) key the token is bound to, see DPoPKeyThumbprint.
+	JWKThumbprint string `json:"jkt,omitempty"`
+}
+
+// BoundDeviceID returns the id of the device the token is bound to.
+//
+// ok will be false if the token is not bound to a device.
+func (t AuthenticationToken) BoundDeviceID() (id string, ok bool) {
+	if t.Confirmation == nil || t.Confirmation.DeviceID == "" {
+		return
+	}
+	return t.Confirmation.DeviceID, true
+}
+
+// DPoPThumbprint returns the JWK SHA-256 thumbprint of the DPoP key the token
+// is bound to.
+//
+// ok will be false if the token is not bound to a DPoP key.
+func (t AuthenticationToken) DPoPThumbprint() (jkt string, ok bool) {
+	if t.Confirmation == nil || t.Confirmation.JWKThumbprint == "" {
+		return
+	}
+	return t.Confirmation.JWKThumbprint, true
+}
+
+// VerifyDevice verifies that the token can be used by the given device.
+//
+// Tokens not bound to a device can be used by any device.
+// Device ids are compared case-insensitively, as they are UUIDs.
+func (t AuthenticationToken) VerifyDevice(deviceID string) error {
+	bound, ok := t.BoundDeviceID()
+	if !ok || strings.EqualFold(bound, deviceID) {
+		return nil
+	}
+	return ErrDeviceMismatch
+}
+
+// VerifyDeviceBinding verifies that the auth token of the request is bound to
+// the DeviceID of the request, if it's bound to a device at all.
+//
+// Requests without an auth token are not bound to any device and always pass
+// the verification.
+// If the auth token is invalid, the error from ValidatedAuthToken is returned.
+func (e *EdgeRequestContext) VerifyDeviceBinding() error {
+	token, err := e.ValidatedAuthToken()
+	if errors.Is(err, ErrEmptyToken) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return token.VerifyDevice(e.DeviceID())
+}
@@ -0,0 +1,123 @@
+package edgecontext_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+const (
+	boundDeviceID = "becc50f6-ff3d-407a-aa49-fa49531363be"
+	otherDeviceID = "a2b5ee4c-5a5f-4b24-9b41-07e3b6e1b1ad"
+)
+
+func TestTokenVerifyDevice(t *testing.T) {
+	for _, c := range []struct {
+		label    string
+		claims   string
+		deviceID string
+		expected error
+	}{
+		{
+			label:    "not-bound",
+			claims:   `{"sub": "t2_user"}`,
+			deviceID: otherDeviceID,
+		},
+		{
+			label:    "empty-cnf",
+			claims:   `{"sub": "t2_user", "cnf": {}}`,
+			deviceID: otherDeviceID,
+		},
+		{
+			label:    "same-device",
+			claims:   `{"sub": "t2_user", "cnf": {"device_id": "` + boundDeviceID + `"}}`,
+			deviceID: boundDeviceID,
+		},
+		{
+			label:    "same-device-upper-case",
+			claims:   `{"sub": "t2_user", "cnf": {"device_id": "` + boundDeviceID + `"}}`,
+			deviceID: "BECC50F6-FF3D-407A-AA49-FA49531363BE",
+		},
+		{
+			label:    "other-device",
+			claims:   `{"sub": "t2_user", "cnf": {"device_id": "` + boundDeviceID + `"}}`,
+			deviceID: otherDeviceID,
+			expected: edgecontext.ErrDeviceMismatch,
+		},
+		{
+			label:    "no-device",
+			claims:   `{"sub": "t2_user", "cnf": {"device_id": "` + boundDeviceID + `"}}`,
+			expected: edgecontext.ErrDeviceMismatch,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			var token edgecontext.AuthenticationToken
+			if err := json.Unmarshal([]byte(c.claims), &token); err != nil {
+				t.Fatal(err)
+			}
+			if err := token.VerifyDevice(c.deviceID); !errors.Is(err, c.expected) {
+				t.Errorf("Expected error %v, got %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestVerifyDeviceBinding(t *testing.T) {
+	key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{})
+	signed, err := edgecontext.NewSigner(key, keyID).Sign(edgecontext.AuthenticationToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "t2_user",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Confirmation: &edgecontext.TokenConfirmation{
+			DeviceID: boundDeviceID,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		label    string
+		args     edgecontext.NewArgs
+		expected error
+	}{
+		{
+			label: "same-device",
+			args: edgecontext.NewArgs{
+				AuthToken: signed,
+				DeviceID:  boundDeviceID,
+			},
+		},
+		{
+			label: "other-device",
+			args: edgecontext.NewArgs{
+				AuthToken: signed,
+				DeviceID:  otherDeviceID,
+			},
+			expected: edgecontext.ErrDeviceMismatch,
+		},
+		{
+			label: "no-token",
+			args: edgecontext.NewArgs{
+				DeviceID: otherDeviceID,
+			},
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), impl, c.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := e.VerifyDeviceBinding(); !errors.Is(err, c.expected) {
+				t.Errorf("Expected error %v, got %v", c.expected, err)
+			}
+		})
+	}
+}
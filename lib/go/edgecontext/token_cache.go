@@ -0,0 +1,108 @@
+package edgecontext
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// tokenCache is a bounded LRU cache of validated tokens,
+// keyed by the raw token string.
+//
+// Entries expire at the token's expiration time,
+// or after the configured ttl, whichever comes first.
+type tokenCache struct {
+	size int
+	ttl  time.Duration
+
+	lock sync.Mutex
+	ll   *list.List
+	m    map[string]*list.Element
+}
+
+type tokenCacheEntry struct {
+	key       string
+	token     *AuthenticationToken
+	expiresAt time.Time
+}
+
+func newTokenCache(size int, ttl time.Duration) *tokenCache {
+	return &tokenCache{
+		size: size,
+		ttl:  ttl,
+		ll:   list.New(),
+		m:    make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the cached token, or nil if it's not in the cache or expired.
+func (c *tokenCache) get(key string, now time.Time) *AuthenticationToken {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.m[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*tokenCacheEntry)
+	if !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil
+	}
+	c.ll.MoveToFront(elem)
+	return entry.token
+}
+
+// add adds a validated token into the cache,
+// evicting the least recently used one if the cache is full.
+func (c *tokenCache) add(key string, token *AuthenticationToken, now time.Time) {
+	expiresAt, _ := token.ExpiresAt()
+	if c.ttl > 0 {
+		if ttlExpiresAt := now.Add(c.ttl); expiresAt.IsZero() || ttlExpiresAt.Before(expiresAt) {
+			expiresAt = ttlExpiresAt
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.m[key]; ok {
+		entry := elem.Value.(*tokenCacheEntry)
+		entry.token = token
+		entry.expiresAt = expiresAt
+		c.ll.MoveToFront(elem)
+		return
+	}
+	c.m[key] = c.ll.PushFront(&tokenCacheEntry{
+		key:       key,
+		token:     token,
+		expiresAt: expiresAt,
+	})
+	for c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// purge removes all the entries from the cache.
+func (c *tokenCache) purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.ll.Init()
+	c.m = make(map[string]*list.Element, c.size)
+}
+
+// len returns the number of entries in the cache, including expired ones not
+// yet removed.
+func (c *tokenCache) len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.ll.Len()
+}
+
+// removeElement must be called with the lock held.
+func (c *tokenCache) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.m, elem.Value.(*tokenCacheEntry).key)
+}
@@ -0,0 +1,97 @@
+package edgecontext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func tokenWithExpiration(subject string, exp time.Time) *AuthenticationToken {
+	token := &AuthenticationToken{}
+	token.RegisteredClaims.Subject = subject
+	if !exp.IsZero() {
+		token.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(exp)
+	}
+	return token
+}
+
+func TestTokenCacheLRU(t *testing.T) {
+	now := time.Now()
+	c := newTokenCache(2, 0)
+
+	c.add("a", tokenWithExpiration("a", time.Time{}), now)
+	c.add("b", tokenWithExpiration("b", time.Time{}), now)
+	// Touch "a" so that "b" becomes the least recently used one.
+	if token := c.get("a", now); token == nil || token.Subject() != "a" {
+		t.Fatalf("Expected cached token a, got %+v", token)
+	}
+	c.add("c", tokenWithExpiration("c", time.Time{}), now)
+
+	if token := c.get("b", now); token != nil {
+		t.Errorf("Expected b to be evicted, got %+v", token)
+	}
+	for _, key := range []string{"a", "c"} {
+		if token := c.get(key, now); token == nil || token.Subject() != key {
+			t.Errorf("Expected cached token %s, got %+v", key, token)
+		}
+	}
+	if n := c.len(); n != 2 {
+		t.Errorf("Expected cache len 2, got %d", n)
+	}
+
+	c.purge()
+	if n := c.len(); n != 0 {
+		t.Errorf("Expected empty cache after purge, got %d", n)
+	}
+}
+
+func TestTokenCacheExpiration(t *testing.T) {
+	// jwt.NumericDate only has second precision.
+	now := time.Now().Truncate(time.Second)
+
+	for _, c := range []struct {
+		label   string
+		ttl     time.Duration
+		exp     time.Time
+		expired time.Duration
+	}{
+		{
+			label:   "token-expiration",
+			exp:     now.Add(time.Minute),
+			expired: time.Minute,
+		},
+		{
+			label:   "ttl",
+			ttl:     time.Second,
+			exp:     now.Add(time.Minute),
+			expired: time.Second,
+		},
+		{
+			label:   "ttl-longer-than-expiration",
+			ttl:     time.Hour,
+			exp:     now.Add(time.Minute),
+			expired: time.Minute,
+		},
+		{
+			label:   "ttl-without-expiration",
+			ttl:     time.Second,
+			expired: time.Second,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			cache := newTokenCache(10, c.ttl)
+			cache.add("token", tokenWithExpiration("subject", c.exp), now)
+
+			if token := cache.get("token", now.Add(c.expired-time.Millisecond)); token == nil {
+				t.Error("Expected cached token before expiration, got nil")
+			}
+			if token := cache.get("token", now.Add(c.expired)); token != nil {
+				t.Errorf("Expected nil token after expiration, got %+v", token)
+			}
+			if n := cache.len(); n != 0 {
+				t.Errorf("Expected expired token to be removed, got len %d", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+//go:build go1.21
+
+package edgecontext
+
+import (
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer.
+//
+// Same as String, it only logs the subject, roles, OAuth client type and
+// expiration time of the token.
+func (t AuthenticationToken) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("sub", t.Subject()),
+		slog.Any("roles", t.Roles),
+		slog.String("client_type", t.OAuthClientType),
+		slog.String("exp", t.expiresAtString()),
+	)
+}
@@ -0,0 +1,47 @@
+//go:build go1.21
+
+package edgecontext_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestTokenLogValue(t *testing.T) {
+	token := edgecontext.AuthenticationToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "t2_example",
+			ExpiresAt: jwt.NewNumericDate(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)),
+		},
+		Roles:           []string{"admin"},
+		OAuthClientID:   "secret-client-id",
+		OAuthClientType: edgecontext.OAuthClientTypeFirstParty,
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("msg", "token", token)
+
+	got := buf.String()
+	const want = `level=INFO msg=msg token.sub=t2_example token.roles=[admin] token.client_type=first_party token.exp=2030-01-02T03:04:05Z` + "\n"
+	if got != want {
+		t.Errorf("Log output got %q, want %q", got, want)
+	}
+	if strings.Contains(got, token.OAuthClientID) {
+		t.Errorf("Log output %q contains the client id", got)
+	}
+}
@@ -0,0 +1,732 @@
+package edgecontext_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/reddit/baseplate.go/timebp"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestTokenIsLoggedIn(t *testing.T) {
+	for _, c := range []struct {
+		label    string
+		subject  string
+		loid     string
+		expected bool
+	}{
+		{
+			label:    "user",
+			subject:  "t2_example",
+			expected: true,
+		},
+		{
+			label: "loid-only",
+			loid:  "t2_loid",
+		},
+		{
+			label:   "service",
+			subject: "service/foo",
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			var token edgecontext.AuthenticationToken
+			token.RegisteredClaims = jwt.RegisteredClaims{Subject: c.subject}
+			token.LoID.ID = c.loid
+			if got := token.IsLoggedIn(); got != c.expected {
+				t.Errorf("Expected IsLoggedIn %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestEdgeRequestContextIsLoggedIn(t *testing.T) {
+	for _, c := range []struct {
+		label    string
+		token    string
+		expected bool
+	}{
+		{
+			label: "no-token",
+		},
+		{
+			label:    "valid-token",
+			token:    validToken,
+			expected: true,
+		},
+		{
+			label: "expired-token",
+			token: expiredToken,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+				AuthToken: c.token,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := e.IsLoggedIn(); got != c.expected {
+				t.Errorf("Expected IsLoggedIn %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestTokenHasRole(t *testing.T) {
+	token := edgecontext.AuthenticationToken{
+		Roles: []string{"Admin", " employee "},
+	}
+	for _, c := range []struct {
+		role     string
+		expected bool
+	}{
+		{
+			role:     "admin",
+			expected: true,
+		},
+		{
+			role:     "ADMIN",
+			expected: true,
+		},
+		{
+			role:     "employee",
+			expected: true,
+		},
+		{
+			role:     "  Employee\t",
+			expected: true,
+		},
+		{
+			role: "moderator",
+		},
+		{
+			role: "",
+		},
+	} {
+		t.Run(c.role, func(t *testing.T) {
+			if got := token.HasRole(c.role); got != c.expected {
+				t.Errorf("Expected HasRole(%q) %v, got %v", c.role, c.expected, got)
+			}
+		})
+	}
+}
+
+func TestTokenHasScope(t *testing.T) {
+	for _, c := range []struct {
+		label    string
+		scopes   []string
+		scope    string
+		expected bool
+	}{
+		{
+			label:    "exact",
+			scopes:   []string{"read", "identity"},
+			scope:    "identity",
+			expected: true,
+		},
+		{
+			label:  "missing",
+			scopes: []string{"read"},
+			scope:  "identity",
+		},
+		{
+			label:  "empty",
+			scopes: []string{"read"},
+			scope:  "",
+		},
+		{
+			label:    "granted-star",
+			scopes:   []string{"*"},
+			scope:    "identity.read",
+			expected: true,
+		},
+		{
+			label:    "granted-wildcard",
+			scopes:   []string{"identity.*"},
+			scope:    "identity.read",
+			expected: true,
+		},
+		{
+			label:  "granted-wildcard-other-prefix",
+			scopes: []string{"identity.*"},
+			scope:  "identityx",
+		},
+		{
+			label:  "granted-wildcard-parent",
+			scopes: []string{"identity.*"},
+			scope:  "identity",
+		},
+		{
+			label:    "requested-wildcard-granted-wildcard",
+			scopes:   []string{"identity.*"},
+			scope:    "identity.*",
+			expected: true,
+		},
+		{
+			label:  "requested-wildcard-granted-narrower",
+			scopes: []string{"read", "identity.read"},
+			scope:  "identity.*",
+		},
+		{
+			label:  "requested-star-granted-narrower",
+			scopes: []string{"identity.read"},
+			scope:  "*",
+		},
+		{
+			label:  "requested-wildcard-missing",
+			scopes: []string{"read"},
+			scope:  "identity.*",
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			token := edgecontext.AuthenticationToken{
+				Scopes: c.scopes,
+			}
+			if got := token.HasScope(c.scope); got != c.expected {
+				t.Errorf("Expected HasScope(%q) with %q %v, got %v", c.scope, c.scopes, c.expected, got)
+			}
+		})
+	}
+}
+
+func TestTokenIsOAuthClientType(t *testing.T) {
+	token := edgecontext.AuthenticationToken{
+		OAuthClientType: edgecontext.OAuthClientTypeThirdParty,
+	}
+	for _, c := range []struct {
+		label    string
+		types    []string
+		expected bool
+	}{
+		{
+			label: "none",
+		},
+		{
+			label:    "match",
+			types:    []string{edgecontext.OAuthClientTypeThirdParty},
+			expected: true,
+		},
+		{
+			label:    "case-insensitive",
+			types:    []string{"THIRD_PARTY"},
+			expected: true,
+		},
+		{
+			label:    "any",
+			types:    []string{edgecontext.OAuthClientTypeScript, edgecontext.OAuthClientTypeThirdParty},
+			expected: true,
+		},
+		{
+			label: "mismatch",
+			types: []string{edgecontext.OAuthClientTypeFirstParty, edgecontext.OAuthClientTypeScript},
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			if got := token.IsOAuthClientType(c.types...); got != c.expected {
+				t.Errorf("Expected IsOAuthClientType(%q) %v, got %v", c.types, c.expected, got)
+			}
+			if got := edgecontext.OAuthClient(token).IsType(c.types...); got != c.expected {
+				t.Errorf("Expected OAuthClient.IsType(%q) %v, got %v", c.types, c.expected, got)
+			}
+		})
+	}
+}
+
+func TestTokenExpiration(t *testing.T) {
+	t.Run("no-expiration", func(t *testing.T) {
+		var token edgecontext.AuthenticationToken
+		if ts, ok := token.ExpiresAt(); ok {
+			t.Errorf("Expected no expiration, got %v", ts)
+		}
+		if token.IsExpired(time.Hour) {
+			t.Error("Expected token without expiration to never expire")
+		}
+		if d, ok := token.TimeToExpiry(); ok {
+			t.Errorf("Expected no time to expiry, got %v", d)
+		}
+	})
+
+	for _, c := range []struct {
+		label    string
+		expireIn time.Duration
+		leeway   time.Duration
+		expected bool
+	}{
+		{
+			label:    "valid",
+			expireIn: time.Hour,
+		},
+		{
+			label:    "expired",
+			expireIn: -time.Hour,
+			expected: true,
+		},
+		{
+			label:    "expiring-within-leeway",
+			expireIn: time.Minute,
+			leeway:   5 * time.Minute,
+			expected: true,
+		},
+		{
+			label:    "expired-within-negative-leeway",
+			expireIn: -time.Minute,
+			leeway:   -5 * time.Minute,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			exp := time.Now().Add(c.expireIn).Truncate(time.Second)
+			var token edgecontext.AuthenticationToken
+			token.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(exp)
+			if ts, ok := token.ExpiresAt(); !ok || !ts.Equal(exp) {
+				t.Errorf("Expected expiration %v, got %v, %v", exp, ts, ok)
+			}
+			if got := token.IsExpired(c.leeway); got != c.expected {
+				t.Errorf("Expected IsExpired(%v) %v, got %v", c.leeway, c.expected, got)
+			}
+			d, ok := token.TimeToExpiry()
+			if !ok {
+				t.Fatal("Expected time to expiry")
+			}
+			if diff := d - c.expireIn; diff > time.Second || diff < -2*time.Second {
+				t.Errorf("Expected time to expiry about %v, got %v", c.expireIn, d)
+			}
+		})
+	}
+}
+
+func TestTokenLoID(t *testing.T) {
+	createdAt := time.Unix(1600000000, 0)
+
+	var token edgecontext.AuthenticationToken
+	if id, ts, ok := token.LoIDClaim(); ok {
+		t.Errorf("Expected no LoID claim, got %q, %v", id, ts)
+	}
+
+	token.LoID.ID = "t2_claim"
+	token.LoID.CreatedAt = timebp.TimestampMillisecond(createdAt)
+	if id, ts, ok := token.LoIDClaim(); !ok || id != "t2_claim" || !ts.Equal(createdAt) {
+		t.Errorf("Expected LoID claim %q, %v, got %q, %v, %v", "t2_claim", createdAt, id, ts, ok)
+	}
+
+	for _, c := range []struct {
+		label            string
+		claim            string
+		header           string
+		expectedLoID     string
+		expectedMismatch bool
+	}{
+		{
+			label: "neither",
+		},
+		{
+			label:        "claim-only",
+			claim:        "t2_claim",
+			expectedLoID: "t2_claim",
+		},
+		{
+			label:        "header-only",
+			header:       "t2_header",
+			expectedLoID: "t2_header",
+		},
+		{
+			label:        "same",
+			claim:        "t2_same",
+			header:       "t2_same",
+			expectedLoID: "t2_same",
+		},
+		{
+			label:            "mismatch",
+			claim:            "t2_claim",
+			header:           "t2_header",
+			expectedLoID:     "t2_header",
+			expectedMismatch: true,
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			var token edgecontext.AuthenticationToken
+			token.LoID.ID = c.claim
+			loid, mismatch := token.ReconcileLoID(c.header)
+			if loid != c.expectedLoID || mismatch != c.expectedMismatch {
+				t.Errorf("Expected %q, %v, got %q, %v", c.expectedLoID, c.expectedMismatch, loid, mismatch)
+			}
+		})
+	}
+}
+
+func TestUserReconciledLoID(t *testing.T) {
+	for _, token := range []string{"", validToken} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			LoID:      expectedLoID,
+			AuthToken: token,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		loid, mismatch := reparse(t, e).User().ReconciledLoID()
+		if loid != expectedLoID || mismatch {
+			t.Errorf("Expected %q, false, got %q, %v", expectedLoID, loid, mismatch)
+		}
+	}
+}
+
+func TestTokenIsService(t *testing.T) {
+	for _, c := range []struct {
+		subject      string
+		expected     bool
+		expectedName string
+	}{
+		{
+			subject: "",
+		},
+		{
+			subject: "t2_example",
+		},
+		{
+			subject:      "service/foo",
+			expected:     true,
+			expectedName: "foo",
+		},
+	} {
+		t.Run(c.subject, func(t *testing.T) {
+			var token edgecontext.AuthenticationToken
+			token.RegisteredClaims.Subject = c.subject
+			if got := token.IsService(); got != c.expected {
+				t.Errorf("Expected IsService %v, got %v", c.expected, got)
+			}
+			name, ok := edgecontext.Service(token).Name()
+			if name != c.expectedName || ok != c.expected {
+				t.Errorf("Expected service name %q, %v, got %q, %v", c.expectedName, c.expected, name, ok)
+			}
+		})
+	}
+}
+
+func TestEdgeRequestContextIsServiceRequest(t *testing.T) {
+	for _, token := range []string{"", validToken} {
+		e, err := edgecontext.New(context.Background(), globalTestImpl, edgecontext.NewArgs{
+			AuthToken: token,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.IsServiceRequest() {
+			t.Errorf("Expected user request for token %q", token)
+		}
+	}
+}
+
+func TestTokenClaims(t *testing.T) {
+	var token edgecontext.AuthenticationToken
+	if err := json.Unmarshal([]byte(`{
+		"sub": "t2_example",
+		"exp": 2524608000,
+		"roles": ["admin"],
+		"experiment": "treatment",
+		"karma": 9007199254740993,
+		"ratio": 0.5,
+		"nested": {"a": "b"}
+	}`), &token); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := token.Subject(); got != "t2_example" {
+		t.Errorf("Expected subject %q, got %q", "t2_example", got)
+	}
+	if !token.HasRole(edgecontext.RoleAdmin) {
+		t.Errorf("Expected role %q, got %q", edgecontext.RoleAdmin, token.Roles)
+	}
+	if ts, ok := token.ExpiresAt(); !ok || ts.Unix() != 2524608000 {
+		t.Errorf("Expected expiration %d, got %v, %v", 2524608000, ts, ok)
+	}
+
+	claims := token.RawClaims()
+	for _, name := range []string{"sub", "exp", "roles", "experiment", "karma", "ratio", "nested"} {
+		if _, ok := claims[name]; !ok {
+			t.Errorf("Expected claim %q in %v", name, claims)
+		}
+	}
+
+	if v, ok := token.StringClaim("experiment"); !ok || v != "treatment" {
+		t.Errorf("Expected string claim %q, got %q, %v", "treatment", v, ok)
+	}
+	if v, ok := token.StringClaim("karma"); ok {
+		t.Errorf("Expected non-string claim to be rejected, got %q", v)
+	}
+	if v, ok := token.StringClaim("missing"); ok {
+		t.Errorf("Expected missing claim to be rejected, got %q", v)
+	}
+
+	if v, ok := token.Int64Claim("karma"); !ok || v != 9007199254740993 {
+		t.Errorf("Expected int64 claim %d, got %d, %v", int64(9007199254740993), v, ok)
+	}
+	if v, ok := token.Int64Claim("ratio"); ok {
+		t.Errorf("Expected non-integer claim to be rejected, got %d", v)
+	}
+	if v, ok := token.Int64Claim("experiment"); ok {
+		t.Errorf("Expected non-number claim to be rejected, got %d", v)
+	}
+}
+
+func TestTokenUnmarshalJSONFields(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	expected := edgecontext.AuthenticationToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://reddit.com",
+			Subject:   "t2_example",
+			Audience:  jwt.ClaimStrings{"service/example"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        "jti",
+		},
+		Roles:           []string{edgecontext.RoleAdmin},
+		OAuthClientID:   "client",
+		OAuthClientType: edgecontext.OAuthClientTypeFirstParty,
+		Scopes:          []string{"identity.read"},
+		Confirmation: &edgecontext.TokenConfirmation{
+			DeviceID: "device",
+		},
+	}
+	expected.LoID.ID = "t2_loid"
+	expected.LoID.CreatedAt = timebp.TimestampMillisecond(now)
+
+	data, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var token edgecontext.AuthenticationToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reset the unexported fields to compare the decoded fields only.
+	actual := edgecontext.AuthenticationToken{
+		RegisteredClaims: token.RegisteredClaims,
+		Roles:            token.Roles,
+		OAuthClientID:    token.OAuthClientID,
+		OAuthClientType:  token.OAuthClientType,
+		Scopes:           token.Scopes,
+		LoID:             token.LoID,
+		Confirmation:     token.Confirmation,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, actual)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for name := range fields {
+		if _, ok := token.RawClaims()[name]; !ok {
+			t.Errorf("Expected claim %q in %v", name, token.RawClaims())
+		}
+	}
+}
+
+func TestValidatedTokenClaims(t *testing.T) {
+	token, err := globalTestImpl.ValidateToken(validToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := token.StringClaim("sub"); !ok || v != "t2_example" {
+		t.Errorf("Expected sub claim %q, got %q, %v", "t2_example", v, ok)
+	}
+	if v, ok := token.Int64Claim("exp"); !ok || v != 2524608000 {
+		t.Errorf("Expected exp claim %d, got %d, %v", 2524608000, v, ok)
+	}
+}
+
+func TestTokenClaimsV2(t *testing.T) {
+	for _, c := range []struct {
+		label           string
+		claims          string
+		expectedVersion int
+		expectedSubject string
+		expectedRoles   []string
+		expectedLoID    string
+	}{
+		{
+			label:           "v1",
+			claims:          `{"sub": "t2_example", "roles": ["admin"], "loid": {"id": "t2_loid", "created_ms": 1600000000000}}`,
+			expectedVersion: edgecontext.ClaimsVersion1,
+			expectedSubject: "t2_example",
+			expectedRoles:   []string{"admin"},
+			expectedLoID:    "t2_loid",
+		},
+		{
+			label:           "v2",
+			claims:          `{"user": {"id": "t2_example", "roles": ["admin"], "loid": {"id": "t2_loid", "created_ms": 1600000000000}}}`,
+			expectedVersion: edgecontext.ClaimsVersion2,
+			expectedSubject: "t2_example",
+			expectedRoles:   []string{"admin"},
+			expectedLoID:    "t2_loid",
+		},
+		{
+			label:           "v2-logged-out",
+			claims:          `{"user": {"loid": {"id": "t2_loid", "created_ms": 1600000000000}}}`,
+			expectedVersion: edgecontext.ClaimsVersion2,
+			expectedLoID:    "t2_loid",
+		},
+		{
+			label:           "v2-top-level-precedence",
+			claims:          `{"sub": "t2_top", "user": {"id": "t2_nested", "roles": ["employee"]}}`,
+			expectedVersion: edgecontext.ClaimsVersion2,
+			expectedSubject: "t2_top",
+			expectedRoles:   []string{"employee"},
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			var token edgecontext.AuthenticationToken
+			if err := json.Unmarshal([]byte(c.claims), &token); err != nil {
+				t.Fatal(err)
+			}
+			if got := token.ClaimsVersion(); got != c.expectedVersion {
+				t.Errorf("Expected claims version %d, got %d", c.expectedVersion, got)
+			}
+			if got := token.Subject(); got != c.expectedSubject {
+				t.Errorf("Expected subject %q, got %q", c.expectedSubject, got)
+			}
+			if !stringSlicesEqual(token.Roles, c.expectedRoles) {
+				t.Errorf("Expected roles %q, got %q", c.expectedRoles, token.Roles)
+			}
+			id, createdAt, _ := token.LoIDClaim()
+			if id != c.expectedLoID {
+				t.Errorf("Expected LoID %q, got %q", c.expectedLoID, id)
+			}
+			if c.expectedLoID != "" && createdAt.UnixMilli() != 1600000000000 {
+				t.Errorf("Expected LoID created at %d, got %v", 1600000000000, createdAt)
+			}
+			if got := token.IsLoggedIn(); got != (c.expectedSubject != "") {
+				t.Errorf("Expected IsLoggedIn %v, got %v", c.expectedSubject != "", got)
+			}
+		})
+	}
+}
+
+func TestTokenDelegation(t *testing.T) {
+	for _, c := range []struct {
+		label         string
+		claims        string
+		expectedActor string
+	}{
+		{
+			label:  "not-delegated",
+			claims: `{"sub": "t2_user"}`,
+		},
+		{
+			label:         "act",
+			claims:        `{"sub": "t2_user", "act": {"sub": "t2_admin"}}`,
+			expectedActor: "t2_admin",
+		},
+		{
+			label:         "nested-act",
+			claims:        `{"sub": "t2_user", "act": {"sub": "service/support", "act": {"sub": "t2_admin"}}}`,
+			expectedActor: "service/support",
+		},
+		{
+			label:         "obo",
+			claims:        `{"sub": "t2_user", "obo": {"sub": "service/support"}}`,
+			expectedActor: "service/support",
+		},
+		{
+			label:         "act-precedence",
+			claims:        `{"sub": "t2_user", "act": {"sub": "t2_admin"}, "obo": {"sub": "service/support"}}`,
+			expectedActor: "t2_admin",
+		},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			var token edgecontext.AuthenticationToken
+			if err := json.Unmarshal([]byte(c.claims), &token); err != nil {
+				t.Fatal(err)
+			}
+			if got := token.EffectiveSubject(); got != "t2_user" {
+				t.Errorf("Expected effective subject %q, got %q", "t2_user", got)
+			}
+			actor, ok := token.ActingSubject()
+			if actor != c.expectedActor || ok != (c.expectedActor != "") {
+				t.Errorf("Expected acting subject %q, got %q, %v", c.expectedActor, actor, ok)
+			}
+			if got := token.IsDelegated(); got != (c.expectedActor != "") {
+				t.Errorf("Expected IsDelegated %v, got %v", c.expectedActor != "", got)
+			}
+		})
+	}
+}
+
+func TestTokenString(t *testing.T) {
+	token := edgecontext.AuthenticationToken{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "t2_example",
+			ExpiresAt: jwt.NewNumericDate(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)),
+		},
+		Roles:           []string{"admin", "employee"},
+		OAuthClientID:   "secret-client-id",
+		OAuthClientType: edgecontext.OAuthClientTypeFirstParty,
+		Scopes:          []string{"identity"},
+	}
+	const want = `AuthenticationToken{sub="t2_example" roles=[admin employee] client_type="first_party" exp=2030-01-02T03:04:05Z}`
+	if got := token.String(); got != want {
+		t.Errorf("String() got %s, want %s", got, want)
+	}
+	if got := (&token).String(); got != want {
+		t.Errorf("(*AuthenticationToken).String() got %s, want %s", got, want)
+	}
+
+	if got, want := (edgecontext.AuthenticationToken{}).String(), `AuthenticationToken{sub="" roles=[] client_type="" exp=never}`; got != want {
+		t.Errorf("String() of empty token got %s, want %s", got, want)
+	}
+}
+
+func TestTokenHasClaim(t *testing.T) {
+	const claims = `{
+		"sub": "t2_user",
+		"exp": 2524608000,
+		"client_id": "",
+		"scopes": [],
+		"act": {},
+		"foo": null,
+		"bar": false,
+		"baz": ["qux"]
+	}`
+	var token edgecontext.AuthenticationToken
+	if err := json.Unmarshal([]byte(claims), &token); err != nil {
+		t.Fatal(err)
+	}
+	for name, expected := range map[string]bool{
+		"sub":       true,
+		"exp":       true,
+		"client_id": false,
+		"scopes":    false,
+		"act":       false,
+		"foo":       false,
+		"bar":       true,
+		"baz":       true,
+		"roles":     false,
+		"loid":      false,
+		"missing":   false,
+	} {
+		if got := token.HasClaim(name); got != expected {
+			t.Errorf("HasClaim(%q) got %v, want %v", name, got, expected)
+		}
+	}
+
+	t.Run("v2", func(t *testing.T) {
+		const claims = `{"user": {"id": "t2_user", "roles": ["admin"], "loid": {"id": "t2_loid"}}}`
+		var token edgecontext.AuthenticationToken
+		if err := json.Unmarshal([]byte(claims), &token); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"sub", "roles", "loid"} {
+			if !token.HasClaim(name) {
+				t.Errorf("Expected HasClaim(%q) to be true, got false", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package edgecontext
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// UntrustedAuthenticationToken holds the claims of a token that was NOT
+// accepted by ValidateToken,
+// returned by ParseTokenUnverified and Impl.ClaimsFromExpiredToken.
+//
+// It's only meant for logging, debugging and grace-period flows (e.g.
+// refreshing the session of the subject of an expired token),
+// the claims must never be used for authorization.
+// It's deliberately not an AuthenticationToken so it can't be put into an
+// EdgeRequestContext by mistake.
+type UntrustedAuthenticationToken struct {
+	// The decoded claims of the token.
+	Claims AuthenticationToken
+
+	// SignatureVerified is true if the signature of the token was verified,
+	// which is only the case for Impl.ClaimsFromExpiredToken.
+	SignatureVerified bool
+}
+
+// unverifiedParser decodes the jwt tokens for ParseTokenUnverified.
+var unverifiedParser = jwt.NewParser()
+
+// ParseTokenUnverified decodes the claims of a jwt token WITHOUT verifying
+// its signature or validating any of its claims.
+//
+// Anyone can mint a token that decodes,
+// so the claims returned are untrusted and must never be used for
+// authorization.
+// Use Impl.ClaimsFromExpiredToken instead when the token must be signed by
+// the trusted keys.
+func ParseTokenUnverified(token string) (*UntrustedAuthenticationToken, error) {
+	if token == "" {
+		return nil, ErrEmptyToken
+	}
+	var claims AuthenticationToken
+	if _, _, err := unverifiedParser.ParseUnverified(token, &claims); err != nil {
+		return nil, classifyTokenError(err, true)
+	}
+	return &UntrustedAuthenticationToken{Claims: claims}, nil
+}
+
+// ClaimsFromExpiredToken is like ValidateToken,
+// but also returns the claims of expired tokens,
+// as long as they pass all the other validations
+// (signature, nbf, required claims, audiences, revocation, etc.).
+//
+// It's meant for grace-period flows where the subject of an expired token is
+// needed without accepting the token for authorization,
+// so the claims are returned as an UntrustedAuthenticationToken.
+// Expired tokens are never added to the token cache.
+//
+// Opaque tokens validated by Config.TokenIntrospector are not supported,
+// as the introspection endpoint does not return the claims of expired tokens.
+func (impl *Impl) ClaimsFromExpiredToken(token string) (*UntrustedAuthenticationToken, error) {
+	claims, err := impl.validateToken(token, true)
+	if err != nil {
+		return nil, err
+	}
+	return &UntrustedAuthenticationToken{
+		Claims:            *claims,
+		SignatureVerified: true,
+	}, nil
+}
+
+// onlyExpired returns true if err returned by the jwt parser is only caused by
+// the expiration of the token,
+// which means that the signature and all the other claims are valid.
+func onlyExpired(err error) bool {
+	if !errors.Is(err, jwt.ErrTokenExpired) {
+		return false
+	}
+	for _, other := range []error{
+		jwt.ErrTokenNotValidYet,
+		jwt.ErrTokenUsedBeforeIssued,
+		jwt.ErrTokenInvalidAudience,
+		jwt.ErrTokenInvalidIssuer,
+		jwt.ErrTokenInvalidSubject,
+		jwt.ErrTokenRequiredClaimMissing,
+	} {
+		if errors.Is(err, other) {
+			return false
+		}
+	}
+	return true
+}
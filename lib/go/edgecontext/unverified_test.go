@@ -0,0 +1,122 @@
+package edgecontext_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/reddit/edgecontext/lib/go/edgecontext"
+)
+
+func TestParseTokenUnverified(t *testing.T) {
+	// The signature of validToken is not verified, so tampering it is fine.
+	token, err := edgecontext.ParseTokenUnverified(validToken[:len(validToken)-4] + "abcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.SignatureVerified {
+		t.Error("Expected SignatureVerified to be false")
+	}
+	if got, want := token.Claims.Subject(), "t2_example"; got != want {
+		t.Errorf("Subject got %q, want %q", got, want)
+	}
+
+	if _, err := edgecontext.ParseTokenUnverified("foo.bar"); !errors.Is(err, edgecontext.ErrMalformedToken) {
+		t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrMalformedToken, err)
+	}
+	if _, err := edgecontext.ParseTokenUnverified(""); !errors.Is(err, edgecontext.ErrEmptyToken) {
+		t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrEmptyToken, err)
+	}
+}
+
+func TestClaimsFromExpiredToken(t *testing.T) {
+	key, keyID, impl := newSignerTestImpl(t, edgecontext.Config{
+		TokenCacheSize: 10,
+	})
+	sign := func(t *testing.T, claims jwt.RegisteredClaims) string {
+		t.Helper()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header[edgecontext.JWTHeaderKeyID] = keyID
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	now := time.Now()
+
+	for _, c := range []struct {
+		name   string
+		claims jwt.RegisteredClaims
+		tamper bool
+		want   error
+	}{
+		{
+			name: "valid",
+			claims: jwt.RegisteredClaims{
+				Subject:   "t2_example",
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+		},
+		{
+			name: "expired",
+			claims: jwt.RegisteredClaims{
+				Subject:   "t2_example",
+				ExpiresAt: jwt.NewNumericDate(now.Add(-time.Hour)),
+			},
+		},
+		{
+			name: "expired-bad-signature",
+			claims: jwt.RegisteredClaims{
+				Subject:   "t2_example",
+				ExpiresAt: jwt.NewNumericDate(now.Add(-time.Hour)),
+			},
+			tamper: true,
+			want:   edgecontext.ErrBadSignature,
+		},
+		{
+			name: "expired-not-valid-yet",
+			claims: jwt.RegisteredClaims{
+				Subject:   "t2_example",
+				ExpiresAt: jwt.NewNumericDate(now.Add(-time.Hour)),
+				NotBefore: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+			want: jwt.ErrTokenNotValidYet,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			signed := sign(t, c.claims)
+			if c.tamper {
+				signed = signed[:len(signed)-4] + "abcd"
+			}
+			token, err := impl.ClaimsFromExpiredToken(signed)
+			if !errors.Is(err, c.want) {
+				t.Fatalf("error mismatch: want %v, got %v", c.want, err)
+			}
+			if c.want != nil {
+				return
+			}
+			if !token.SignatureVerified {
+				t.Error("Expected SignatureVerified to be true")
+			}
+			if got, want := token.Claims.Subject(), "t2_example"; got != want {
+				t.Errorf("Subject got %q, want %q", got, want)
+			}
+		})
+	}
+
+	t.Run("not-cached", func(t *testing.T) {
+		signed := sign(t, jwt.RegisteredClaims{
+			Subject:   "t2_example",
+			ExpiresAt: jwt.NewNumericDate(now.Add(-time.Hour)),
+		})
+		if _, err := impl.ClaimsFromExpiredToken(signed); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := impl.ValidateToken(signed); !errors.Is(err, edgecontext.ErrTokenExpired) {
+			t.Errorf("error mismatch: want %v, got %v", edgecontext.ErrTokenExpired, err)
+		}
+	})
+}
@@ -1,7 +1,6 @@
 package edgecontext
 
 import (
-	"strings"
 	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
@@ -11,6 +10,23 @@ import (
 
 const userPrefix = "t2_"
 
+// UserIDSource is where the effective user id of a request is resolved from.
+type UserIDSource string
+
+// UserIDSource values.
+const (
+	// The request has no user id.
+	UserIDSourceNone UserIDSource = ""
+
+	// The user id is the subject of the validated auth token of a logged in
+	// user.
+	UserIDSourceToken UserIDSource = "token"
+
+	// The user id is the LoID of a logged out user, from either the header or
+	// the auth token.
+	UserIDSourceLoID UserIDSource = "loid"
+)
+
 // An User wraps *EdgeRequestContext and provides info about a logged in or
 // logged our user.
 type User struct {
@@ -22,13 +38,10 @@ type User struct {
 // ok will be false if the user is not logged in.
 func (u User) ID() (id string, ok bool) {
 	token := u.e.AuthToken()
-	if token == nil {
+	if token == nil || !token.IsLoggedIn() {
 		return
 	}
-	if strings.HasPrefix(token.Subject(), userPrefix) {
-		return token.Subject(), true
-	}
-	return
+	return token.Subject(), true
 }
 
 // IsLoggedIn returns true if the user is logged in.
@@ -37,30 +50,71 @@ func (u User) IsLoggedIn() bool {
 	return ok
 }
 
+// IsTeenRestricted returns true if the account is subject to teen-safety or
+// parental-control restrictions,
+// in which case stricter recommendation and chat policies should be applied.
+func (u User) IsTeenRestricted() bool {
+	return u.e.args().TeenRestricted
+}
+
+// IsEmailVerified returns true if the email address of the account is
+// verified.
+func (u User) IsEmailVerified() bool {
+	return u.e.args().EmailVerified
+}
+
+// IsPremium returns true if the account had an active premium subscription at
+// the time of the request, as seen by the edge.
+func (u User) IsPremium() bool {
+	return u.e.args().Premium
+}
+
 // LoID returns the LoID of this user.
+//
+// For logged in users, it's the same as ID.
 func (u User) LoID() (loid string, ok bool) {
+	loid, source := u.resolveID()
+	return loid, source != UserIDSourceNone
+}
+
+// resolveID implements the precedence of EdgeRequestContext.UserID.
+func (u User) resolveID() (id string, source UserIDSource) {
 	// First, we return the logged in user id if it's a logged in user.
 	if id, ok := u.ID(); ok {
-		return id, ok
+		return id, UserIDSourceToken
 	}
 
 	// Then, we use the loid from the thrift payload.
-	if u.e.raw.LoID != "" {
-		return u.e.raw.LoID, true
+	if loid := u.e.args().LoID; loid != "" {
+		return loid, UserIDSourceLoID
 	}
 
 	// Finally, we fallback to the loid from the JWT token.
 	token := u.e.AuthToken()
+	if token == nil || token.LoID.ID == "" {
+		return "", UserIDSourceNone
+	}
+	return token.LoID.ID, UserIDSourceLoID
+}
+
+// ReconciledLoID reconciles the LoID from the header with the LoID claim of the
+// validated auth token.
+//
+// See AuthenticationToken.ReconcileLoID for the details.
+// Unlike LoID, it never returns the user id of logged in users.
+func (u User) ReconciledLoID() (loid string, mismatch bool) {
+	headerLoID := u.e.args().LoID
+	token := u.e.AuthToken()
 	if token == nil {
-		return
+		return headerLoID, false
 	}
-	return token.LoID.ID, token.LoID.ID != ""
+	return token.ReconcileLoID(headerLoID)
 }
 
 // CookieCreatedAt returns the time the cookie was created.
 func (u User) CookieCreatedAt() (ts time.Time, ok bool) {
-	if !u.e.raw.LoIDCreatedAt.IsZero() {
-		return u.e.raw.LoIDCreatedAt, true
+	if ts := u.e.args().LoIDCreatedAt; !ts.IsZero() {
+		return ts, true
 	}
 	token := u.e.AuthToken()
 	if token == nil {
@@ -80,19 +134,14 @@ func (u User) Roles() []string {
 }
 
 // HasRole returns true if the user has the specific role.
+//
+// See AuthenticationToken.HasRole for how the roles are compared.
 func (u User) HasRole(role string) bool {
 	token := u.e.AuthToken()
 	if token == nil {
 		return false
 	}
-	// Since in most cases the roles slice would be quite small,
-	// it's better to iterate them than converting the slice into a set.
-	for _, r := range token.Roles {
-		if strings.ToLower(role) == strings.ToLower(r) {
-			return true
-		}
-	}
-	return false
+	return token.HasRole(role)
 }
 
 // UpdateExperimentEvent updates the passed in experiment event with user info.
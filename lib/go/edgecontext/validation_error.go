@@ -0,0 +1,78 @@
+package edgecontext
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Distinguishable errors returned by ValidateToken (and ValidateServiceToken),
+// so callers can map the failures to responses and metrics without string
+// matching.
+//
+// The original errors (e.g. jwt.ErrTokenExpired) are still wrapped,
+// so both of them work with errors.Is.
+var (
+	// ErrTokenExpired indicates that the token is expired.
+	ErrTokenExpired = errors.New("edgecontext.ValidateToken: token is expired")
+
+	// ErrBadSignature indicates that the signature of the token failed the
+	// verification.
+	ErrBadSignature = errors.New("edgecontext.ValidateToken: bad token signature")
+
+	// ErrWrongAlgorithm indicates that the token is signed with a method not
+	// accepted, see Config.JWTAlgorithms and Config.KeyAlgorithms.
+	ErrWrongAlgorithm = errors.New("edgecontext.ValidateToken: signing method not accepted")
+
+	// ErrMalformedToken indicates that the token can't be decoded.
+	ErrMalformedToken = errors.New("edgecontext.ValidateToken: malformed token")
+)
+
+// validationError wraps the error returned by the parser with one of the
+// distinguishable errors above.
+type validationError struct {
+	kind error
+	err  error
+}
+
+func (e *validationError) Error() string {
+	return e.kind.Error() + ": " + e.err.Error()
+}
+
+func (e *validationError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *validationError) Unwrap() error {
+	return e.err
+}
+
+// classifyTokenError wraps err returned by the parser with the matching
+// distinguishable error.
+//
+// keyLookedUp should be false if the parser returned err before looking up
+// the key for the signing method,
+// which means that the method is not accepted.
+func classifyTokenError(err error, keyLookedUp bool) error {
+	var ve *validationError
+	if err == nil || errors.As(err, &ve) {
+		return err
+	}
+
+	var kind error
+	switch {
+	case !keyLookedUp && (errors.Is(err, jwt.ErrTokenSignatureInvalid) || errors.Is(err, jwt.ErrTokenUnverifiable)):
+		kind = ErrWrongAlgorithm
+	case errors.Is(err, ErrKeyAlgorithmMismatch):
+		kind = ErrWrongAlgorithm
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		kind = ErrMalformedToken
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		kind = ErrBadSignature
+	case errors.Is(err, jwt.ErrTokenExpired):
+		kind = ErrTokenExpired
+	default:
+		return err
+	}
+	return &validationError{kind: kind, err: err}
+}
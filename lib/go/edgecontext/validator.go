@@ -1,12 +1,22 @@
 package edgecontext
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/reddit/baseplate.go/log"
 	"github.com/reddit/baseplate.go/secrets"
 	"golang.org/x/crypto/ssh"
@@ -14,26 +24,207 @@ import (
 
 type keysType struct {
 	// map of kid -> pub key.
-	m map[string]*rsa.PublicKey
+	m map[string]crypto.PublicKey
 
 	// when either kid header does not exist in the jwt token,
 	// or the kid is not present in the map,
-	// we fallback to the first (usually current) key.
-	first *rsa.PublicKey
+	// we fallback to the first (usually current) key usable by the signing
+	// method of the token, unless in strict mode.
+	all []crypto.PublicKey
+	// kids are the kids of all, or empty for keys without fingerprints.
+	kids []string
+
+	// map of kid -> the only signing method accepted for the key,
+	// see Config.KeyAlgorithms.
+	algs map[string]string
 }
 
-func (kt *keysType) getKey(kid string) *rsa.PublicKey {
-	if key := kt.m[kid]; key != nil {
-		return key
+// mergeAlgs merges the configured algs into kt.algs,
+// which take precedence over the ones from the keys.
+func (kt *keysType) mergeAlgs(algs map[string]string) {
+	if len(kt.algs) == 0 {
+		kt.algs = algs
+		return
+	}
+	for kid, alg := range algs {
+		kt.algs[kid] = alg
 	}
-	return kt.first
 }
 
+// allows returns true if the key of kid can be used with the signing method
+// according to kt.algs.
+func (kt *keysType) allows(kid string, method jwt.SigningMethod) bool {
+	alg, ok := kt.algs[kid]
+	return !ok || alg == method.Alg()
+}
+
+// Reasons of the kid fallbacks, used as the reason label of
+// keyIDFallbacks.
 const (
-	authenticationPubKeySecretPath = "secret/authentication/public-key"
-	jwtAlg                         = "RS256"
+	keyIDFallbackMissing  = "missing"
+	keyIDFallbackUnknown  = "unknown"
+	keyIDFallbackMismatch = "mismatch"
 )
 
+var keyIDFallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "edgecontext",
+	Subsystem: "jwt",
+	Name:      "kid_fallbacks_total",
+	Help:      "Total number of jwt tokens verified with the fallback key instead of the key of their kid header",
+}, []string{"reason"})
+
+// getKey returns the public key to verify the signature of a jwt token with
+// the kid header and the signing method.
+//
+// In strict mode the key must be found by kid,
+// otherwise it falls back to the first key usable by the method,
+// which is counted in keyIDFallbacks.
+func (kt *keysType) getKey(kid string, method jwt.SigningMethod, strict bool) (crypto.PublicKey, error) {
+	key := kt.m[kid]
+	if key != nil && !kt.allows(kid, method) {
+		// Never fallback to other keys here,
+		// as it's most likely an algorithm substitution.
+		return nil, fmt.Errorf(
+			"%w: key %q only accepts %q, got %q",
+			ErrKeyAlgorithmMismatch,
+			kid,
+			kt.algs[kid],
+			method.Alg(),
+		)
+	}
+	if key != nil && keyMatchesMethod(key, method) {
+		return key, nil
+	}
+
+	var reason string
+	switch {
+	case kid == "":
+		reason = keyIDFallbackMissing
+	case key == nil:
+		reason = keyIDFallbackUnknown
+	default:
+		reason = keyIDFallbackMismatch
+	}
+	if strict {
+		switch reason {
+		case keyIDFallbackMissing:
+			return nil, ErrMissingKeyID
+		case keyIDFallbackUnknown:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, kid)
+		}
+		return nil, fmt.Errorf("public key %q can't be used with signing method %q", kid, method.Alg())
+	}
+
+	for i, key := range kt.all {
+		if kt.allows(kt.kids[i], method) && keyMatchesMethod(key, method) {
+			keyIDFallbacks.WithLabelValues(reason).Inc()
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("no public key for signing method %q", method.Alg())
+}
+
+// keyMatchesMethod returns true if the public key can be used to verify the
+// signatures of the signing method.
+func keyMatchesMethod(key crypto.PublicKey, method jwt.SigningMethod) bool {
+	switch m := method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok := key.(*rsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodECDSA:
+		k, ok := key.(*ecdsa.PublicKey)
+		return ok && k.Curve.Params().BitSize == m.CurveBits
+	case *jwt.SigningMethodEd25519:
+		_, ok := key.(ed25519.PublicKey)
+		return ok
+	}
+	return false
+}
+
+// DefaultPubKeySecretPath is the path of the versioned secret in the secrets
+// store holding the public keys for jwt tokens,
+// used when Config.PubKeySecretPath is empty.
+const DefaultPubKeySecretPath = "secret/authentication/public-key"
+
+const jwtAlg = "RS256"
+
+// DefaultJWTAlgorithms are the signing methods accepted by ValidateToken when
+// Config.JWTAlgorithms is empty.
+var DefaultJWTAlgorithms = []string{
+	jwtAlg,
+}
+
+// supportedJWTAlgs are the signing methods supported by Config.JWTAlgorithms,
+// which are the ones verified with public keys (see keyMatchesMethod).
+var supportedJWTAlgs = map[string]bool{
+	"RS256": true,
+	"RS384": true,
+	"RS512": true,
+	"PS256": true,
+	"PS384": true,
+	"PS512": true,
+	"ES256": true,
+	"ES384": true,
+	"ES512": true,
+	"EdDSA": true,
+}
+
+// devJWTAlg is the signing method only accepted in dev mode.
+const devJWTAlg = "HS256"
+
+// jwtAlgs returns the signing methods accepted by ValidateToken,
+// which are the supported ones from cfg.JWTAlgorithms (or
+// DefaultJWTAlgorithms) and cfg.ExtraJWTAlgorithms,
+// and devJWTAlg in dev mode.
+func jwtAlgs(ctx context.Context, cfg Config) []string {
+	algs := supportedAlgs(ctx, cfg.Logger, cfg.JWTAlgorithms, cfg.ExtraJWTAlgorithms)
+	if len(cfg.InsecureDevModeHMACSecret) > 0 {
+		algs = append(algs, devJWTAlg)
+	}
+	return algs
+}
+
+// keyAlgs returns the supported signing methods from cfg.KeyAlgorithms.
+func keyAlgs(ctx context.Context, cfg Config) map[string]string {
+	if len(cfg.KeyAlgorithms) == 0 {
+		return nil
+	}
+	algs := make(map[string]string, len(cfg.KeyAlgorithms))
+	for kid, alg := range cfg.KeyAlgorithms {
+		if !supportedJWTAlgs[alg] {
+			cfg.Logger.Log(ctx, fmt.Sprintf("Unsupported JWT algorithm %q for key %q ignored.", alg, kid))
+			continue
+		}
+		algs[kid] = alg
+	}
+	return algs
+}
+
+// supportedAlgs returns the supported signing methods from configured (or
+// DefaultJWTAlgorithms) and extra.
+func supportedAlgs(ctx context.Context, logger log.Wrapper, configured, extra []string) []string {
+	if len(configured) == 0 {
+		configured = DefaultJWTAlgorithms
+	}
+	var algs []string
+	for _, list := range [][]string{configured, extra} {
+		for _, alg := range list {
+			if !supportedJWTAlgs[alg] {
+				logger.Log(ctx, fmt.Sprintf("Unsupported JWT algorithm %q ignored.", alg))
+				continue
+			}
+			if !containsString(algs, alg) {
+				algs = append(algs, alg)
+			}
+		}
+	}
+	if len(algs) == 0 {
+		logger.Log(ctx, "No supported JWT algorithms configured, using DefaultJWTAlgorithms.")
+		algs = append(algs, DefaultJWTAlgorithms...)
+	}
+	return algs
+}
+
 // JWTHeaderKeyID is the JWT header for the key id,
 // as defined in RFC 7517 section 4.5.
 const JWTHeaderKeyID = "kid"
@@ -53,20 +244,73 @@ var (
 	// *AuthenticationToken.
 	ErrInvalidTokenType = errors.New("edgecontext.ValidateToken: invalid token type")
 
+	// ErrMissingRequiredClaim is an error returned by ValidateToken indicates
+	// that the token does not have one of the Config.RequiredClaims.
+	ErrMissingRequiredClaim = errors.New("edgecontext.ValidateToken: missing required claim")
+
+	// ErrUnexpectedTokenTypeHeader is an error returned by ValidateToken
+	// indicates that the typ header of the JWT token does not match
+	// Config.TokenTypeHeader.
+	ErrUnexpectedTokenTypeHeader = errors.New("edgecontext.ValidateToken: unexpected typ header")
+
 	// ErrNoPublicKeysLoaded is an error returned by ValidateToken indicates that
 	// the function is called before any public keys are loaded from secrets.
 	ErrNoPublicKeysLoaded = errors.New("edgecontext.ValidateToken: no public keys loaded")
+
+	// ErrMissingKeyID is an error returned by ValidateToken with
+	// Config.StrictKeyID indicates that the JWT token does not have the kid
+	// header.
+	ErrMissingKeyID = errors.New("edgecontext.ValidateToken: missing kid header")
+
+	// ErrUnknownKeyID is an error returned by ValidateToken with
+	// Config.StrictKeyID indicates that the kid header of the JWT token does
+	// not match any of the loaded public keys.
+	ErrUnknownKeyID = errors.New("edgecontext.ValidateToken: unknown kid header")
+
+	// ErrKeyAlgorithmMismatch is an error returned by ValidateToken indicates
+	// that the JWT token is signed with a different method than the one
+	// configured for its kid in Config.KeyAlgorithms.
+	ErrKeyAlgorithmMismatch = errors.New("edgecontext.ValidateToken: signing method not allowed for the key")
 )
 
 // ValidateToken parses and validates a jwt token, and return the decoded
 // AuthenticationToken.
+//
+// Expired, malformed tokens and tokens with bad signatures or signing methods
+// are rejected with ErrTokenExpired, ErrMalformedToken, ErrBadSignature and
+// ErrWrongAlgorithm respectively.
+//
+// Tokens with PASETOv4PublicPrefix are validated as PASETO v4 public tokens
+// instead, see Config.PASETOPubKeySecretPath.
+// When Config.TokenIntrospector is set,
+// opaque tokens (neither JWT nor PASETO) are introspected with it.
+// When Config.RevocationChecker is set,
+// it's consulted for every validated (and cached) token.
+//
+// Tokens bound to a DPoP key are only rejected when DPoPConfig.RequireProof
+// is true, see ValidateTokenWithDPoP.
+//
+// If the Impl was initialized with a positive TokenCacheSize,
+// previously validated tokens are returned from the cache.
+// The returned AuthenticationToken should be treated as read-only.
 func (impl *Impl) ValidateToken(token string) (*AuthenticationToken, error) {
-	keys, ok := impl.keysValue.Load().(*keysType)
-	if !ok {
-		// This would only happen when all previous middleware parsing failed.
-		return nil, ErrNoPublicKeysLoaded
+	claims, err := impl.validateToken(token, false)
+	if err != nil {
+		return nil, err
 	}
+	if impl.dpop.RequireProof {
+		if _, bound := claims.DPoPThumbprint(); bound {
+			return nil, ErrDPoPProofRequired
+		}
+	}
+	return claims, nil
+}
 
+// validateToken implements ValidateToken.
+//
+// When allowExpired is true, expired tokens passing all the other validations
+// are also returned (but not cached), for ClaimsFromExpiredToken.
+func (impl *Impl) validateToken(token string, allowExpired bool) (*AuthenticationToken, error) {
 	if token == "" {
 		// If we don't do the special handling here,
 		// jwt.ParseWithClaims below will return an error with message
@@ -78,58 +322,387 @@ func (impl *Impl) ValidateToken(token string) (*AuthenticationToken, error) {
 		return nil, ErrEmptyToken
 	}
 
-	tok, err := jwt.ParseWithClaims(
+	if impl.tokenCache != nil {
+		if cached := impl.tokenCache.get(token, time.Now()); cached != nil {
+			return impl.checkRevocation(cached)
+		}
+	}
+
+	var (
+		claims *AuthenticationToken
+		err    error
+	)
+	switch {
+	case strings.HasPrefix(token, PASETOv4PublicPrefix):
+		claims, err = impl.parsePASETO(token, time.Now())
+	case impl.introspector != nil && !isJWT(token):
+		claims, err = impl.introspect(token, time.Now())
+	default:
+		if iss := impl.issuerOf(token); iss != nil {
+			claims, err = iss.parseJWT(impl, token)
+			break
+		}
+		// Only jwt tokens of the default issuer require the default keys.
+		keys, keysErr := impl.loadKeys()
+		if keysErr != nil {
+			return nil, keysErr
+		}
+		claims, err = impl.parseJWT(impl.jwtParser(), keys, token)
+	}
+	// The parsers only return the claims along with an error when the token is
+	// expired but otherwise valid.
+	expired := err != nil && claims != nil
+	if err != nil && !(allowExpired && expired) {
+		return nil, classifyTokenError(err, true)
+	}
+
+	for _, name := range impl.requiredClaims {
+		if !claims.HasClaim(name) {
+			return nil, fmt.Errorf("%w: %q", ErrMissingRequiredClaim, name)
+		}
+	}
+	if len(impl.audiences) > 0 && !audienceMatches(claims.Audience, impl.audiences) {
+		return nil, fmt.Errorf("%w: %q", jwt.ErrTokenInvalidAudience, []string(claims.Audience))
+	}
+	if _, err := impl.checkRevocation(claims); err != nil {
+		return nil, err
+	}
+	if impl.tokenCache != nil && !expired {
+		impl.tokenCache.add(token, claims, time.Now())
+	}
+	return claims, nil
+}
+
+// parseJWT parses and validates a non-empty jwt token.
+//
+// If the token is expired but otherwise valid,
+// the claims are returned along with the error.
+func (impl *Impl) parseJWT(parser *jwt.Parser, keys *keysType, token string) (*AuthenticationToken, error) {
+	tok, err := impl.parseToken(parser, keys, token, &AuthenticationToken{})
+	if err != nil && (tok == nil || !onlyExpired(err)) {
+		return nil, err
+	}
+
+	if impl.tokenType != "" {
+		typ, _ := tok.Header["typ"].(string)
+		if !tokenTypeMatches(typ, impl.tokenType) {
+			return nil, fmt.Errorf("%w: %q", ErrUnexpectedTokenTypeHeader, typ)
+		}
+	}
+
+	if claims, ok := tok.Claims.(*AuthenticationToken); ok {
+		return claims, err
+	}
+
+	return nil, fmt.Errorf("%w: %T", ErrInvalidTokenType, tok.Claims)
+}
+
+// audienceMatches returns true if any of the audiences of the token is
+// expected.
+func audienceMatches(audience jwt.ClaimStrings, expected []string) bool {
+	for _, aud := range audience {
+		if containsString(expected, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenTypeMatches compares the typ header of a jwt token against the
+// expected value.
+//
+// As recommended by RFC 7515 section 4.1.9, the comparison is
+// case-insensitive and the "application/" prefix is optional.
+func tokenTypeMatches(typ, expected string) bool {
+	const prefix = "application/"
+	trim := func(s string) string {
+		if len(s) > len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+			return s[len(prefix):]
+		}
+		return s
+	}
+	return strings.EqualFold(trim(typ), trim(expected))
+}
+
+// loadKeys returns the currently loaded public keys.
+func (impl *Impl) loadKeys() (*keysType, error) {
+	keys, ok := impl.keysValue.Load().(*keysType)
+	if !ok {
+		if len(impl.devHMACSecret) > 0 {
+			// Public keys are optional in dev mode.
+			return &keysType{}, nil
+		}
+		// This would only happen when all previous middleware parsing failed.
+		return nil, ErrNoPublicKeysLoaded
+	}
+	return keys, nil
+}
+
+// parseToken parses and verifies the signature of a non-empty jwt token into
+// claims.
+//
+// The parsed token is also returned along with the error when the claims
+// validation failed, see onlyExpired.
+func (impl *Impl) parseToken(parser *jwt.Parser, keys *keysType, token string, claims jwt.Claims) (*jwt.Token, error) {
+	var keyLookedUp bool
+	tok, err := parser.ParseWithClaims(
 		token,
-		&AuthenticationToken{},
+		claims,
 		func(jt *jwt.Token) (interface{}, error) {
+			keyLookedUp = true
+			if _, ok := jt.Method.(*jwt.SigningMethodHMAC); ok {
+				// Only reachable in dev mode, as HS256 is not a valid method
+				// otherwise.
+				if len(impl.devHMACSecret) == 0 {
+					return nil, fmt.Errorf("signing method %q is only supported in dev mode", jt.Method.Alg())
+				}
+				return impl.devHMACSecret, nil
+			}
 			kid, _ := jt.Header[JWTHeaderKeyID].(string)
-			return keys.getKey(kid), nil
+			return keys.getKey(kid, jt.Method, impl.strictKeyID)
 		},
-		jwt.WithValidMethods([]string{jwtAlg}),
 	)
 	if err != nil {
-		return nil, err
+		return tok, classifyTokenError(err, keyLookedUp)
 	}
 
 	if !tok.Valid {
 		return nil, ErrInvalidToken
 	}
+	return tok, nil
+}
 
-	if claims, ok := tok.Claims.(*AuthenticationToken); ok {
-		return claims, nil
-	}
+// defaultJWTParser is used by ValidateToken when the Impl was not created by
+// Init.
+var defaultJWTParser = newJWTParser(DefaultJWTAlgorithms, nil)
 
-	return nil, fmt.Errorf("%w: %T", ErrInvalidTokenType, tok.Claims)
+// newJWTParser creates the jwt.Parser used by ValidateToken.
+//
+// The valid methods are always restricted to algs,
+// which is checked before the signature verification,
+// additional options are applied after that.
+func newJWTParser(algs []string, options []jwt.ParserOption) *jwt.Parser {
+	return jwt.NewParser(append(
+		[]jwt.ParserOption{jwt.WithValidMethods(algs)},
+		options...,
+	)...)
+}
+
+// jwtParser returns the jwt.Parser configured for impl.
+func (impl *Impl) jwtParser() *jwt.Parser {
+	if impl.parser == nil {
+		return defaultJWTParser
+	}
+	return impl.parser
 }
 
 func (impl *Impl) validatorMiddleware(next secrets.SecretHandlerFunc) secrets.SecretHandlerFunc {
 	return func(sec *secrets.Secrets) {
 		defer next(sec)
 
-		versioned, err := sec.GetVersionedSecret(authenticationPubKeySecretPath)
-		if err != nil {
-			impl.logger.Log(context.Background(), fmt.Sprintf(
-				"Failed to get secrets %q: %v",
-				authenticationPubKeySecretPath,
-				err,
-			))
+		impl.updatePASETOKeys(sec)
+		impl.updateIssuerKeys(sec)
+		if impl.externalKeys {
+			return
+		}
+
+		var all []secrets.Secret
+		for _, path := range impl.pubKeySecretPaths {
+			keys, err := impl.getPubKeySecrets(sec, path)
+			if err != nil {
+				impl.logger.Log(context.Background(), fmt.Sprintf(
+					"Failed to get secrets %q: %v",
+					path,
+					err,
+				))
+				continue
+			}
+			all = append(all, keys...)
+		}
+		if len(all) == 0 {
 			return
 		}
 
-		keys := parseVersionedKeys(context.Background(), versioned, impl.logger)
+		keys := parseKeys(context.Background(), all, "secrets store", impl.logger)
 		if keys != nil {
-			impl.keysValue.Store(keys)
+			keys.mergeAlgs(impl.keyAlgs)
+			impl.storeKeys(keys)
 		}
 	}
 }
 
+// storeKeys swaps in the new default key set.
+//
+// The token cache is only purged and the OnKeysUpdated callbacks are only
+// called when the keys actually changed.
+func (impl *Impl) storeKeys(keys *keysType) {
+	old, _ := impl.keysValue.Load().(*keysType)
+	impl.keysValue.Store(keys)
+	impl.markKeysRefreshed()
+	if keys.equal(old) {
+		return
+	}
+	impl.purgeTokenCache()
+
+	impl.keysUpdatedLock.Lock()
+	callbacks := impl.keysUpdated
+	impl.keysUpdatedLock.Unlock()
+	if len(callbacks) == 0 {
+		return
+	}
+	fingerprints := keys.fingerprints()
+	for _, fn := range callbacks {
+		fn(fingerprints)
+	}
+}
+
+// purgeTokenCache purges the token cache after any key set is updated,
+// as the tokens in the cache could be signed by keys no longer trusted.
+func (impl *Impl) purgeTokenCache() {
+	if impl.tokenCache != nil {
+		impl.tokenCache.purge()
+	}
+}
+
+// OnKeysUpdated registers fn to be called with the fingerprints (see
+// PublicKeyFingerprint) of the new keys every time the default key set for jwt
+// tokens is changed, from the secrets store, JWKS, KeyDir or LocalKeys.
+//
+// Refreshes with the same keys in the same order don't call fn.
+//
+// It's only called for the updates after the registration,
+// synchronously from the goroutine loading the keys,
+// so fn should return quickly.
+// The keys are already used for validation when fn is called.
+func (impl *Impl) OnKeysUpdated(fn func(fingerprints []string)) {
+	impl.keysUpdatedLock.Lock()
+	defer impl.keysUpdatedLock.Unlock()
+	impl.keysUpdated = append(impl.keysUpdated, fn)
+}
+
+// KeyFingerprints returns the fingerprints (see PublicKeyFingerprint) of the
+// default public keys currently trusted for jwt tokens,
+// excluding the keys of Config.Issuers,
+// in the order they are tried when the token has no kid.
+//
+// It returns nil when no keys are loaded.
+func (impl *Impl) KeyFingerprints() []string {
+	keys, ok := impl.keysValue.Load().(*keysType)
+	if !ok {
+		return nil
+	}
+	return keys.fingerprints()
+}
+
+// KeyCount returns the number of the default public keys currently trusted for
+// jwt tokens, excluding the keys of Config.Issuers.
+func (impl *Impl) KeyCount() int {
+	keys, ok := impl.keysValue.Load().(*keysType)
+	if !ok {
+		return 0
+	}
+	return len(keys.all)
+}
+
+// fingerprints returns the fingerprints of all the keys, in order.
+//
+// Keys without fingerprints are skipped.
+func (kt *keysType) fingerprints() []string {
+	fingerprints := make([]string, 0, len(kt.all))
+	for _, key := range kt.all {
+		if fingerprint, err := PublicKeyFingerprint(key); err == nil {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+	}
+	return fingerprints
+}
+
+// equal returns true if kt and other have the same keys in the same order,
+// with the same signing methods,
+// so swapping one for the other is not a key rotation.
+func (kt *keysType) equal(other *keysType) bool {
+	if kt == nil || other == nil {
+		return kt == other
+	}
+	if len(kt.all) != len(other.all) || !stringMapsEqual(kt.algs, other.algs) {
+		return false
+	}
+	a, b := kt.fingerprints(), other.fingerprints()
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// getPubKeySecrets returns all the versions of the versioned secret at path,
+// or the simple secret at path when allowed by
+// Config.AllowSimplePubKeySecrets.
+func (impl *Impl) getPubKeySecrets(sec *secrets.Secrets, path string) ([]secrets.Secret, error) {
+	versioned, err := sec.GetVersionedSecret(path)
+	if err == nil {
+		return versioned.GetAll(), nil
+	}
+	if impl.simplePubKeys {
+		if simple, simpleErr := sec.GetSimpleSecret(path); simpleErr == nil {
+			return []secrets.Secret{simple.Value}, nil
+		}
+	}
+	return nil, err
+}
+
+// pubKeySecretPaths returns the paths of the versioned secrets holding the
+// default keys configured by cfg, in order.
+func pubKeySecretPaths(cfg Config) []string {
+	path := cfg.PubKeySecretPath
+	if path == "" {
+		path = DefaultPubKeySecretPath
+	}
+	return append([]string{path}, cfg.ExtraPubKeySecretPaths...)
+}
+
 func parseVersionedKeys(ctx context.Context, versioned secrets.VersionedSecret, logger log.Wrapper) *keysType {
-	all := versioned.GetAll()
+	return parseKeys(ctx, versioned.GetAll(), "secrets store", logger)
+}
+
+// parseKeys parses the public keys, each of them in PEM (public key or
+// certificate), JWK or JWK Set format.
+//
+// It returns nil when there are no valid keys, with source in the log.
+func parseKeys(ctx context.Context, all []secrets.Secret, source string, logger log.Wrapper) *keysType {
 	keys := &keysType{
-		m: make(map[string]*rsa.PublicKey, len(all)),
+		m: make(map[string]crypto.PublicKey, len(all)),
 	}
 	for i, v := range all {
-		key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(v))
+		if isJSONObject([]byte(v)) {
+			jwkKeys, err := parseJWKJSON([]byte(v))
+			if err != nil {
+				logger.Log(ctx, fmt.Sprintf(
+					"Failed to parse JWK key #%d: %v",
+					i,
+					err,
+				))
+				continue
+			}
+			for j, k := range jwkKeys {
+				if err := keys.addJWK(k); err != nil {
+					logger.Log(ctx, fmt.Sprintf(
+						"Failed to parse JWK key #%d.%d %q: %v",
+						i,
+						j,
+						k.Kid,
+						err,
+					))
+				}
+			}
+			continue
+		}
+
+		key, err := parsePublicKey([]byte(v))
 		if err != nil {
 			logger.Log(ctx, fmt.Sprintf(
 				"Failed to parse key #%d: %v",
@@ -137,31 +710,89 @@ func parseVersionedKeys(ctx context.Context, versioned secrets.VersionedSecret,
 				err,
 			))
 		} else {
-			if keys.first == nil {
-				keys.first = key
-			}
-			if fingerprint, err := RSAPublicKeyFingerprint(key); err != nil {
+			fingerprint, err := PublicKeyFingerprint(key)
+			if err != nil {
 				logger.Log(ctx, fmt.Sprintf(
 					"Failed to get fingerprint of key #%d: %v",
 					i,
 					err,
 				))
 			} else {
+				if _, ok := keys.m[fingerprint]; ok {
+					// The same key from another secret version or path.
+					continue
+				}
 				keys.m[fingerprint] = key
 			}
+			keys.all = append(keys.all, key)
+			keys.kids = append(keys.kids, fingerprint)
 		}
 	}
-	if keys.first == nil {
-		logger.Log(ctx, "No valid keys in secrets store.")
+	if len(keys.all) == 0 {
+		logger.Log(ctx, fmt.Sprintf("No valid keys in %s.", source))
 		return nil
 	}
 	return keys
 }
 
+// isJSONObject returns true if the secret looks like a JSON object instead of
+// PEM.
+func isJSONObject(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// pemCertificateType is the PEM block type of x509 certificates.
+const pemCertificateType = "CERTIFICATE"
+
+// parsePublicKey parses a PEM encoded RSA, ECDSA or Ed25519 public key,
+// or the public key of a PEM encoded x509 certificate.
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil && block.Type == pemCertificateType {
+		return parseCertificatePublicKey(block.Bytes)
+	}
+
+	rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err == nil {
+		return rsaKey, nil
+	}
+	if ecKey, ecErr := jwt.ParseECPublicKeyFromPEM(data); ecErr == nil {
+		return ecKey, nil
+	}
+	if edKey, edErr := jwt.ParseEdPublicKeyFromPEM(data); edErr == nil {
+		return edKey, nil
+	}
+	return nil, err
+}
+
+// parseCertificatePublicKey extracts the public key of a DER encoded x509
+// certificate.
+//
+// Only the public key is used, the validity period and the chain of the
+// certificate are not verified,
+// so they should be managed by the pipeline publishing the certificates.
+func parseCertificatePublicKey(der []byte) (crypto.PublicKey, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported certificate public key type %T", cert.PublicKey)
+}
+
 // RSAPublicKeyFingerprint calculates the fingerprint of an RSA public key,
 // using ssh.FingerprintSHA256:
 // https://pkg.go.dev/golang.org/x/crypto/ssh#FingerprintSHA256
 func RSAPublicKeyFingerprint(pubKey *rsa.PublicKey) (string, error) {
+	return PublicKeyFingerprint(pubKey)
+}
+
+// PublicKeyFingerprint is like RSAPublicKeyFingerprint,
+// but also supports *ecdsa.PublicKey and ed25519.PublicKey.
+func PublicKeyFingerprint(pubKey crypto.PublicKey) (string, error) {
 	key, err := ssh.NewPublicKey(pubKey)
 	if err != nil {
 		return "", err
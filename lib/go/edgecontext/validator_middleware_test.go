@@ -3,7 +3,10 @@ package edgecontext
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/reddit/baseplate.go/log"
 	"github.com/reddit/baseplate.go/secrets"
 )
@@ -169,13 +172,96 @@ func TestParseVersionedKeys(t *testing.T) {
 			}
 			compareUnorderedFingerprints(t, fingerprints, c.fingerprints)
 
-			fingerprint, err := RSAPublicKeyFingerprint(keys.first)
+			fingerprint, err := PublicKeyFingerprint(keys.all[0])
 			if err != nil {
-				t.Errorf("Unable to calculate fingerprint of keys.first: %v", err)
+				t.Errorf("Unable to calculate fingerprint of keys.all[0]: %v", err)
 			}
 			if fingerprint != c.firstFingerprint {
-				t.Errorf("keys.first fingerprint got %q, want %q", fingerprint, c.firstFingerprint)
+				t.Errorf("keys.all[0] fingerprint got %q, want %q", fingerprint, c.firstFingerprint)
 			}
 		})
 	}
 }
+
+func TestGetKeyFallbackMetric(t *testing.T) {
+	keys := parseVersionedKeys(context.Background(), secrets.VersionedSecret{
+		Current: []byte(validKey1),
+	}, log.TestWrapper(t))
+	if keys == nil {
+		t.Fatal("Unexpected nil result")
+	}
+
+	for _, c := range []struct {
+		kid    string
+		reason string
+	}{
+		{kid: fingerprint1},
+		{kid: "", reason: keyIDFallbackMissing},
+		{kid: fingerprint2, reason: keyIDFallbackUnknown},
+	} {
+		t.Run(c.kid, func(t *testing.T) {
+			counter := func(reason string) float64 {
+				return testutil.ToFloat64(keyIDFallbacks.WithLabelValues(reason))
+			}
+			before := map[string]float64{
+				keyIDFallbackMissing: counter(keyIDFallbackMissing),
+				keyIDFallbackUnknown: counter(keyIDFallbackUnknown),
+			}
+			key, err := keys.getKey(c.kid, jwt.SigningMethodRS256, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if key != keys.all[0] {
+				t.Errorf("Expected keys.all[0], got %v", key)
+			}
+			for reason, n := range before {
+				want := n
+				if reason == c.reason {
+					want++
+				}
+				if got := counter(reason); got != want {
+					t.Errorf("Counter of %q: want %v, got %v", reason, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyStaleness(t *testing.T) {
+	var logged int
+	impl := &Impl{
+		logger: func(_ context.Context, msg string) {
+			logged++
+		},
+		maxKeyStaleness: time.Minute,
+	}
+	ctx := context.Background()
+	collector := impl.KeyStalenessCollector()
+
+	if _, ok := impl.keyStaleness(ctx); ok {
+		t.Error("Keys never loaded reported with staleness")
+	}
+
+	impl.markKeysRefreshed()
+	if got := testutil.ToFloat64(collector); got >= time.Minute.Seconds() {
+		t.Errorf("Staleness gauge got %v, want < %v", got, time.Minute.Seconds())
+	}
+
+	impl.keysRefreshed.Store(time.Now().Add(-time.Hour))
+	if got := testutil.ToFloat64(collector); got < time.Hour.Seconds() {
+		t.Errorf("Staleness gauge got %v, want >= %v", got, time.Hour.Seconds())
+	}
+	// Only warned once while staying stale.
+	impl.keyStaleness(ctx)
+	if logged != 1 {
+		t.Errorf("Expected 1 warning, got %d", logged)
+	}
+
+	// Warned again after the next refresh went stale.
+	impl.markKeysRefreshed()
+	impl.keysRefreshed.Store(time.Now().Add(-time.Hour))
+	impl.keyStaleness(ctx)
+	if logged != 2 {
+		t.Errorf("Expected 2 warnings, got %d", logged)
+	}
+}
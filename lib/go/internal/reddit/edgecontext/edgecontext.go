@@ -198,8 +198,26 @@ func (p *Loid) String() string {
 // Attributes:
 //  - ID: The ID of the Session tracker cookie.
 // 
+//  - CreatedMs: The time when the session was created, in number of milliseconds since
+// epoch.
+// 
+//  - Type: The type of the session, one of "cookie", "mobile" or "api".
+// 
+//  - ElevatedUntilMs: If the session recently completed step-up authentication, the time
+// until when it's considered elevated, in number of milliseconds since epoch.
+// 
+//  - Mfa: Whether the session was authenticated with multi-factor authentication.
+// 
+//  - AuthMethod: How the user authenticated, one of "password", "google", "apple",
+// "magic_link" or "refresh".
+// 
 type Session struct {
   ID string `thrift:"id,1" db:"id" json:"id"`
+  CreatedMs *int64 `thrift:"created_ms,2" db:"created_ms" json:"created_ms,omitempty"`
+  Type *string `thrift:"type,3" db:"type" json:"type,omitempty"`
+  ElevatedUntilMs *int64 `thrift:"elevated_until_ms,4" db:"elevated_until_ms" json:"elevated_until_ms,omitempty"`
+  Mfa *bool `thrift:"mfa,5" db:"mfa" json:"mfa,omitempty"`
+  AuthMethod *string `thrift:"auth_method,6" db:"auth_method" json:"auth_method,omitempty"`
 }
 
 func NewSession() *Session {
@@ -210,6 +228,61 @@ func NewSession() *Session {
 func (p *Session) GetID() string {
   return p.ID
 }
+var Session_CreatedMs_DEFAULT int64
+func (p *Session) GetCreatedMs() int64 {
+  if !p.IsSetCreatedMs() {
+    return Session_CreatedMs_DEFAULT
+  }
+return *p.CreatedMs
+}
+var Session_Type_DEFAULT string
+func (p *Session) GetType() string {
+  if !p.IsSetType() {
+    return Session_Type_DEFAULT
+  }
+return *p.Type
+}
+var Session_ElevatedUntilMs_DEFAULT int64
+func (p *Session) GetElevatedUntilMs() int64 {
+  if !p.IsSetElevatedUntilMs() {
+    return Session_ElevatedUntilMs_DEFAULT
+  }
+return *p.ElevatedUntilMs
+}
+var Session_Mfa_DEFAULT bool
+func (p *Session) GetMfa() bool {
+  if !p.IsSetMfa() {
+    return Session_Mfa_DEFAULT
+  }
+return *p.Mfa
+}
+var Session_AuthMethod_DEFAULT string
+func (p *Session) GetAuthMethod() string {
+  if !p.IsSetAuthMethod() {
+    return Session_AuthMethod_DEFAULT
+  }
+return *p.AuthMethod
+}
+func (p *Session) IsSetCreatedMs() bool {
+  return p.CreatedMs != nil
+}
+
+func (p *Session) IsSetType() bool {
+  return p.Type != nil
+}
+
+func (p *Session) IsSetElevatedUntilMs() bool {
+  return p.ElevatedUntilMs != nil
+}
+
+func (p *Session) IsSetMfa() bool {
+  return p.Mfa != nil
+}
+
+func (p *Session) IsSetAuthMethod() bool {
+  return p.AuthMethod != nil
+}
+
 func (p *Session) Read(ctx context.Context, iprot thrift.TProtocol) error {
   if _, err := iprot.ReadStructBegin(ctx); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -233,6 +306,56 @@ func (p *Session) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
+    case 2:
+      if fieldTypeId == thrift.I64 {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 4:
+      if fieldTypeId == thrift.I64 {
+        if err := p.ReadField4(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 5:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField5(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 6:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField6(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
     default:
       if err := iprot.Skip(ctx, fieldTypeId); err != nil {
         return err
@@ -257,11 +380,61 @@ func (p *Session)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error
   return nil
 }
 
+func (p *Session)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadI64(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.CreatedMs = &v
+}
+  return nil
+}
+
+func (p *Session)  ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 3: ", err)
+} else {
+  p.Type = &v
+}
+  return nil
+}
+
+func (p *Session)  ReadField4(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadI64(ctx); err != nil {
+  return thrift.PrependError("error reading field 4: ", err)
+} else {
+  p.ElevatedUntilMs = &v
+}
+  return nil
+}
+
+func (p *Session)  ReadField5(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 5: ", err)
+} else {
+  p.Mfa = &v
+}
+  return nil
+}
+
+func (p *Session)  ReadField6(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 6: ", err)
+} else {
+  p.AuthMethod = &v
+}
+  return nil
+}
+
 func (p *Session) Write(ctx context.Context, oprot thrift.TProtocol) error {
   if err := oprot.WriteStructBegin(ctx, "Session"); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
   if p != nil {
     if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+    if err := p.writeField3(ctx, oprot); err != nil { return err }
+    if err := p.writeField4(ctx, oprot); err != nil { return err }
+    if err := p.writeField5(ctx, oprot); err != nil { return err }
+    if err := p.writeField6(ctx, oprot); err != nil { return err }
   }
   if err := oprot.WriteFieldStop(ctx); err != nil {
     return thrift.PrependError("write field stop error: ", err) }
@@ -280,6 +453,66 @@ func (p *Session) writeField1(ctx context.Context, oprot thrift.TProtocol) (err
   return err
 }
 
+func (p *Session) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetCreatedMs() {
+    if err := oprot.WriteFieldBegin(ctx, "created_ms", thrift.I64, 2); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:created_ms: ", p), err) }
+    if err := oprot.WriteI64(ctx, int64(*p.CreatedMs)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.created_ms (2) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 2:created_ms: ", p), err) }
+  }
+  return err
+}
+
+func (p *Session) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetType() {
+    if err := oprot.WriteFieldBegin(ctx, "type", thrift.STRING, 3); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:type: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.Type)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.type (3) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 3:type: ", p), err) }
+  }
+  return err
+}
+
+func (p *Session) writeField4(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetElevatedUntilMs() {
+    if err := oprot.WriteFieldBegin(ctx, "elevated_until_ms", thrift.I64, 4); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:elevated_until_ms: ", p), err) }
+    if err := oprot.WriteI64(ctx, int64(*p.ElevatedUntilMs)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.elevated_until_ms (4) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 4:elevated_until_ms: ", p), err) }
+  }
+  return err
+}
+
+func (p *Session) writeField5(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetMfa() {
+    if err := oprot.WriteFieldBegin(ctx, "mfa", thrift.BOOL, 5); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:mfa: ", p), err) }
+    if err := oprot.WriteBool(ctx, bool(*p.Mfa)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.mfa (5) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 5:mfa: ", p), err) }
+  }
+  return err
+}
+
+func (p *Session) writeField6(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetAuthMethod() {
+    if err := oprot.WriteFieldBegin(ctx, "auth_method", thrift.STRING, 6); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:auth_method: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.AuthMethod)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.auth_method (6) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 6:auth_method: ", p), err) }
+  }
+  return err
+}
+
 func (p *Session) Equals(other *Session) bool {
   if p == other {
     return true
@@ -287,6 +520,36 @@ func (p *Session) Equals(other *Session) bool {
     return false
   }
   if p.ID != other.ID { return false }
+  if p.CreatedMs != other.CreatedMs {
+    if p.CreatedMs == nil || other.CreatedMs == nil {
+      return false
+    }
+    if (*p.CreatedMs) != (*other.CreatedMs) { return false }
+  }
+  if p.Type != other.Type {
+    if p.Type == nil || other.Type == nil {
+      return false
+    }
+    if (*p.Type) != (*other.Type) { return false }
+  }
+  if p.ElevatedUntilMs != other.ElevatedUntilMs {
+    if p.ElevatedUntilMs == nil || other.ElevatedUntilMs == nil {
+      return false
+    }
+    if (*p.ElevatedUntilMs) != (*other.ElevatedUntilMs) { return false }
+  }
+  if p.Mfa != other.Mfa {
+    if p.Mfa == nil || other.Mfa == nil {
+      return false
+    }
+    if (*p.Mfa) != (*other.Mfa) { return false }
+  }
+  if p.AuthMethod != other.AuthMethod {
+    if p.AuthMethod == nil || other.AuthMethod == nil {
+      return false
+    }
+    if (*p.AuthMethod) != (*other.AuthMethod) { return false }
+  }
   return true
 }
 
@@ -308,8 +571,26 @@ func (p *Session) String() string {
 // Attributes:
 //  - ID: The ID of the device.
 // 
+//  - Model: The model of the device, e.g. "iPhone14,2" or "Pixel 7".
+// 
+//  - OsName: The name of the operating system of the device, e.g. "iOS" or "Android".
+// 
+//  - OsVersion: The version of the operating system of the device, e.g. "16.4.1".
+// 
+//  - FormFactor: The coarse form factor of the device, one of "phone", "tablet",
+// "desktop", "tv" or "bot".
+// 
+//  - AttestationVerdict: The verdict of the mobile device attestation (Play Integrity or App
+// Attest) evaluated at the edge, one of "strong", "device", "basic",
+// "failed" or "unavailable".
+// 
 type Device struct {
   ID string `thrift:"id,1" db:"id" json:"id"`
+  Model *string `thrift:"model,2" db:"model" json:"model,omitempty"`
+  OsName *string `thrift:"os_name,3" db:"os_name" json:"os_name,omitempty"`
+  OsVersion *string `thrift:"os_version,4" db:"os_version" json:"os_version,omitempty"`
+  FormFactor *string `thrift:"form_factor,5" db:"form_factor" json:"form_factor,omitempty"`
+  AttestationVerdict *string `thrift:"attestation_verdict,6" db:"attestation_verdict" json:"attestation_verdict,omitempty"`
 }
 
 func NewDevice() *Device {
@@ -320,6 +601,61 @@ func NewDevice() *Device {
 func (p *Device) GetID() string {
   return p.ID
 }
+var Device_Model_DEFAULT string
+func (p *Device) GetModel() string {
+  if !p.IsSetModel() {
+    return Device_Model_DEFAULT
+  }
+return *p.Model
+}
+var Device_OsName_DEFAULT string
+func (p *Device) GetOsName() string {
+  if !p.IsSetOsName() {
+    return Device_OsName_DEFAULT
+  }
+return *p.OsName
+}
+var Device_OsVersion_DEFAULT string
+func (p *Device) GetOsVersion() string {
+  if !p.IsSetOsVersion() {
+    return Device_OsVersion_DEFAULT
+  }
+return *p.OsVersion
+}
+var Device_FormFactor_DEFAULT string
+func (p *Device) GetFormFactor() string {
+  if !p.IsSetFormFactor() {
+    return Device_FormFactor_DEFAULT
+  }
+return *p.FormFactor
+}
+var Device_AttestationVerdict_DEFAULT string
+func (p *Device) GetAttestationVerdict() string {
+  if !p.IsSetAttestationVerdict() {
+    return Device_AttestationVerdict_DEFAULT
+  }
+return *p.AttestationVerdict
+}
+func (p *Device) IsSetModel() bool {
+  return p.Model != nil
+}
+
+func (p *Device) IsSetOsName() bool {
+  return p.OsName != nil
+}
+
+func (p *Device) IsSetOsVersion() bool {
+  return p.OsVersion != nil
+}
+
+func (p *Device) IsSetFormFactor() bool {
+  return p.FormFactor != nil
+}
+
+func (p *Device) IsSetAttestationVerdict() bool {
+  return p.AttestationVerdict != nil
+}
+
 func (p *Device) Read(ctx context.Context, iprot thrift.TProtocol) error {
   if _, err := iprot.ReadStructBegin(ctx); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -343,6 +679,56 @@ func (p *Device) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
+    case 2:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 4:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField4(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 5:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField5(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 6:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField6(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
     default:
       if err := iprot.Skip(ctx, fieldTypeId); err != nil {
         return err
@@ -367,40 +753,180 @@ func (p *Device)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error
   return nil
 }
 
-func (p *Device) Write(ctx context.Context, oprot thrift.TProtocol) error {
-  if err := oprot.WriteStructBegin(ctx, "Device"); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
-  if p != nil {
-    if err := p.writeField1(ctx, oprot); err != nil { return err }
-  }
-  if err := oprot.WriteFieldStop(ctx); err != nil {
-    return thrift.PrependError("write field stop error: ", err) }
-  if err := oprot.WriteStructEnd(ctx); err != nil {
-    return thrift.PrependError("write struct stop error: ", err) }
+func (p *Device)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.Model = &v
+}
   return nil
 }
 
-func (p *Device) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
-  if err := oprot.WriteFieldBegin(ctx, "id", thrift.STRING, 1); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:id: ", p), err) }
-  if err := oprot.WriteString(ctx, string(p.ID)); err != nil {
-  return thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err) }
-  if err := oprot.WriteFieldEnd(ctx); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:id: ", p), err) }
-  return err
+func (p *Device)  ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 3: ", err)
+} else {
+  p.OsName = &v
 }
-
-func (p *Device) Equals(other *Device) bool {
-  if p == other {
-    return true
-  } else if p == nil || other == nil {
-    return false
-  }
-  if p.ID != other.ID { return false }
-  return true
+  return nil
 }
 
-func (p *Device) String() string {
+func (p *Device)  ReadField4(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 4: ", err)
+} else {
+  p.OsVersion = &v
+}
+  return nil
+}
+
+func (p *Device)  ReadField5(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 5: ", err)
+} else {
+  p.FormFactor = &v
+}
+  return nil
+}
+
+func (p *Device)  ReadField6(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 6: ", err)
+} else {
+  p.AttestationVerdict = &v
+}
+  return nil
+}
+
+func (p *Device) Write(ctx context.Context, oprot thrift.TProtocol) error {
+  if err := oprot.WriteStructBegin(ctx, "Device"); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
+  if p != nil {
+    if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+    if err := p.writeField3(ctx, oprot); err != nil { return err }
+    if err := p.writeField4(ctx, oprot); err != nil { return err }
+    if err := p.writeField5(ctx, oprot); err != nil { return err }
+    if err := p.writeField6(ctx, oprot); err != nil { return err }
+  }
+  if err := oprot.WriteFieldStop(ctx); err != nil {
+    return thrift.PrependError("write field stop error: ", err) }
+  if err := oprot.WriteStructEnd(ctx); err != nil {
+    return thrift.PrependError("write struct stop error: ", err) }
+  return nil
+}
+
+func (p *Device) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "id", thrift.STRING, 1); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:id: ", p), err) }
+  if err := oprot.WriteString(ctx, string(p.ID)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.id (1) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:id: ", p), err) }
+  return err
+}
+
+func (p *Device) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetModel() {
+    if err := oprot.WriteFieldBegin(ctx, "model", thrift.STRING, 2); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:model: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.Model)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.model (2) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 2:model: ", p), err) }
+  }
+  return err
+}
+
+func (p *Device) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetOsName() {
+    if err := oprot.WriteFieldBegin(ctx, "os_name", thrift.STRING, 3); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:os_name: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.OsName)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.os_name (3) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 3:os_name: ", p), err) }
+  }
+  return err
+}
+
+func (p *Device) writeField4(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetOsVersion() {
+    if err := oprot.WriteFieldBegin(ctx, "os_version", thrift.STRING, 4); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:os_version: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.OsVersion)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.os_version (4) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 4:os_version: ", p), err) }
+  }
+  return err
+}
+
+func (p *Device) writeField5(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetFormFactor() {
+    if err := oprot.WriteFieldBegin(ctx, "form_factor", thrift.STRING, 5); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:form_factor: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.FormFactor)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.form_factor (5) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 5:form_factor: ", p), err) }
+  }
+  return err
+}
+
+func (p *Device) writeField6(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetAttestationVerdict() {
+    if err := oprot.WriteFieldBegin(ctx, "attestation_verdict", thrift.STRING, 6); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:attestation_verdict: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.AttestationVerdict)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.attestation_verdict (6) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 6:attestation_verdict: ", p), err) }
+  }
+  return err
+}
+
+func (p *Device) Equals(other *Device) bool {
+  if p == other {
+    return true
+  } else if p == nil || other == nil {
+    return false
+  }
+  if p.ID != other.ID { return false }
+  if p.Model != other.Model {
+    if p.Model == nil || other.Model == nil {
+      return false
+    }
+    if (*p.Model) != (*other.Model) { return false }
+  }
+  if p.OsName != other.OsName {
+    if p.OsName == nil || other.OsName == nil {
+      return false
+    }
+    if (*p.OsName) != (*other.OsName) { return false }
+  }
+  if p.OsVersion != other.OsVersion {
+    if p.OsVersion == nil || other.OsVersion == nil {
+      return false
+    }
+    if (*p.OsVersion) != (*other.OsVersion) { return false }
+  }
+  if p.FormFactor != other.FormFactor {
+    if p.FormFactor == nil || other.FormFactor == nil {
+      return false
+    }
+    if (*p.FormFactor) != (*other.FormFactor) { return false }
+  }
+  if p.AttestationVerdict != other.AttestationVerdict {
+    if p.AttestationVerdict == nil || other.AttestationVerdict == nil {
+      return false
+    }
+    if (*p.AttestationVerdict) != (*other.AttestationVerdict) { return false }
+  }
+  return true
+}
+
+func (p *Device) String() string {
   if p == nil {
     return "<nil>"
   }
@@ -419,8 +945,18 @@ func (p *Device) String() string {
 // Attributes:
 //  - Name: The name of the origin service.
 // 
+//  - Version: The version of the origin service.
+// 
+//  - DeployID: The id of the deployment of the origin service.
+// 
+//  - EdgePop: The edge POP or region that terminated the client connection, e.g.
+// "sjc1".
+// 
 type OriginService struct {
   Name string `thrift:"name,1" db:"name" json:"name"`
+  Version *string `thrift:"version,2" db:"version" json:"version,omitempty"`
+  DeployID *string `thrift:"deploy_id,3" db:"deploy_id" json:"deploy_id,omitempty"`
+  EdgePop *string `thrift:"edge_pop,4" db:"edge_pop" json:"edge_pop,omitempty"`
 }
 
 func NewOriginService() *OriginService {
@@ -431,6 +967,39 @@ func NewOriginService() *OriginService {
 func (p *OriginService) GetName() string {
   return p.Name
 }
+var OriginService_Version_DEFAULT string
+func (p *OriginService) GetVersion() string {
+  if !p.IsSetVersion() {
+    return OriginService_Version_DEFAULT
+  }
+return *p.Version
+}
+var OriginService_DeployID_DEFAULT string
+func (p *OriginService) GetDeployID() string {
+  if !p.IsSetDeployID() {
+    return OriginService_DeployID_DEFAULT
+  }
+return *p.DeployID
+}
+var OriginService_EdgePop_DEFAULT string
+func (p *OriginService) GetEdgePop() string {
+  if !p.IsSetEdgePop() {
+    return OriginService_EdgePop_DEFAULT
+  }
+return *p.EdgePop
+}
+func (p *OriginService) IsSetVersion() bool {
+  return p.Version != nil
+}
+
+func (p *OriginService) IsSetDeployID() bool {
+  return p.DeployID != nil
+}
+
+func (p *OriginService) IsSetEdgePop() bool {
+  return p.EdgePop != nil
+}
+
 func (p *OriginService) Read(ctx context.Context, iprot thrift.TProtocol) error {
   if _, err := iprot.ReadStructBegin(ctx); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -454,6 +1023,36 @@ func (p *OriginService) Read(ctx context.Context, iprot thrift.TProtocol) error
           return err
         }
       }
+    case 2:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 4:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField4(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
     default:
       if err := iprot.Skip(ctx, fieldTypeId); err != nil {
         return err
@@ -478,11 +1077,41 @@ func (p *OriginService)  ReadField1(ctx context.Context, iprot thrift.TProtocol)
   return nil
 }
 
+func (p *OriginService)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.Version = &v
+}
+  return nil
+}
+
+func (p *OriginService)  ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 3: ", err)
+} else {
+  p.DeployID = &v
+}
+  return nil
+}
+
+func (p *OriginService)  ReadField4(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 4: ", err)
+} else {
+  p.EdgePop = &v
+}
+  return nil
+}
+
 func (p *OriginService) Write(ctx context.Context, oprot thrift.TProtocol) error {
   if err := oprot.WriteStructBegin(ctx, "OriginService"); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
   if p != nil {
     if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+    if err := p.writeField3(ctx, oprot); err != nil { return err }
+    if err := p.writeField4(ctx, oprot); err != nil { return err }
   }
   if err := oprot.WriteFieldStop(ctx); err != nil {
     return thrift.PrependError("write field stop error: ", err) }
@@ -501,6 +1130,42 @@ func (p *OriginService) writeField1(ctx context.Context, oprot thrift.TProtocol)
   return err
 }
 
+func (p *OriginService) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetVersion() {
+    if err := oprot.WriteFieldBegin(ctx, "version", thrift.STRING, 2); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:version: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.Version)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.version (2) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 2:version: ", p), err) }
+  }
+  return err
+}
+
+func (p *OriginService) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetDeployID() {
+    if err := oprot.WriteFieldBegin(ctx, "deploy_id", thrift.STRING, 3); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:deploy_id: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.DeployID)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.deploy_id (3) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 3:deploy_id: ", p), err) }
+  }
+  return err
+}
+
+func (p *OriginService) writeField4(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetEdgePop() {
+    if err := oprot.WriteFieldBegin(ctx, "edge_pop", thrift.STRING, 4); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:edge_pop: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.EdgePop)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.edge_pop (4) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 4:edge_pop: ", p), err) }
+  }
+  return err
+}
+
 func (p *OriginService) Equals(other *OriginService) bool {
   if p == other {
     return true
@@ -508,6 +1173,24 @@ func (p *OriginService) Equals(other *OriginService) bool {
     return false
   }
   if p.Name != other.Name { return false }
+  if p.Version != other.Version {
+    if p.Version == nil || other.Version == nil {
+      return false
+    }
+    if (*p.Version) != (*other.Version) { return false }
+  }
+  if p.DeployID != other.DeployID {
+    if p.DeployID == nil || other.DeployID == nil {
+      return false
+    }
+    if (*p.DeployID) != (*other.DeployID) { return false }
+  }
+  if p.EdgePop != other.EdgePop {
+    if p.EdgePop == nil || other.EdgePop == nil {
+      return false
+    }
+    if (*p.EdgePop) != (*other.EdgePop) { return false }
+  }
   return true
 }
 
@@ -528,8 +1211,20 @@ func (p *OriginService) String() string {
 // 
 // Attributes:
 //  - CountryCode: The country code of the requesting client based on geographic location.
+//  - SubdivisionCode: The ISO 3166-2 subdivision code (e.g. state or province) of the
+// requesting client based on geographic location, e.g. "US-CA".
+//  - City: The name of the city of the requesting client based on geographic
+// location, e.g. "San Francisco".
+//  - MetroCode: The Nielsen DMA (Designated Market Area) code of the requesting client
+// based on geographic location, e.g. 807 for San Francisco-Oakland-San Jose.
+//  - Provenance: How the country code was determined, one of "ip" (IP address lookup),
+// "account" (user account setting), or "carrier" (mobile carrier header).
 type Geolocation struct {
   CountryCode CountryCode `thrift:"country_code,1" db:"country_code" json:"country_code"`
+  SubdivisionCode *string `thrift:"subdivision_code,2" db:"subdivision_code" json:"subdivision_code,omitempty"`
+  City *string `thrift:"city,3" db:"city" json:"city,omitempty"`
+  MetroCode *int32 `thrift:"metro_code,4" db:"metro_code" json:"metro_code,omitempty"`
+  Provenance *string `thrift:"provenance,5" db:"provenance" json:"provenance,omitempty"`
 }
 
 func NewGeolocation() *Geolocation {
@@ -540,6 +1235,50 @@ func NewGeolocation() *Geolocation {
 func (p *Geolocation) GetCountryCode() CountryCode {
   return p.CountryCode
 }
+var Geolocation_SubdivisionCode_DEFAULT string
+func (p *Geolocation) GetSubdivisionCode() string {
+  if !p.IsSetSubdivisionCode() {
+    return Geolocation_SubdivisionCode_DEFAULT
+  }
+return *p.SubdivisionCode
+}
+var Geolocation_City_DEFAULT string
+func (p *Geolocation) GetCity() string {
+  if !p.IsSetCity() {
+    return Geolocation_City_DEFAULT
+  }
+return *p.City
+}
+var Geolocation_MetroCode_DEFAULT int32
+func (p *Geolocation) GetMetroCode() int32 {
+  if !p.IsSetMetroCode() {
+    return Geolocation_MetroCode_DEFAULT
+  }
+return *p.MetroCode
+}
+var Geolocation_Provenance_DEFAULT string
+func (p *Geolocation) GetProvenance() string {
+  if !p.IsSetProvenance() {
+    return Geolocation_Provenance_DEFAULT
+  }
+return *p.Provenance
+}
+func (p *Geolocation) IsSetSubdivisionCode() bool {
+  return p.SubdivisionCode != nil
+}
+
+func (p *Geolocation) IsSetCity() bool {
+  return p.City != nil
+}
+
+func (p *Geolocation) IsSetMetroCode() bool {
+  return p.MetroCode != nil
+}
+
+func (p *Geolocation) IsSetProvenance() bool {
+  return p.Provenance != nil
+}
+
 func (p *Geolocation) Read(ctx context.Context, iprot thrift.TProtocol) error {
   if _, err := iprot.ReadStructBegin(ctx); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -563,6 +1302,46 @@ func (p *Geolocation) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
+    case 2:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 4:
+      if fieldTypeId == thrift.I32 {
+        if err := p.ReadField4(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 5:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField5(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
     default:
       if err := iprot.Skip(ctx, fieldTypeId); err != nil {
         return err
@@ -588,47 +1367,1152 @@ func (p *Geolocation)  ReadField1(ctx context.Context, iprot thrift.TProtocol) e
   return nil
 }
 
-func (p *Geolocation) Write(ctx context.Context, oprot thrift.TProtocol) error {
-  if err := oprot.WriteStructBegin(ctx, "Geolocation"); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
-  if p != nil {
-    if err := p.writeField1(ctx, oprot); err != nil { return err }
-  }
-  if err := oprot.WriteFieldStop(ctx); err != nil {
-    return thrift.PrependError("write field stop error: ", err) }
-  if err := oprot.WriteStructEnd(ctx); err != nil {
-    return thrift.PrependError("write struct stop error: ", err) }
+func (p *Geolocation)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.SubdivisionCode = &v
+}
   return nil
 }
 
-func (p *Geolocation) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
-  if err := oprot.WriteFieldBegin(ctx, "country_code", thrift.STRING, 1); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:country_code: ", p), err) }
-  if err := oprot.WriteString(ctx, string(p.CountryCode)); err != nil {
-  return thrift.PrependError(fmt.Sprintf("%T.country_code (1) field write error: ", p), err) }
+func (p *Geolocation)  ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 3: ", err)
+} else {
+  p.City = &v
+}
+  return nil
+}
+
+func (p *Geolocation)  ReadField4(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadI32(ctx); err != nil {
+  return thrift.PrependError("error reading field 4: ", err)
+} else {
+  p.MetroCode = &v
+}
+  return nil
+}
+
+func (p *Geolocation)  ReadField5(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 5: ", err)
+} else {
+  p.Provenance = &v
+}
+  return nil
+}
+
+func (p *Geolocation) Write(ctx context.Context, oprot thrift.TProtocol) error {
+  if err := oprot.WriteStructBegin(ctx, "Geolocation"); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
+  if p != nil {
+    if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+    if err := p.writeField3(ctx, oprot); err != nil { return err }
+    if err := p.writeField4(ctx, oprot); err != nil { return err }
+    if err := p.writeField5(ctx, oprot); err != nil { return err }
+  }
+  if err := oprot.WriteFieldStop(ctx); err != nil {
+    return thrift.PrependError("write field stop error: ", err) }
+  if err := oprot.WriteStructEnd(ctx); err != nil {
+    return thrift.PrependError("write struct stop error: ", err) }
+  return nil
+}
+
+func (p *Geolocation) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "country_code", thrift.STRING, 1); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:country_code: ", p), err) }
+  if err := oprot.WriteString(ctx, string(p.CountryCode)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.country_code (1) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:country_code: ", p), err) }
+  return err
+}
+
+func (p *Geolocation) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetSubdivisionCode() {
+    if err := oprot.WriteFieldBegin(ctx, "subdivision_code", thrift.STRING, 2); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:subdivision_code: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.SubdivisionCode)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.subdivision_code (2) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 2:subdivision_code: ", p), err) }
+  }
+  return err
+}
+
+func (p *Geolocation) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetCity() {
+    if err := oprot.WriteFieldBegin(ctx, "city", thrift.STRING, 3); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:city: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.City)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.city (3) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 3:city: ", p), err) }
+  }
+  return err
+}
+
+func (p *Geolocation) writeField4(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetMetroCode() {
+    if err := oprot.WriteFieldBegin(ctx, "metro_code", thrift.I32, 4); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:metro_code: ", p), err) }
+    if err := oprot.WriteI32(ctx, int32(*p.MetroCode)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.metro_code (4) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 4:metro_code: ", p), err) }
+  }
+  return err
+}
+
+func (p *Geolocation) writeField5(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetProvenance() {
+    if err := oprot.WriteFieldBegin(ctx, "provenance", thrift.STRING, 5); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:provenance: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.Provenance)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.provenance (5) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 5:provenance: ", p), err) }
+  }
+  return err
+}
+
+func (p *Geolocation) Equals(other *Geolocation) bool {
+  if p == other {
+    return true
+  } else if p == nil || other == nil {
+    return false
+  }
+  if p.CountryCode != other.CountryCode { return false }
+  if p.SubdivisionCode != other.SubdivisionCode {
+    if p.SubdivisionCode == nil || other.SubdivisionCode == nil {
+      return false
+    }
+    if (*p.SubdivisionCode) != (*other.SubdivisionCode) { return false }
+  }
+  if p.City != other.City {
+    if p.City == nil || other.City == nil {
+      return false
+    }
+    if (*p.City) != (*other.City) { return false }
+  }
+  if p.MetroCode != other.MetroCode {
+    if p.MetroCode == nil || other.MetroCode == nil {
+      return false
+    }
+    if (*p.MetroCode) != (*other.MetroCode) { return false }
+  }
+  if p.Provenance != other.Provenance {
+    if p.Provenance == nil || other.Provenance == nil {
+      return false
+    }
+    if (*p.Provenance) != (*other.Provenance) { return false }
+  }
+  return true
+}
+
+func (p *Geolocation) String() string {
+  if p == nil {
+    return "<nil>"
+  }
+  return fmt.Sprintf("Geolocation(%+v)", *p)
+}
+
+// Unique identifier of this Edge Request
+// 
+// This model is a component of the "Edge-Request" header.  You should not need to
+// interact with this model directly, but rather through the EdgeRequestContext
+// interface provided by baseplate.
+// 
+// 
+// Attributes:
+//  - ReadableID: The id of this Edge Request, in the most human-readable format.
+//  - TraceID: The W3C Trace Context trace id of the distributed trace this Edge Request
+// belongs to, as 32 lowercase hex characters.
+type RequestId struct {
+  ReadableID string `thrift:"readable_id,1" db:"readable_id" json:"readable_id"`
+  TraceID *string `thrift:"trace_id,2" db:"trace_id" json:"trace_id,omitempty"`
+}
+
+func NewRequestId() *RequestId {
+  return &RequestId{}
+}
+
+
+func (p *RequestId) GetReadableID() string {
+  return p.ReadableID
+}
+var RequestId_TraceID_DEFAULT string
+func (p *RequestId) GetTraceID() string {
+  if !p.IsSetTraceID() {
+    return RequestId_TraceID_DEFAULT
+  }
+return *p.TraceID
+}
+func (p *RequestId) IsSetTraceID() bool {
+  return p.TraceID != nil
+}
+
+func (p *RequestId) Read(ctx context.Context, iprot thrift.TProtocol) error {
+  if _, err := iprot.ReadStructBegin(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+  }
+
+
+  for {
+    _, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+    if err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+    }
+    if fieldTypeId == thrift.STOP { break; }
+    switch fieldId {
+    case 1:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField1(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 2:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    default:
+      if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+        return err
+      }
+    }
+    if err := iprot.ReadFieldEnd(ctx); err != nil {
+      return err
+    }
+  }
+  if err := iprot.ReadStructEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+  }
+  return nil
+}
+
+func (p *RequestId)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 1: ", err)
+} else {
+  p.ReadableID = v
+}
+  return nil
+}
+
+func (p *RequestId)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.TraceID = &v
+}
+  return nil
+}
+
+func (p *RequestId) Write(ctx context.Context, oprot thrift.TProtocol) error {
+  if err := oprot.WriteStructBegin(ctx, "RequestId"); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
+  if p != nil {
+    if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+  }
+  if err := oprot.WriteFieldStop(ctx); err != nil {
+    return thrift.PrependError("write field stop error: ", err) }
+  if err := oprot.WriteStructEnd(ctx); err != nil {
+    return thrift.PrependError("write struct stop error: ", err) }
+  return nil
+}
+
+func (p *RequestId) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "readable_id", thrift.STRING, 1); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:readable_id: ", p), err) }
+  if err := oprot.WriteString(ctx, string(p.ReadableID)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.readable_id (1) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:readable_id: ", p), err) }
+  return err
+}
+
+func (p *RequestId) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetTraceID() {
+    if err := oprot.WriteFieldBegin(ctx, "trace_id", thrift.STRING, 2); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:trace_id: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.TraceID)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.trace_id (2) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 2:trace_id: ", p), err) }
+  }
+  return err
+}
+
+func (p *RequestId) Equals(other *RequestId) bool {
+  if p == other {
+    return true
+  } else if p == nil || other == nil {
+    return false
+  }
+  if p.ReadableID != other.ReadableID { return false }
+  if p.TraceID != other.TraceID {
+    if p.TraceID == nil || other.TraceID == nil {
+      return false
+    }
+    if (*p.TraceID) != (*other.TraceID) { return false }
+  }
+  return true
+}
+
+func (p *RequestId) String() string {
+  if p == nil {
+    return "<nil>"
+  }
+  return fmt.Sprintf("RequestId(%+v)", *p)
+}
+
+// Locale data from a request to our services that we want to
+// propagate between services.
+// 
+// This model is a component of the "Edge-Request" header.  You should not need to
+// interact with this model directly, but rather through the EdgeRequestContext
+// interface provided by baseplate.
+// 
+// 
+// Attributes:
+//  - LocaleCode: IETF language code representing the client locale preferences.
+// Preferably in BCP-47 format ({lang} or {lang}-{region}),
+// but underscore separated locales also valid ({lang}_{region})
+//  - Timezone: IANA time zone database name of the time zone of the client,
+// e.g. "America/New_York".
+//  - AcceptedLocaleCodes: The full list of locales accepted by the client, in order of preference,
+// e.g. from the Accept-Language header.
+// 
+type Locale struct {
+  LocaleCode LocaleCode `thrift:"locale_code,1" db:"locale_code" json:"locale_code"`
+  Timezone *string `thrift:"timezone,2" db:"timezone" json:"timezone,omitempty"`
+  AcceptedLocaleCodes []string `thrift:"accepted_locale_codes,3" db:"accepted_locale_codes" json:"accepted_locale_codes,omitempty"`
+}
+
+func NewLocale() *Locale {
+  return &Locale{}
+}
+
+
+func (p *Locale) GetLocaleCode() LocaleCode {
+  return p.LocaleCode
+}
+var Locale_Timezone_DEFAULT string
+func (p *Locale) GetTimezone() string {
+  if !p.IsSetTimezone() {
+    return Locale_Timezone_DEFAULT
+  }
+return *p.Timezone
+}
+var Locale_AcceptedLocaleCodes_DEFAULT []string
+
+func (p *Locale) GetAcceptedLocaleCodes() []string {
+  return p.AcceptedLocaleCodes
+}
+func (p *Locale) IsSetTimezone() bool {
+  return p.Timezone != nil
+}
+
+func (p *Locale) IsSetAcceptedLocaleCodes() bool {
+  return p.AcceptedLocaleCodes != nil
+}
+
+func (p *Locale) Read(ctx context.Context, iprot thrift.TProtocol) error {
+  if _, err := iprot.ReadStructBegin(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+  }
+
+
+  for {
+    _, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+    if err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+    }
+    if fieldTypeId == thrift.STOP { break; }
+    switch fieldId {
+    case 1:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField1(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 2:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.LIST {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    default:
+      if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+        return err
+      }
+    }
+    if err := iprot.ReadFieldEnd(ctx); err != nil {
+      return err
+    }
+  }
+  if err := iprot.ReadStructEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+  }
+  return nil
+}
+
+func (p *Locale)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 1: ", err)
+} else {
+  temp := LocaleCode(v)
+  p.LocaleCode = temp
+}
+  return nil
+}
+
+func (p *Locale)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.Timezone = &v
+}
+  return nil
+}
+
+func (p *Locale)  ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+  _, size, err := iprot.ReadListBegin(ctx)
+  if err != nil {
+    return thrift.PrependError("error reading list begin: ", err)
+  }
+  tSlice := make([]string, 0, size)
+  p.AcceptedLocaleCodes =  tSlice
+  for i := 0; i < size; i ++ {
+var _elem0 string
+    if v, err := iprot.ReadString(ctx); err != nil {
+    return thrift.PrependError("error reading field 0: ", err)
+} else {
+    _elem0 = v
+}
+    p.AcceptedLocaleCodes = append(p.AcceptedLocaleCodes, _elem0)
+  }
+  if err := iprot.ReadListEnd(ctx); err != nil {
+    return thrift.PrependError("error reading list end: ", err)
+  }
+  return nil
+}
+
+func (p *Locale) Write(ctx context.Context, oprot thrift.TProtocol) error {
+  if err := oprot.WriteStructBegin(ctx, "Locale"); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
+  if p != nil {
+    if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+    if err := p.writeField3(ctx, oprot); err != nil { return err }
+  }
+  if err := oprot.WriteFieldStop(ctx); err != nil {
+    return thrift.PrependError("write field stop error: ", err) }
+  if err := oprot.WriteStructEnd(ctx); err != nil {
+    return thrift.PrependError("write struct stop error: ", err) }
+  return nil
+}
+
+func (p *Locale) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "locale_code", thrift.STRING, 1); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:locale_code: ", p), err) }
+  if err := oprot.WriteString(ctx, string(p.LocaleCode)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.locale_code (1) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:locale_code: ", p), err) }
+  return err
+}
+
+func (p *Locale) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetTimezone() {
+    if err := oprot.WriteFieldBegin(ctx, "timezone", thrift.STRING, 2); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:timezone: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.Timezone)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.timezone (2) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 2:timezone: ", p), err) }
+  }
+  return err
+}
+
+func (p *Locale) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetAcceptedLocaleCodes() {
+    if err := oprot.WriteFieldBegin(ctx, "accepted_locale_codes", thrift.LIST, 3); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:accepted_locale_codes: ", p), err) }
+    if err := oprot.WriteListBegin(ctx, thrift.STRING, len(p.AcceptedLocaleCodes)); err != nil {
+      return thrift.PrependError("error writing list begin: ", err)
+    }
+    for _, v := range p.AcceptedLocaleCodes {
+      if err := oprot.WriteString(ctx, string(v)); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err) }
+    }
+    if err := oprot.WriteListEnd(ctx); err != nil {
+      return thrift.PrependError("error writing list end: ", err)
+    }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 3:accepted_locale_codes: ", p), err) }
+  }
+  return err
+}
+
+func (p *Locale) Equals(other *Locale) bool {
+  if p == other {
+    return true
+  } else if p == nil || other == nil {
+    return false
+  }
+  if p.LocaleCode != other.LocaleCode { return false }
+  if p.Timezone != other.Timezone {
+    if p.Timezone == nil || other.Timezone == nil {
+      return false
+    }
+    if (*p.Timezone) != (*other.Timezone) { return false }
+  }
+  if len(p.AcceptedLocaleCodes) != len(other.AcceptedLocaleCodes) { return false }
+  for i, _tgt := range p.AcceptedLocaleCodes {
+    _src1 := other.AcceptedLocaleCodes[i]
+    if _tgt != _src1 { return false }
+  }
+  return true
+}
+
+func (p *Locale) String() string {
+  if p == nil {
+    return "<nil>"
+  }
+  return fmt.Sprintf("Locale(%+v)", *p)
+}
+
+// Metadata about the client making the request to our services that we want
+// to propagate between services.
+// 
+// This model is a component of the "Edge-Request" header.  You should not need to
+// interact with this model directly, but rather through the EdgeRequestContext
+// interface provided by baseplate.
+// 
+// 
+// Attributes:
+//  - IP: The IP address of the client, as seen by the edge.
+// 
+// Either IPv4 dotted decimal ("192.0.2.1") or IPv6 ("2001:db8::1") form.
+//  - UserAgent: The User-Agent header sent by the client to the edge.
+//  - AppVersion: The semantic version of the client application, e.g. "2023.21.0".
+// 
+//  - BuildNumber: The build number of the client application.
+// 
+//  - Platform: The platform of the client, one of "ios", "android", "web", "mweb"
+// (mobile web), or "api" (third party API clients).
+// 
+//  - InternalNetwork: Whether the request originates from the corporate network (VPN) or an
+// employee device.
+//  - LimitAdTracking: Whether the client asked to limit ad tracking, e.g. via iOS App Tracking
+// Transparency, the Android advertising ID opt-out, or the DNT and Sec-GPC
+// headers.
+//  - AnonymizingProxy: Whether the edge determined the client IP belongs to a VPN or an
+// anonymizing proxy.
+//  - Asn: The autonomous system number of the client IP, 0 if unknown.
+type Client struct {
+  IP string `thrift:"ip,1" db:"ip" json:"ip"`
+  UserAgent string `thrift:"user_agent,2" db:"user_agent" json:"user_agent"`
+  AppVersion string `thrift:"app_version,3" db:"app_version" json:"app_version"`
+  BuildNumber int64 `thrift:"build_number,4" db:"build_number" json:"build_number"`
+  Platform string `thrift:"platform,5" db:"platform" json:"platform"`
+  InternalNetwork bool `thrift:"internal_network,6" db:"internal_network" json:"internal_network"`
+  LimitAdTracking bool `thrift:"limit_ad_tracking,7" db:"limit_ad_tracking" json:"limit_ad_tracking"`
+  AnonymizingProxy bool `thrift:"anonymizing_proxy,8" db:"anonymizing_proxy" json:"anonymizing_proxy"`
+  Asn int64 `thrift:"asn,9" db:"asn" json:"asn"`
+}
+
+func NewClient() *Client {
+  return &Client{}
+}
+
+
+func (p *Client) GetIP() string {
+  return p.IP
+}
+
+func (p *Client) GetUserAgent() string {
+  return p.UserAgent
+}
+
+func (p *Client) GetAppVersion() string {
+  return p.AppVersion
+}
+
+func (p *Client) GetBuildNumber() int64 {
+  return p.BuildNumber
+}
+
+func (p *Client) GetPlatform() string {
+  return p.Platform
+}
+
+func (p *Client) GetInternalNetwork() bool {
+  return p.InternalNetwork
+}
+
+func (p *Client) GetLimitAdTracking() bool {
+  return p.LimitAdTracking
+}
+
+func (p *Client) GetAnonymizingProxy() bool {
+  return p.AnonymizingProxy
+}
+
+func (p *Client) GetAsn() int64 {
+  return p.Asn
+}
+func (p *Client) Read(ctx context.Context, iprot thrift.TProtocol) error {
+  if _, err := iprot.ReadStructBegin(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+  }
+
+
+  for {
+    _, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+    if err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+    }
+    if fieldTypeId == thrift.STOP { break; }
+    switch fieldId {
+    case 1:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField1(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 2:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 4:
+      if fieldTypeId == thrift.I64 {
+        if err := p.ReadField4(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 5:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField5(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 6:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField6(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 7:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField7(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 8:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField8(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 9:
+      if fieldTypeId == thrift.I64 {
+        if err := p.ReadField9(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    default:
+      if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+        return err
+      }
+    }
+    if err := iprot.ReadFieldEnd(ctx); err != nil {
+      return err
+    }
+  }
+  if err := iprot.ReadStructEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+  }
+  return nil
+}
+
+func (p *Client)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 1: ", err)
+} else {
+  p.IP = v
+}
+  return nil
+}
+
+func (p *Client)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.UserAgent = v
+}
+  return nil
+}
+
+func (p *Client)  ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 3: ", err)
+} else {
+  p.AppVersion = v
+}
+  return nil
+}
+
+func (p *Client)  ReadField4(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadI64(ctx); err != nil {
+  return thrift.PrependError("error reading field 4: ", err)
+} else {
+  p.BuildNumber = v
+}
+  return nil
+}
+
+func (p *Client)  ReadField5(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 5: ", err)
+} else {
+  p.Platform = v
+}
+  return nil
+}
+
+func (p *Client)  ReadField6(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 6: ", err)
+} else {
+  p.InternalNetwork = v
+}
+  return nil
+}
+
+func (p *Client)  ReadField7(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 7: ", err)
+} else {
+  p.LimitAdTracking = v
+}
+  return nil
+}
+
+func (p *Client)  ReadField8(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 8: ", err)
+} else {
+  p.AnonymizingProxy = v
+}
+  return nil
+}
+
+func (p *Client)  ReadField9(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadI64(ctx); err != nil {
+  return thrift.PrependError("error reading field 9: ", err)
+} else {
+  p.Asn = v
+}
+  return nil
+}
+
+func (p *Client) Write(ctx context.Context, oprot thrift.TProtocol) error {
+  if err := oprot.WriteStructBegin(ctx, "Client"); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
+  if p != nil {
+    if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+    if err := p.writeField3(ctx, oprot); err != nil { return err }
+    if err := p.writeField4(ctx, oprot); err != nil { return err }
+    if err := p.writeField5(ctx, oprot); err != nil { return err }
+    if err := p.writeField6(ctx, oprot); err != nil { return err }
+    if err := p.writeField7(ctx, oprot); err != nil { return err }
+    if err := p.writeField8(ctx, oprot); err != nil { return err }
+    if err := p.writeField9(ctx, oprot); err != nil { return err }
+  }
+  if err := oprot.WriteFieldStop(ctx); err != nil {
+    return thrift.PrependError("write field stop error: ", err) }
+  if err := oprot.WriteStructEnd(ctx); err != nil {
+    return thrift.PrependError("write struct stop error: ", err) }
+  return nil
+}
+
+func (p *Client) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "ip", thrift.STRING, 1); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:ip: ", p), err) }
+  if err := oprot.WriteString(ctx, string(p.IP)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.ip (1) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:ip: ", p), err) }
+  return err
+}
+
+func (p *Client) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "user_agent", thrift.STRING, 2); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:user_agent: ", p), err) }
+  if err := oprot.WriteString(ctx, string(p.UserAgent)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.user_agent (2) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 2:user_agent: ", p), err) }
+  return err
+}
+
+func (p *Client) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "app_version", thrift.STRING, 3); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:app_version: ", p), err) }
+  if err := oprot.WriteString(ctx, string(p.AppVersion)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.app_version (3) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 3:app_version: ", p), err) }
+  return err
+}
+
+func (p *Client) writeField4(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "build_number", thrift.I64, 4); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:build_number: ", p), err) }
+  if err := oprot.WriteI64(ctx, int64(p.BuildNumber)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.build_number (4) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 4:build_number: ", p), err) }
+  return err
+}
+
+func (p *Client) writeField5(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "platform", thrift.STRING, 5); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:platform: ", p), err) }
+  if err := oprot.WriteString(ctx, string(p.Platform)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.platform (5) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 5:platform: ", p), err) }
+  return err
+}
+
+func (p *Client) writeField6(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "internal_network", thrift.BOOL, 6); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:internal_network: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.InternalNetwork)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.internal_network (6) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 6:internal_network: ", p), err) }
+  return err
+}
+
+func (p *Client) writeField7(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "limit_ad_tracking", thrift.BOOL, 7); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:limit_ad_tracking: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.LimitAdTracking)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.limit_ad_tracking (7) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 7:limit_ad_tracking: ", p), err) }
+  return err
+}
+
+func (p *Client) writeField8(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "anonymizing_proxy", thrift.BOOL, 8); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:anonymizing_proxy: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.AnonymizingProxy)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.anonymizing_proxy (8) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 8:anonymizing_proxy: ", p), err) }
+  return err
+}
+
+func (p *Client) writeField9(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "asn", thrift.I64, 9); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 9:asn: ", p), err) }
+  if err := oprot.WriteI64(ctx, int64(p.Asn)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.asn (9) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 9:asn: ", p), err) }
+  return err
+}
+
+func (p *Client) Equals(other *Client) bool {
+  if p == other {
+    return true
+  } else if p == nil || other == nil {
+    return false
+  }
+  if p.IP != other.IP { return false }
+  if p.UserAgent != other.UserAgent { return false }
+  if p.AppVersion != other.AppVersion { return false }
+  if p.BuildNumber != other.BuildNumber { return false }
+  if p.Platform != other.Platform { return false }
+  if p.InternalNetwork != other.InternalNetwork { return false }
+  if p.LimitAdTracking != other.LimitAdTracking { return false }
+  if p.AnonymizingProxy != other.AnonymizingProxy { return false }
+  if p.Asn != other.Asn { return false }
+  return true
+}
+
+func (p *Client) String() string {
+  if p == nil {
+    return "<nil>"
+  }
+  return fmt.Sprintf("Client(%+v)", *p)
+}
+
+// The privacy consents given by the user making the request.
+// 
+// This model is a component of the "Edge-Request" header.  You should not need to
+// interact with this model directly, but rather through the EdgeRequestContext
+// interface provided by baseplate.
+// 
+// 
+// Attributes:
+//  - AdsPersonalization: Whether the user consented to personalized ads.
+//  - Analytics: Whether the user consented to analytics tracking.
+//  - ThirdPartySharing: Whether the user consented to sharing their data with third parties.
+type Consent struct {
+  AdsPersonalization bool `thrift:"ads_personalization,1" db:"ads_personalization" json:"ads_personalization"`
+  Analytics bool `thrift:"analytics,2" db:"analytics" json:"analytics"`
+  ThirdPartySharing bool `thrift:"third_party_sharing,3" db:"third_party_sharing" json:"third_party_sharing"`
+}
+
+func NewConsent() *Consent {
+  return &Consent{}
+}
+
+
+func (p *Consent) GetAdsPersonalization() bool {
+  return p.AdsPersonalization
+}
+
+func (p *Consent) GetAnalytics() bool {
+  return p.Analytics
+}
+
+func (p *Consent) GetThirdPartySharing() bool {
+  return p.ThirdPartySharing
+}
+func (p *Consent) Read(ctx context.Context, iprot thrift.TProtocol) error {
+  if _, err := iprot.ReadStructBegin(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+  }
+
+
+  for {
+    _, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+    if err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+    }
+    if fieldTypeId == thrift.STOP { break; }
+    switch fieldId {
+    case 1:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField1(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 2:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    default:
+      if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+        return err
+      }
+    }
+    if err := iprot.ReadFieldEnd(ctx); err != nil {
+      return err
+    }
+  }
+  if err := iprot.ReadStructEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+  }
+  return nil
+}
+
+func (p *Consent)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 1: ", err)
+} else {
+  p.AdsPersonalization = v
+}
+  return nil
+}
+
+func (p *Consent)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.Analytics = v
+}
+  return nil
+}
+
+func (p *Consent)  ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 3: ", err)
+} else {
+  p.ThirdPartySharing = v
+}
+  return nil
+}
+
+func (p *Consent) Write(ctx context.Context, oprot thrift.TProtocol) error {
+  if err := oprot.WriteStructBegin(ctx, "Consent"); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
+  if p != nil {
+    if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+    if err := p.writeField3(ctx, oprot); err != nil { return err }
+  }
+  if err := oprot.WriteFieldStop(ctx); err != nil {
+    return thrift.PrependError("write field stop error: ", err) }
+  if err := oprot.WriteStructEnd(ctx); err != nil {
+    return thrift.PrependError("write struct stop error: ", err) }
+  return nil
+}
+
+func (p *Consent) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "ads_personalization", thrift.BOOL, 1); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:ads_personalization: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.AdsPersonalization)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.ads_personalization (1) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:ads_personalization: ", p), err) }
+  return err
+}
+
+func (p *Consent) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "analytics", thrift.BOOL, 2); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:analytics: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.Analytics)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.analytics (2) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 2:analytics: ", p), err) }
+  return err
+}
+
+func (p *Consent) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "third_party_sharing", thrift.BOOL, 3); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:third_party_sharing: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.ThirdPartySharing)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.third_party_sharing (3) field write error: ", p), err) }
   if err := oprot.WriteFieldEnd(ctx); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:country_code: ", p), err) }
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 3:third_party_sharing: ", p), err) }
   return err
 }
 
-func (p *Geolocation) Equals(other *Geolocation) bool {
+func (p *Consent) Equals(other *Consent) bool {
   if p == other {
     return true
   } else if p == nil || other == nil {
     return false
   }
-  if p.CountryCode != other.CountryCode { return false }
+  if p.AdsPersonalization != other.AdsPersonalization { return false }
+  if p.Analytics != other.Analytics { return false }
+  if p.ThirdPartySharing != other.ThirdPartySharing { return false }
   return true
 }
 
-func (p *Geolocation) String() string {
+func (p *Consent) String() string {
   if p == nil {
     return "<nil>"
   }
-  return fmt.Sprintf("Geolocation(%+v)", *p)
+  return fmt.Sprintf("Consent(%+v)", *p)
 }
 
-// Unique identifier of this Edge Request
+// The components of the account of the user making the request that we want
+// to propagate between services.
 // 
 // This model is a component of the "Edge-Request" header.  You should not need to
 // interact with this model directly, but rather through the EdgeRequestContext
@@ -636,20 +2520,34 @@ func (p *Geolocation) String() string {
 // 
 // 
 // Attributes:
-//  - ReadableID: The id of this Edge Request, in the most human-readable format.
-type RequestId struct {
-  ReadableID string `thrift:"readable_id,1" db:"readable_id" json:"readable_id"`
+//  - TeenRestricted: Whether the account is subject to teen-safety or parental-control
+// restrictions.
+//  - EmailVerified: Whether the email address of the account is verified.
+//  - Premium: Whether the account has an active premium subscription at the time of
+// the request.
+type Account struct {
+  TeenRestricted bool `thrift:"teen_restricted,1" db:"teen_restricted" json:"teen_restricted"`
+  EmailVerified bool `thrift:"email_verified,2" db:"email_verified" json:"email_verified"`
+  Premium bool `thrift:"premium,3" db:"premium" json:"premium"`
 }
 
-func NewRequestId() *RequestId {
-  return &RequestId{}
+func NewAccount() *Account {
+  return &Account{}
 }
 
 
-func (p *RequestId) GetReadableID() string {
-  return p.ReadableID
+func (p *Account) GetTeenRestricted() bool {
+  return p.TeenRestricted
 }
-func (p *RequestId) Read(ctx context.Context, iprot thrift.TProtocol) error {
+
+func (p *Account) GetEmailVerified() bool {
+  return p.EmailVerified
+}
+
+func (p *Account) GetPremium() bool {
+  return p.Premium
+}
+func (p *Account) Read(ctx context.Context, iprot thrift.TProtocol) error {
   if _, err := iprot.ReadStructBegin(ctx); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
   }
@@ -663,7 +2561,7 @@ func (p *RequestId) Read(ctx context.Context, iprot thrift.TProtocol) error {
     if fieldTypeId == thrift.STOP { break; }
     switch fieldId {
     case 1:
-      if fieldTypeId == thrift.STRING {
+      if fieldTypeId == thrift.BOOL {
         if err := p.ReadField1(ctx, iprot); err != nil {
           return err
         }
@@ -672,6 +2570,26 @@ func (p *RequestId) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
+    case 2:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
     default:
       if err := iprot.Skip(ctx, fieldTypeId); err != nil {
         return err
@@ -687,20 +2605,40 @@ func (p *RequestId) Read(ctx context.Context, iprot thrift.TProtocol) error {
   return nil
 }
 
-func (p *RequestId)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
-  if v, err := iprot.ReadString(ctx); err != nil {
+func (p *Account)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
   return thrift.PrependError("error reading field 1: ", err)
 } else {
-  p.ReadableID = v
+  p.TeenRestricted = v
 }
   return nil
 }
 
-func (p *RequestId) Write(ctx context.Context, oprot thrift.TProtocol) error {
-  if err := oprot.WriteStructBegin(ctx, "RequestId"); err != nil {
+func (p *Account)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.EmailVerified = v
+}
+  return nil
+}
+
+func (p *Account)  ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 3: ", err)
+} else {
+  p.Premium = v
+}
+  return nil
+}
+
+func (p *Account) Write(ctx context.Context, oprot thrift.TProtocol) error {
+  if err := oprot.WriteStructBegin(ctx, "Account"); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
   if p != nil {
     if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+    if err := p.writeField3(ctx, oprot); err != nil { return err }
   }
   if err := oprot.WriteFieldStop(ctx); err != nil {
     return thrift.PrependError("write field stop error: ", err) }
@@ -709,35 +2647,56 @@ func (p *RequestId) Write(ctx context.Context, oprot thrift.TProtocol) error {
   return nil
 }
 
-func (p *RequestId) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
-  if err := oprot.WriteFieldBegin(ctx, "readable_id", thrift.STRING, 1); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:readable_id: ", p), err) }
-  if err := oprot.WriteString(ctx, string(p.ReadableID)); err != nil {
-  return thrift.PrependError(fmt.Sprintf("%T.readable_id (1) field write error: ", p), err) }
+func (p *Account) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "teen_restricted", thrift.BOOL, 1); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:teen_restricted: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.TeenRestricted)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.teen_restricted (1) field write error: ", p), err) }
   if err := oprot.WriteFieldEnd(ctx); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:readable_id: ", p), err) }
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:teen_restricted: ", p), err) }
   return err
 }
 
-func (p *RequestId) Equals(other *RequestId) bool {
+func (p *Account) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "email_verified", thrift.BOOL, 2); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:email_verified: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.EmailVerified)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.email_verified (2) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 2:email_verified: ", p), err) }
+  return err
+}
+
+func (p *Account) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "premium", thrift.BOOL, 3); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:premium: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.Premium)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.premium (3) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 3:premium: ", p), err) }
+  return err
+}
+
+func (p *Account) Equals(other *Account) bool {
   if p == other {
     return true
   } else if p == nil || other == nil {
     return false
   }
-  if p.ReadableID != other.ReadableID { return false }
+  if p.TeenRestricted != other.TeenRestricted { return false }
+  if p.EmailVerified != other.EmailVerified { return false }
+  if p.Premium != other.Premium { return false }
   return true
 }
 
-func (p *RequestId) String() string {
+func (p *Account) String() string {
   if p == nil {
     return "<nil>"
   }
-  return fmt.Sprintf("RequestId(%+v)", *p)
+  return fmt.Sprintf("Account(%+v)", *p)
 }
 
-// Locale data from a request to our services that we want to
-// propagate between services.
+// The classification of the traffic a request belongs to, set by the edge.
 // 
 // This model is a component of the "Edge-Request" header.  You should not need to
 // interact with this model directly, but rather through the EdgeRequestContext
@@ -745,22 +2704,34 @@ func (p *RequestId) String() string {
 // 
 // 
 // Attributes:
-//  - LocaleCode: IETF language code representing the client locale preferences.
-// Preferably in BCP-47 format ({lang} or {lang}-{region}),
-// but underscore separated locales also valid ({lang}_{region})
-type Locale struct {
-  LocaleCode LocaleCode `thrift:"locale_code,1" db:"locale_code" json:"locale_code"`
+//  - Shadow: Whether the request is replayed or shadowed (dark launches, traffic
+// mirroring), in which case side effects should be suppressed.
+//  - Synthetic: Whether the request is generated by a synthetic probe or a load test.
+//  - Canary: Whether the request belongs to the canary (early rollout) cohort chosen
+// by the edge.
+type Traffic struct {
+  Shadow bool `thrift:"shadow,1" db:"shadow" json:"shadow"`
+  Synthetic bool `thrift:"synthetic,2" db:"synthetic" json:"synthetic"`
+  Canary bool `thrift:"canary,3" db:"canary" json:"canary"`
 }
 
-func NewLocale() *Locale {
-  return &Locale{}
+func NewTraffic() *Traffic {
+  return &Traffic{}
 }
 
 
-func (p *Locale) GetLocaleCode() LocaleCode {
-  return p.LocaleCode
+func (p *Traffic) GetShadow() bool {
+  return p.Shadow
 }
-func (p *Locale) Read(ctx context.Context, iprot thrift.TProtocol) error {
+
+func (p *Traffic) GetSynthetic() bool {
+  return p.Synthetic
+}
+
+func (p *Traffic) GetCanary() bool {
+  return p.Canary
+}
+func (p *Traffic) Read(ctx context.Context, iprot thrift.TProtocol) error {
   if _, err := iprot.ReadStructBegin(ctx); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
   }
@@ -774,7 +2745,7 @@ func (p *Locale) Read(ctx context.Context, iprot thrift.TProtocol) error {
     if fieldTypeId == thrift.STOP { break; }
     switch fieldId {
     case 1:
-      if fieldTypeId == thrift.STRING {
+      if fieldTypeId == thrift.BOOL {
         if err := p.ReadField1(ctx, iprot); err != nil {
           return err
         }
@@ -783,6 +2754,26 @@ func (p *Locale) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
+    case 2:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.BOOL {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
     default:
       if err := iprot.Skip(ctx, fieldTypeId); err != nil {
         return err
@@ -798,21 +2789,40 @@ func (p *Locale) Read(ctx context.Context, iprot thrift.TProtocol) error {
   return nil
 }
 
-func (p *Locale)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
-  if v, err := iprot.ReadString(ctx); err != nil {
+func (p *Traffic)  ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
   return thrift.PrependError("error reading field 1: ", err)
 } else {
-  temp := LocaleCode(v)
-  p.LocaleCode = temp
+  p.Shadow = v
 }
   return nil
 }
 
-func (p *Locale) Write(ctx context.Context, oprot thrift.TProtocol) error {
-  if err := oprot.WriteStructBegin(ctx, "Locale"); err != nil {
+func (p *Traffic)  ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 2: ", err)
+} else {
+  p.Synthetic = v
+}
+  return nil
+}
+
+func (p *Traffic)  ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadBool(ctx); err != nil {
+  return thrift.PrependError("error reading field 3: ", err)
+} else {
+  p.Canary = v
+}
+  return nil
+}
+
+func (p *Traffic) Write(ctx context.Context, oprot thrift.TProtocol) error {
+  if err := oprot.WriteStructBegin(ctx, "Traffic"); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
   if p != nil {
     if err := p.writeField1(ctx, oprot); err != nil { return err }
+    if err := p.writeField2(ctx, oprot); err != nil { return err }
+    if err := p.writeField3(ctx, oprot); err != nil { return err }
   }
   if err := oprot.WriteFieldStop(ctx); err != nil {
     return thrift.PrependError("write field stop error: ", err) }
@@ -821,31 +2831,53 @@ func (p *Locale) Write(ctx context.Context, oprot thrift.TProtocol) error {
   return nil
 }
 
-func (p *Locale) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
-  if err := oprot.WriteFieldBegin(ctx, "locale_code", thrift.STRING, 1); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:locale_code: ", p), err) }
-  if err := oprot.WriteString(ctx, string(p.LocaleCode)); err != nil {
-  return thrift.PrependError(fmt.Sprintf("%T.locale_code (1) field write error: ", p), err) }
+func (p *Traffic) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "shadow", thrift.BOOL, 1); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:shadow: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.Shadow)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.shadow (1) field write error: ", p), err) }
   if err := oprot.WriteFieldEnd(ctx); err != nil {
-    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:locale_code: ", p), err) }
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 1:shadow: ", p), err) }
   return err
 }
 
-func (p *Locale) Equals(other *Locale) bool {
+func (p *Traffic) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "synthetic", thrift.BOOL, 2); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:synthetic: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.Synthetic)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.synthetic (2) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 2:synthetic: ", p), err) }
+  return err
+}
+
+func (p *Traffic) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if err := oprot.WriteFieldBegin(ctx, "canary", thrift.BOOL, 3); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:canary: ", p), err) }
+  if err := oprot.WriteBool(ctx, bool(p.Canary)); err != nil {
+  return thrift.PrependError(fmt.Sprintf("%T.canary (3) field write error: ", p), err) }
+  if err := oprot.WriteFieldEnd(ctx); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T write field end error 3:canary: ", p), err) }
+  return err
+}
+
+func (p *Traffic) Equals(other *Traffic) bool {
   if p == other {
     return true
   } else if p == nil || other == nil {
     return false
   }
-  if p.LocaleCode != other.LocaleCode { return false }
+  if p.Shadow != other.Shadow { return false }
+  if p.Synthetic != other.Synthetic { return false }
+  if p.Canary != other.Canary { return false }
   return true
 }
 
-func (p *Locale) String() string {
+func (p *Traffic) String() string {
   if p == nil {
     return "<nil>"
   }
-  return fmt.Sprintf("Locale(%+v)", *p)
+  return fmt.Sprintf("Traffic(%+v)", *p)
 }
 
 // Container model for the Edge-Request context header.
@@ -865,6 +2897,29 @@ func (p *Locale) String() string {
 //  - Geolocation
 //  - RequestID
 //  - Locale
+//  - Client
+//  - FeatureFlagOverrides: Per-request feature flag overrides, keyed by feature flag name.
+// 
+// Only set by the edge for internal and debug clients.
+//  - Consent
+//  - ComplianceRegion: The data protection regime that applies to the request, computed once at
+// the edge, one of "gdpr", "ccpa", "lgpd", or "none".
+//  - Account
+//  - Baggage: Generic low-cardinality metadata set by the edge, for values that don't
+// warrant their own field.
+//  - WorkloadIdentity: The SPIFFE ID of the calling workload, for requests initiated by a
+// service instead of a user, e.g. "spiffe://reddit.com/ns/jobs/sa/backfill".
+//  - DeadlineMs: The absolute deadline by which the response must be sent back to the
+// client, in number of milliseconds since epoch.
+//  - Traffic
+//  - Hops: The names of the services that forwarded this Edge-Request header, in
+// order, with the most recent hops last.
+//  - TenantID: The id of the tenant (brand) the request is served for, in deployments
+// that serve multiple tenants from shared infrastructure.
+//  - ReferringSurface: The product surface that initiated the request, one of "home_feed",
+// "push_notification", "email", "embed", or "other".
+//  - ServiceAuthenticationToken: The auth token of the service initiating the request on behalf of the
+// user in authentication_token, if any.
 type Request struct {
   Loid *Loid `thrift:"loid,1" db:"loid" json:"loid"`
   Session *Session `thrift:"session,2" db:"session" json:"session"`
@@ -874,6 +2929,19 @@ type Request struct {
   Geolocation *Geolocation `thrift:"geolocation,6" db:"geolocation" json:"geolocation"`
   RequestID *RequestId `thrift:"request_id,7" db:"request_id" json:"request_id,omitempty"`
   Locale *Locale `thrift:"locale,8" db:"locale" json:"locale,omitempty"`
+  Client *Client `thrift:"client,9" db:"client" json:"client,omitempty"`
+  FeatureFlagOverrides map[string]string `thrift:"feature_flag_overrides,10" db:"feature_flag_overrides" json:"feature_flag_overrides,omitempty"`
+  Consent *Consent `thrift:"consent,11" db:"consent" json:"consent,omitempty"`
+  ComplianceRegion *string `thrift:"compliance_region,12" db:"compliance_region" json:"compliance_region,omitempty"`
+  Account *Account `thrift:"account,13" db:"account" json:"account,omitempty"`
+  Baggage map[string]string `thrift:"baggage,14" db:"baggage" json:"baggage,omitempty"`
+  WorkloadIdentity *string `thrift:"workload_identity,15" db:"workload_identity" json:"workload_identity,omitempty"`
+  DeadlineMs *int64 `thrift:"deadline_ms,16" db:"deadline_ms" json:"deadline_ms,omitempty"`
+  Traffic *Traffic `thrift:"traffic,17" db:"traffic" json:"traffic,omitempty"`
+  Hops []string `thrift:"hops,18" db:"hops" json:"hops,omitempty"`
+  TenantID *string `thrift:"tenant_id,19" db:"tenant_id" json:"tenant_id,omitempty"`
+  ReferringSurface *string `thrift:"referring_surface,20" db:"referring_surface" json:"referring_surface,omitempty"`
+  ServiceAuthenticationToken *AuthenticationToken `thrift:"service_authentication_token,21" db:"service_authentication_token" json:"service_authentication_token,omitempty"`
 }
 
 func NewRequest() *Request {
@@ -933,6 +3001,91 @@ func (p *Request) GetLocale() *Locale {
   }
 return p.Locale
 }
+var Request_Client_DEFAULT *Client
+func (p *Request) GetClient() *Client {
+  if !p.IsSetClient() {
+    return Request_Client_DEFAULT
+  }
+return p.Client
+}
+var Request_FeatureFlagOverrides_DEFAULT map[string]string
+
+func (p *Request) GetFeatureFlagOverrides() map[string]string {
+  return p.FeatureFlagOverrides
+}
+var Request_Consent_DEFAULT *Consent
+func (p *Request) GetConsent() *Consent {
+  if !p.IsSetConsent() {
+    return Request_Consent_DEFAULT
+  }
+return p.Consent
+}
+var Request_ComplianceRegion_DEFAULT string
+func (p *Request) GetComplianceRegion() string {
+  if !p.IsSetComplianceRegion() {
+    return Request_ComplianceRegion_DEFAULT
+  }
+return *p.ComplianceRegion
+}
+var Request_Account_DEFAULT *Account
+func (p *Request) GetAccount() *Account {
+  if !p.IsSetAccount() {
+    return Request_Account_DEFAULT
+  }
+return p.Account
+}
+var Request_Baggage_DEFAULT map[string]string
+
+func (p *Request) GetBaggage() map[string]string {
+  return p.Baggage
+}
+var Request_WorkloadIdentity_DEFAULT string
+func (p *Request) GetWorkloadIdentity() string {
+  if !p.IsSetWorkloadIdentity() {
+    return Request_WorkloadIdentity_DEFAULT
+  }
+return *p.WorkloadIdentity
+}
+var Request_DeadlineMs_DEFAULT int64
+func (p *Request) GetDeadlineMs() int64 {
+  if !p.IsSetDeadlineMs() {
+    return Request_DeadlineMs_DEFAULT
+  }
+return *p.DeadlineMs
+}
+var Request_Traffic_DEFAULT *Traffic
+func (p *Request) GetTraffic() *Traffic {
+  if !p.IsSetTraffic() {
+    return Request_Traffic_DEFAULT
+  }
+return p.Traffic
+}
+var Request_Hops_DEFAULT []string
+
+func (p *Request) GetHops() []string {
+  return p.Hops
+}
+var Request_TenantID_DEFAULT string
+func (p *Request) GetTenantID() string {
+  if !p.IsSetTenantID() {
+    return Request_TenantID_DEFAULT
+  }
+return *p.TenantID
+}
+var Request_ReferringSurface_DEFAULT string
+func (p *Request) GetReferringSurface() string {
+  if !p.IsSetReferringSurface() {
+    return Request_ReferringSurface_DEFAULT
+  }
+return *p.ReferringSurface
+}
+var Request_ServiceAuthenticationToken_DEFAULT AuthenticationToken
+func (p *Request) GetServiceAuthenticationToken() AuthenticationToken {
+  if !p.IsSetServiceAuthenticationToken() {
+    return Request_ServiceAuthenticationToken_DEFAULT
+  }
+return *p.ServiceAuthenticationToken
+}
 func (p *Request) IsSetLoid() bool {
   return p.Loid != nil
 }
@@ -961,6 +3114,58 @@ func (p *Request) IsSetLocale() bool {
   return p.Locale != nil
 }
 
+func (p *Request) IsSetClient() bool {
+  return p.Client != nil
+}
+
+func (p *Request) IsSetFeatureFlagOverrides() bool {
+  return p.FeatureFlagOverrides != nil
+}
+
+func (p *Request) IsSetConsent() bool {
+  return p.Consent != nil
+}
+
+func (p *Request) IsSetComplianceRegion() bool {
+  return p.ComplianceRegion != nil
+}
+
+func (p *Request) IsSetAccount() bool {
+  return p.Account != nil
+}
+
+func (p *Request) IsSetBaggage() bool {
+  return p.Baggage != nil
+}
+
+func (p *Request) IsSetWorkloadIdentity() bool {
+  return p.WorkloadIdentity != nil
+}
+
+func (p *Request) IsSetDeadlineMs() bool {
+  return p.DeadlineMs != nil
+}
+
+func (p *Request) IsSetTraffic() bool {
+  return p.Traffic != nil
+}
+
+func (p *Request) IsSetHops() bool {
+  return p.Hops != nil
+}
+
+func (p *Request) IsSetTenantID() bool {
+  return p.TenantID != nil
+}
+
+func (p *Request) IsSetReferringSurface() bool {
+  return p.ReferringSurface != nil
+}
+
+func (p *Request) IsSetServiceAuthenticationToken() bool {
+  return p.ServiceAuthenticationToken != nil
+}
+
 func (p *Request) Read(ctx context.Context, iprot thrift.TProtocol) error {
   if _, err := iprot.ReadStructBegin(ctx); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -984,9 +3189,59 @@ func (p *Request) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
-    case 2:
+    case 2:
+      if fieldTypeId == thrift.STRUCT {
+        if err := p.ReadField2(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 3:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField3(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 4:
+      if fieldTypeId == thrift.STRUCT {
+        if err := p.ReadField4(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 5:
+      if fieldTypeId == thrift.STRUCT {
+        if err := p.ReadField5(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 6:
+      if fieldTypeId == thrift.STRUCT {
+        if err := p.ReadField6(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 7:
       if fieldTypeId == thrift.STRUCT {
-        if err := p.ReadField2(ctx, iprot); err != nil {
+        if err := p.ReadField7(ctx, iprot); err != nil {
           return err
         }
       } else {
@@ -994,9 +3249,9 @@ func (p *Request) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
-    case 3:
-      if fieldTypeId == thrift.STRING {
-        if err := p.ReadField3(ctx, iprot); err != nil {
+    case 8:
+      if fieldTypeId == thrift.STRUCT {
+        if err := p.ReadField8(ctx, iprot); err != nil {
           return err
         }
       } else {
@@ -1004,9 +3259,9 @@ func (p *Request) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
-    case 4:
+    case 9:
       if fieldTypeId == thrift.STRUCT {
-        if err := p.ReadField4(ctx, iprot); err != nil {
+        if err := p.ReadField9(ctx, iprot); err != nil {
           return err
         }
       } else {
@@ -1014,9 +3269,9 @@ func (p *Request) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
-    case 5:
-      if fieldTypeId == thrift.STRUCT {
-        if err := p.ReadField5(ctx, iprot); err != nil {
+    case 10:
+      if fieldTypeId == thrift.MAP {
+        if err := p.ReadField10(ctx, iprot); err != nil {
           return err
         }
       } else {
@@ -1024,9 +3279,9 @@ func (p *Request) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
-    case 6:
+    case 11:
       if fieldTypeId == thrift.STRUCT {
-        if err := p.ReadField6(ctx, iprot); err != nil {
+        if err := p.ReadField11(ctx, iprot); err != nil {
           return err
         }
       } else {
@@ -1034,9 +3289,19 @@ func (p *Request) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
-    case 7:
+    case 12:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField12(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 13:
       if fieldTypeId == thrift.STRUCT {
-        if err := p.ReadField7(ctx, iprot); err != nil {
+        if err := p.ReadField13(ctx, iprot); err != nil {
           return err
         }
       } else {
@@ -1044,9 +3309,79 @@ func (p *Request) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
-    case 8:
+    case 14:
+      if fieldTypeId == thrift.MAP {
+        if err := p.ReadField14(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 15:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField15(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 16:
+      if fieldTypeId == thrift.I64 {
+        if err := p.ReadField16(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 17:
       if fieldTypeId == thrift.STRUCT {
-        if err := p.ReadField8(ctx, iprot); err != nil {
+        if err := p.ReadField17(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 18:
+      if fieldTypeId == thrift.LIST {
+        if err := p.ReadField18(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 19:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField19(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 20:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField20(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 21:
+      if fieldTypeId == thrift.STRING {
+        if err := p.ReadField21(ctx, iprot); err != nil {
           return err
         }
       } else {
@@ -1135,6 +3470,171 @@ func (p *Request)  ReadField8(ctx context.Context, iprot thrift.TProtocol) error
   return nil
 }
 
+func (p *Request)  ReadField9(ctx context.Context, iprot thrift.TProtocol) error {
+  p.Client = &Client{}
+  if err := p.Client.Read(ctx, iprot); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Client), err)
+  }
+  return nil
+}
+
+func (p *Request)  ReadField10(ctx context.Context, iprot thrift.TProtocol) error {
+  _, _, size, err := iprot.ReadMapBegin(ctx)
+  if err != nil {
+    return thrift.PrependError("error reading map begin: ", err)
+  }
+  tMap := make(map[string]string, size)
+  p.FeatureFlagOverrides =  tMap
+  for i := 0; i < size; i ++ {
+var _key2 string
+    if v, err := iprot.ReadString(ctx); err != nil {
+    return thrift.PrependError("error reading field 0: ", err)
+} else {
+    _key2 = v
+}
+var _val3 string
+    if v, err := iprot.ReadString(ctx); err != nil {
+    return thrift.PrependError("error reading field 0: ", err)
+} else {
+    _val3 = v
+}
+    p.FeatureFlagOverrides[_key2] = _val3
+  }
+  if err := iprot.ReadMapEnd(ctx); err != nil {
+    return thrift.PrependError("error reading map end: ", err)
+  }
+  return nil
+}
+
+func (p *Request)  ReadField11(ctx context.Context, iprot thrift.TProtocol) error {
+  p.Consent = &Consent{}
+  if err := p.Consent.Read(ctx, iprot); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Consent), err)
+  }
+  return nil
+}
+
+func (p *Request)  ReadField12(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 12: ", err)
+} else {
+  p.ComplianceRegion = &v
+}
+  return nil
+}
+
+func (p *Request)  ReadField13(ctx context.Context, iprot thrift.TProtocol) error {
+  p.Account = &Account{}
+  if err := p.Account.Read(ctx, iprot); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Account), err)
+  }
+  return nil
+}
+
+func (p *Request)  ReadField14(ctx context.Context, iprot thrift.TProtocol) error {
+  _, _, size, err := iprot.ReadMapBegin(ctx)
+  if err != nil {
+    return thrift.PrependError("error reading map begin: ", err)
+  }
+  tMap := make(map[string]string, size)
+  p.Baggage =  tMap
+  for i := 0; i < size; i ++ {
+var _key4 string
+    if v, err := iprot.ReadString(ctx); err != nil {
+    return thrift.PrependError("error reading field 0: ", err)
+} else {
+    _key4 = v
+}
+var _val5 string
+    if v, err := iprot.ReadString(ctx); err != nil {
+    return thrift.PrependError("error reading field 0: ", err)
+} else {
+    _val5 = v
+}
+    p.Baggage[_key4] = _val5
+  }
+  if err := iprot.ReadMapEnd(ctx); err != nil {
+    return thrift.PrependError("error reading map end: ", err)
+  }
+  return nil
+}
+
+func (p *Request)  ReadField15(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 15: ", err)
+} else {
+  p.WorkloadIdentity = &v
+}
+  return nil
+}
+
+func (p *Request)  ReadField16(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadI64(ctx); err != nil {
+  return thrift.PrependError("error reading field 16: ", err)
+} else {
+  p.DeadlineMs = &v
+}
+  return nil
+}
+
+func (p *Request)  ReadField17(ctx context.Context, iprot thrift.TProtocol) error {
+  p.Traffic = &Traffic{}
+  if err := p.Traffic.Read(ctx, iprot); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Traffic), err)
+  }
+  return nil
+}
+
+func (p *Request)  ReadField18(ctx context.Context, iprot thrift.TProtocol) error {
+  _, size, err := iprot.ReadListBegin(ctx)
+  if err != nil {
+    return thrift.PrependError("error reading list begin: ", err)
+  }
+  tSlice := make([]string, 0, size)
+  p.Hops =  tSlice
+  for i := 0; i < size; i ++ {
+var _elem6 string
+    if v, err := iprot.ReadString(ctx); err != nil {
+    return thrift.PrependError("error reading field 0: ", err)
+} else {
+    _elem6 = v
+}
+    p.Hops = append(p.Hops, _elem6)
+  }
+  if err := iprot.ReadListEnd(ctx); err != nil {
+    return thrift.PrependError("error reading list end: ", err)
+  }
+  return nil
+}
+
+func (p *Request)  ReadField19(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 19: ", err)
+} else {
+  p.TenantID = &v
+}
+  return nil
+}
+
+func (p *Request)  ReadField20(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 20: ", err)
+} else {
+  p.ReferringSurface = &v
+}
+  return nil
+}
+
+func (p *Request)  ReadField21(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadString(ctx); err != nil {
+  return thrift.PrependError("error reading field 21: ", err)
+} else {
+  temp := AuthenticationToken(v)
+  p.ServiceAuthenticationToken = &temp
+}
+  return nil
+}
+
 func (p *Request) Write(ctx context.Context, oprot thrift.TProtocol) error {
   if err := oprot.WriteStructBegin(ctx, "Request"); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
@@ -1147,6 +3647,19 @@ func (p *Request) Write(ctx context.Context, oprot thrift.TProtocol) error {
     if err := p.writeField6(ctx, oprot); err != nil { return err }
     if err := p.writeField7(ctx, oprot); err != nil { return err }
     if err := p.writeField8(ctx, oprot); err != nil { return err }
+    if err := p.writeField9(ctx, oprot); err != nil { return err }
+    if err := p.writeField10(ctx, oprot); err != nil { return err }
+    if err := p.writeField11(ctx, oprot); err != nil { return err }
+    if err := p.writeField12(ctx, oprot); err != nil { return err }
+    if err := p.writeField13(ctx, oprot); err != nil { return err }
+    if err := p.writeField14(ctx, oprot); err != nil { return err }
+    if err := p.writeField15(ctx, oprot); err != nil { return err }
+    if err := p.writeField16(ctx, oprot); err != nil { return err }
+    if err := p.writeField17(ctx, oprot); err != nil { return err }
+    if err := p.writeField18(ctx, oprot); err != nil { return err }
+    if err := p.writeField19(ctx, oprot); err != nil { return err }
+    if err := p.writeField20(ctx, oprot); err != nil { return err }
+    if err := p.writeField21(ctx, oprot); err != nil { return err }
   }
   if err := oprot.WriteFieldStop(ctx); err != nil {
     return thrift.PrependError("write field stop error: ", err) }
@@ -1246,6 +3759,194 @@ func (p *Request) writeField8(ctx context.Context, oprot thrift.TProtocol) (err
   return err
 }
 
+func (p *Request) writeField9(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetClient() {
+    if err := oprot.WriteFieldBegin(ctx, "client", thrift.STRUCT, 9); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 9:client: ", p), err) }
+    if err := p.Client.Write(ctx, oprot); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Client), err)
+    }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 9:client: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField10(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetFeatureFlagOverrides() {
+    if err := oprot.WriteFieldBegin(ctx, "feature_flag_overrides", thrift.MAP, 10); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 10:feature_flag_overrides: ", p), err) }
+    if err := oprot.WriteMapBegin(ctx, thrift.STRING, thrift.STRING, len(p.FeatureFlagOverrides)); err != nil {
+      return thrift.PrependError("error writing map begin: ", err)
+    }
+    for k, v := range p.FeatureFlagOverrides {
+      if err := oprot.WriteString(ctx, string(k)); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err) }
+      if err := oprot.WriteString(ctx, string(v)); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err) }
+    }
+    if err := oprot.WriteMapEnd(ctx); err != nil {
+      return thrift.PrependError("error writing map end: ", err)
+    }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 10:feature_flag_overrides: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField11(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetConsent() {
+    if err := oprot.WriteFieldBegin(ctx, "consent", thrift.STRUCT, 11); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 11:consent: ", p), err) }
+    if err := p.Consent.Write(ctx, oprot); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Consent), err)
+    }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 11:consent: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField12(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetComplianceRegion() {
+    if err := oprot.WriteFieldBegin(ctx, "compliance_region", thrift.STRING, 12); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 12:compliance_region: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.ComplianceRegion)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.compliance_region (12) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 12:compliance_region: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField13(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetAccount() {
+    if err := oprot.WriteFieldBegin(ctx, "account", thrift.STRUCT, 13); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 13:account: ", p), err) }
+    if err := p.Account.Write(ctx, oprot); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Account), err)
+    }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 13:account: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField14(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetBaggage() {
+    if err := oprot.WriteFieldBegin(ctx, "baggage", thrift.MAP, 14); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 14:baggage: ", p), err) }
+    if err := oprot.WriteMapBegin(ctx, thrift.STRING, thrift.STRING, len(p.Baggage)); err != nil {
+      return thrift.PrependError("error writing map begin: ", err)
+    }
+    for k, v := range p.Baggage {
+      if err := oprot.WriteString(ctx, string(k)); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err) }
+      if err := oprot.WriteString(ctx, string(v)); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err) }
+    }
+    if err := oprot.WriteMapEnd(ctx); err != nil {
+      return thrift.PrependError("error writing map end: ", err)
+    }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 14:baggage: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField15(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetWorkloadIdentity() {
+    if err := oprot.WriteFieldBegin(ctx, "workload_identity", thrift.STRING, 15); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 15:workload_identity: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.WorkloadIdentity)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.workload_identity (15) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 15:workload_identity: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField16(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetDeadlineMs() {
+    if err := oprot.WriteFieldBegin(ctx, "deadline_ms", thrift.I64, 16); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 16:deadline_ms: ", p), err) }
+    if err := oprot.WriteI64(ctx, int64(*p.DeadlineMs)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.deadline_ms (16) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 16:deadline_ms: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField17(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetTraffic() {
+    if err := oprot.WriteFieldBegin(ctx, "traffic", thrift.STRUCT, 17); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 17:traffic: ", p), err) }
+    if err := p.Traffic.Write(ctx, oprot); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Traffic), err)
+    }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 17:traffic: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField18(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetHops() {
+    if err := oprot.WriteFieldBegin(ctx, "hops", thrift.LIST, 18); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 18:hops: ", p), err) }
+    if err := oprot.WriteListBegin(ctx, thrift.STRING, len(p.Hops)); err != nil {
+      return thrift.PrependError("error writing list begin: ", err)
+    }
+    for _, v := range p.Hops {
+      if err := oprot.WriteString(ctx, string(v)); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T. (0) field write error: ", p), err) }
+    }
+    if err := oprot.WriteListEnd(ctx); err != nil {
+      return thrift.PrependError("error writing list end: ", err)
+    }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 18:hops: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField19(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetTenantID() {
+    if err := oprot.WriteFieldBegin(ctx, "tenant_id", thrift.STRING, 19); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 19:tenant_id: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.TenantID)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.tenant_id (19) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 19:tenant_id: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField20(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetReferringSurface() {
+    if err := oprot.WriteFieldBegin(ctx, "referring_surface", thrift.STRING, 20); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 20:referring_surface: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.ReferringSurface)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.referring_surface (20) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 20:referring_surface: ", p), err) }
+  }
+  return err
+}
+
+func (p *Request) writeField21(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetServiceAuthenticationToken() {
+    if err := oprot.WriteFieldBegin(ctx, "service_authentication_token", thrift.STRING, 21); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 21:service_authentication_token: ", p), err) }
+    if err := oprot.WriteString(ctx, string(*p.ServiceAuthenticationToken)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.service_authentication_token (21) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 21:service_authentication_token: ", p), err) }
+  }
+  return err
+}
+
 func (p *Request) Equals(other *Request) bool {
   if p == other {
     return true
@@ -1260,6 +3961,61 @@ func (p *Request) Equals(other *Request) bool {
   if !p.Geolocation.Equals(other.Geolocation) { return false }
   if !p.RequestID.Equals(other.RequestID) { return false }
   if !p.Locale.Equals(other.Locale) { return false }
+  if !p.Client.Equals(other.Client) { return false }
+  if len(p.FeatureFlagOverrides) != len(other.FeatureFlagOverrides) { return false }
+  for k, _tgt := range p.FeatureFlagOverrides {
+    _src7 := other.FeatureFlagOverrides[k]
+    if _tgt != _src7 { return false }
+  }
+  if !p.Consent.Equals(other.Consent) { return false }
+  if p.ComplianceRegion != other.ComplianceRegion {
+    if p.ComplianceRegion == nil || other.ComplianceRegion == nil {
+      return false
+    }
+    if (*p.ComplianceRegion) != (*other.ComplianceRegion) { return false }
+  }
+  if !p.Account.Equals(other.Account) { return false }
+  if len(p.Baggage) != len(other.Baggage) { return false }
+  for k, _tgt := range p.Baggage {
+    _src8 := other.Baggage[k]
+    if _tgt != _src8 { return false }
+  }
+  if p.WorkloadIdentity != other.WorkloadIdentity {
+    if p.WorkloadIdentity == nil || other.WorkloadIdentity == nil {
+      return false
+    }
+    if (*p.WorkloadIdentity) != (*other.WorkloadIdentity) { return false }
+  }
+  if p.DeadlineMs != other.DeadlineMs {
+    if p.DeadlineMs == nil || other.DeadlineMs == nil {
+      return false
+    }
+    if (*p.DeadlineMs) != (*other.DeadlineMs) { return false }
+  }
+  if !p.Traffic.Equals(other.Traffic) { return false }
+  if len(p.Hops) != len(other.Hops) { return false }
+  for i, _tgt := range p.Hops {
+    _src9 := other.Hops[i]
+    if _tgt != _src9 { return false }
+  }
+  if p.TenantID != other.TenantID {
+    if p.TenantID == nil || other.TenantID == nil {
+      return false
+    }
+    if (*p.TenantID) != (*other.TenantID) { return false }
+  }
+  if p.ReferringSurface != other.ReferringSurface {
+    if p.ReferringSurface == nil || other.ReferringSurface == nil {
+      return false
+    }
+    if (*p.ReferringSurface) != (*other.ReferringSurface) { return false }
+  }
+  if p.ServiceAuthenticationToken != other.ServiceAuthenticationToken {
+    if p.ServiceAuthenticationToken == nil || other.ServiceAuthenticationToken == nil {
+      return false
+    }
+    if (*p.ServiceAuthenticationToken) != (*other.ServiceAuthenticationToken) { return false }
+  }
   return true
 }
 
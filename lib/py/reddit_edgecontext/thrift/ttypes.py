@@ -132,16 +132,46 @@ class Session(object):
     Attributes:
      - id: The ID of the Session tracker cookie.
 
+     - created_ms: The time when the session was created, in number of milliseconds since
+    epoch.
+
+     - type: The type of the session, one of "cookie", "mobile" or "api".
+
+     - elevated_until_ms: If the session recently completed step-up authentication, the time
+    until when it's considered elevated, in number of milliseconds since epoch.
+
+     - mfa: Whether the session was authenticated with multi-factor authentication.
+
+     - auth_method: How the user authenticated, one of "password", "google", "apple",
+    "magic_link" or "refresh".
+
 
     """
 
-    __slots__ = ("id",)
+    __slots__ = (
+        "id",
+        "created_ms",
+        "type",
+        "elevated_until_ms",
+        "mfa",
+        "auth_method",
+    )
 
     def __init__(
         self,
         id=None,
+        created_ms=None,
+        type=None,
+        elevated_until_ms=None,
+        mfa=None,
+        auth_method=None,
     ):
         self.id = id
+        self.created_ms = created_ms
+        self.type = type
+        self.elevated_until_ms = elevated_until_ms
+        self.mfa = mfa
+        self.auth_method = auth_method
 
     def read(self, iprot):
         if (
@@ -165,6 +195,39 @@ class Session(object):
                     )
                 else:
                     iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.I64:
+                    self.created_ms = iprot.readI64()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 3:
+                if ftype == TType.STRING:
+                    self.type = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 4:
+                if ftype == TType.I64:
+                    self.elevated_until_ms = iprot.readI64()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 5:
+                if ftype == TType.BOOL:
+                    self.mfa = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 6:
+                if ftype == TType.STRING:
+                    self.auth_method = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
             else:
                 iprot.skip(ftype)
             iprot.readFieldEnd()
@@ -179,6 +242,28 @@ class Session(object):
             oprot.writeFieldBegin("id", TType.STRING, 1)
             oprot.writeString(self.id.encode("utf-8") if sys.version_info[0] == 2 else self.id)
             oprot.writeFieldEnd()
+        if self.created_ms is not None:
+            oprot.writeFieldBegin("created_ms", TType.I64, 2)
+            oprot.writeI64(self.created_ms)
+            oprot.writeFieldEnd()
+        if self.type is not None:
+            oprot.writeFieldBegin("type", TType.STRING, 3)
+            oprot.writeString(self.type.encode("utf-8") if sys.version_info[0] == 2 else self.type)
+            oprot.writeFieldEnd()
+        if self.elevated_until_ms is not None:
+            oprot.writeFieldBegin("elevated_until_ms", TType.I64, 4)
+            oprot.writeI64(self.elevated_until_ms)
+            oprot.writeFieldEnd()
+        if self.mfa is not None:
+            oprot.writeFieldBegin("mfa", TType.BOOL, 5)
+            oprot.writeBool(self.mfa)
+            oprot.writeFieldEnd()
+        if self.auth_method is not None:
+            oprot.writeFieldBegin("auth_method", TType.STRING, 6)
+            oprot.writeString(
+                self.auth_method.encode("utf-8") if sys.version_info[0] == 2 else self.auth_method
+            )
+            oprot.writeFieldEnd()
         oprot.writeFieldStop()
         oprot.writeStructEnd()
 
@@ -216,16 +301,46 @@ class Device(object):
     Attributes:
      - id: The ID of the device.
 
+     - model: The model of the device, e.g. "iPhone14,2" or "Pixel 7".
+
+     - os_name: The name of the operating system of the device, e.g. "iOS" or "Android".
+
+     - os_version: The version of the operating system of the device, e.g. "16.4.1".
+
+     - form_factor: The coarse form factor of the device, one of "phone", "tablet",
+    "desktop", "tv" or "bot".
+
+     - attestation_verdict: The verdict of the mobile device attestation (Play Integrity or App
+    Attest) evaluated at the edge, one of "strong", "device", "basic",
+    "failed" or "unavailable".
+
 
     """
 
-    __slots__ = ("id",)
+    __slots__ = (
+        "id",
+        "model",
+        "os_name",
+        "os_version",
+        "form_factor",
+        "attestation_verdict",
+    )
 
     def __init__(
         self,
         id=None,
+        model=None,
+        os_name=None,
+        os_version=None,
+        form_factor=None,
+        attestation_verdict=None,
     ):
         self.id = id
+        self.model = model
+        self.os_name = os_name
+        self.os_version = os_version
+        self.form_factor = form_factor
+        self.attestation_verdict = attestation_verdict
 
     def read(self, iprot):
         if (
@@ -249,6 +364,51 @@ class Device(object):
                     )
                 else:
                     iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.STRING:
+                    self.model = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 3:
+                if ftype == TType.STRING:
+                    self.os_name = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 4:
+                if ftype == TType.STRING:
+                    self.os_version = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 5:
+                if ftype == TType.STRING:
+                    self.form_factor = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 6:
+                if ftype == TType.STRING:
+                    self.attestation_verdict = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
             else:
                 iprot.skip(ftype)
             iprot.readFieldEnd()
@@ -263,6 +423,38 @@ class Device(object):
             oprot.writeFieldBegin("id", TType.STRING, 1)
             oprot.writeString(self.id.encode("utf-8") if sys.version_info[0] == 2 else self.id)
             oprot.writeFieldEnd()
+        if self.model is not None:
+            oprot.writeFieldBegin("model", TType.STRING, 2)
+            oprot.writeString(
+                self.model.encode("utf-8") if sys.version_info[0] == 2 else self.model
+            )
+            oprot.writeFieldEnd()
+        if self.os_name is not None:
+            oprot.writeFieldBegin("os_name", TType.STRING, 3)
+            oprot.writeString(
+                self.os_name.encode("utf-8") if sys.version_info[0] == 2 else self.os_name
+            )
+            oprot.writeFieldEnd()
+        if self.os_version is not None:
+            oprot.writeFieldBegin("os_version", TType.STRING, 4)
+            oprot.writeString(
+                self.os_version.encode("utf-8") if sys.version_info[0] == 2 else self.os_version
+            )
+            oprot.writeFieldEnd()
+        if self.form_factor is not None:
+            oprot.writeFieldBegin("form_factor", TType.STRING, 5)
+            oprot.writeString(
+                self.form_factor.encode("utf-8") if sys.version_info[0] == 2 else self.form_factor
+            )
+            oprot.writeFieldEnd()
+        if self.attestation_verdict is not None:
+            oprot.writeFieldBegin("attestation_verdict", TType.STRING, 6)
+            oprot.writeString(
+                self.attestation_verdict.encode("utf-8")
+                if sys.version_info[0] == 2
+                else self.attestation_verdict
+            )
+            oprot.writeFieldEnd()
         oprot.writeFieldStop()
         oprot.writeStructEnd()
 
@@ -301,16 +493,34 @@ class OriginService(object):
     Attributes:
      - name: The name of the origin service.
 
+     - version: The version of the origin service.
+
+     - deploy_id: The id of the deployment of the origin service.
+
+     - edge_pop: The edge POP or region that terminated the client connection, e.g.
+    "sjc1".
+
 
     """
 
-    __slots__ = ("name",)
+    __slots__ = (
+        "name",
+        "version",
+        "deploy_id",
+        "edge_pop",
+    )
 
     def __init__(
         self,
         name=None,
+        version=None,
+        deploy_id=None,
+        edge_pop=None,
     ):
         self.name = name
+        self.version = version
+        self.deploy_id = deploy_id
+        self.edge_pop = edge_pop
 
     def read(self, iprot):
         if (
@@ -334,6 +544,33 @@ class OriginService(object):
                     )
                 else:
                     iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.STRING:
+                    self.version = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 3:
+                if ftype == TType.STRING:
+                    self.deploy_id = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 4:
+                if ftype == TType.STRING:
+                    self.edge_pop = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
             else:
                 iprot.skip(ftype)
             iprot.readFieldEnd()
@@ -348,6 +585,24 @@ class OriginService(object):
             oprot.writeFieldBegin("name", TType.STRING, 1)
             oprot.writeString(self.name.encode("utf-8") if sys.version_info[0] == 2 else self.name)
             oprot.writeFieldEnd()
+        if self.version is not None:
+            oprot.writeFieldBegin("version", TType.STRING, 2)
+            oprot.writeString(
+                self.version.encode("utf-8") if sys.version_info[0] == 2 else self.version
+            )
+            oprot.writeFieldEnd()
+        if self.deploy_id is not None:
+            oprot.writeFieldBegin("deploy_id", TType.STRING, 3)
+            oprot.writeString(
+                self.deploy_id.encode("utf-8") if sys.version_info[0] == 2 else self.deploy_id
+            )
+            oprot.writeFieldEnd()
+        if self.edge_pop is not None:
+            oprot.writeFieldBegin("edge_pop", TType.STRING, 4)
+            oprot.writeString(
+                self.edge_pop.encode("utf-8") if sys.version_info[0] == 2 else self.edge_pop
+            )
+            oprot.writeFieldEnd()
         oprot.writeFieldStop()
         oprot.writeStructEnd()
 
@@ -384,16 +639,38 @@ class Geolocation(object):
 
     Attributes:
      - country_code: The country code of the requesting client based on geographic location.
+     - subdivision_code: The ISO 3166-2 subdivision code (e.g. state or province) of the
+    requesting client based on geographic location, e.g. "US-CA".
+     - city: The name of the city of the requesting client based on geographic
+    location, e.g. "San Francisco".
+     - metro_code: The Nielsen DMA (Designated Market Area) code of the requesting client
+    based on geographic location, e.g. 807 for San Francisco-Oakland-San Jose.
+     - provenance: How the country code was determined, one of "ip" (IP address lookup),
+    "account" (user account setting), or "carrier" (mobile carrier header).
 
     """
 
-    __slots__ = ("country_code",)
+    __slots__ = (
+        "country_code",
+        "subdivision_code",
+        "city",
+        "metro_code",
+        "provenance",
+    )
 
     def __init__(
         self,
         country_code=None,
+        subdivision_code=None,
+        city=None,
+        metro_code=None,
+        provenance=None,
     ):
         self.country_code = country_code
+        self.subdivision_code = subdivision_code
+        self.city = city
+        self.metro_code = metro_code
+        self.provenance = provenance
 
     def read(self, iprot):
         if (
@@ -417,6 +694,38 @@ class Geolocation(object):
                     )
                 else:
                     iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.STRING:
+                    self.subdivision_code = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 3:
+                if ftype == TType.STRING:
+                    self.city = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 4:
+                if ftype == TType.I32:
+                    self.metro_code = iprot.readI32()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 5:
+                if ftype == TType.STRING:
+                    self.provenance = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
             else:
                 iprot.skip(ftype)
             iprot.readFieldEnd()
@@ -433,6 +742,28 @@ class Geolocation(object):
                 self.country_code.encode("utf-8") if sys.version_info[0] == 2 else self.country_code
             )
             oprot.writeFieldEnd()
+        if self.subdivision_code is not None:
+            oprot.writeFieldBegin("subdivision_code", TType.STRING, 2)
+            oprot.writeString(
+                self.subdivision_code.encode("utf-8")
+                if sys.version_info[0] == 2
+                else self.subdivision_code
+            )
+            oprot.writeFieldEnd()
+        if self.city is not None:
+            oprot.writeFieldBegin("city", TType.STRING, 3)
+            oprot.writeString(self.city.encode("utf-8") if sys.version_info[0] == 2 else self.city)
+            oprot.writeFieldEnd()
+        if self.metro_code is not None:
+            oprot.writeFieldBegin("metro_code", TType.I32, 4)
+            oprot.writeI32(self.metro_code)
+            oprot.writeFieldEnd()
+        if self.provenance is not None:
+            oprot.writeFieldBegin("provenance", TType.STRING, 5)
+            oprot.writeString(
+                self.provenance.encode("utf-8") if sys.version_info[0] == 2 else self.provenance
+            )
+            oprot.writeFieldEnd()
         oprot.writeFieldStop()
         oprot.writeStructEnd()
 
@@ -468,16 +799,23 @@ class RequestId(object):
 
     Attributes:
      - readable_id: The id of this Edge Request, in the most human-readable format.
+     - trace_id: The W3C Trace Context trace id of the distributed trace this Edge Request
+    belongs to, as 32 lowercase hex characters.
 
     """
 
-    __slots__ = ("readable_id",)
+    __slots__ = (
+        "readable_id",
+        "trace_id",
+    )
 
     def __init__(
         self,
         readable_id=None,
+        trace_id=None,
     ):
         self.readable_id = readable_id
+        self.trace_id = trace_id
 
     def read(self, iprot):
         if (
@@ -501,6 +839,15 @@ class RequestId(object):
                     )
                 else:
                     iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.STRING:
+                    self.trace_id = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
             else:
                 iprot.skip(ftype)
             iprot.readFieldEnd()
@@ -517,6 +864,12 @@ class RequestId(object):
                 self.readable_id.encode("utf-8") if sys.version_info[0] == 2 else self.readable_id
             )
             oprot.writeFieldEnd()
+        if self.trace_id is not None:
+            oprot.writeFieldBegin("trace_id", TType.STRING, 2)
+            oprot.writeString(
+                self.trace_id.encode("utf-8") if sys.version_info[0] == 2 else self.trace_id
+            )
+            oprot.writeFieldEnd()
         oprot.writeFieldStop()
         oprot.writeStructEnd()
 
@@ -555,16 +908,29 @@ class Locale(object):
      - locale_code: IETF language code representing the client locale preferences.
     Preferably in BCP-47 format ({lang} or {lang}-{region}),
     but underscore separated locales also valid ({lang}_{region})
+     - timezone: IANA time zone database name of the time zone of the client,
+    e.g. "America/New_York".
+     - accepted_locale_codes: The full list of locales accepted by the client, in order of preference,
+    e.g. from the Accept-Language header.
+
 
     """
 
-    __slots__ = ("locale_code",)
+    __slots__ = (
+        "locale_code",
+        "timezone",
+        "accepted_locale_codes",
+    )
 
     def __init__(
         self,
         locale_code=None,
+        timezone=None,
+        accepted_locale_codes=None,
     ):
         self.locale_code = locale_code
+        self.timezone = timezone
+        self.accepted_locale_codes = accepted_locale_codes
 
     def read(self, iprot):
         if (
@@ -588,6 +954,29 @@ class Locale(object):
                     )
                 else:
                     iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.STRING:
+                    self.timezone = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 3:
+                if ftype == TType.LIST:
+                    self.accepted_locale_codes = []
+                    (_etype3, _size0) = iprot.readListBegin()
+                    for _i4 in range(_size0):
+                        _elem5 = (
+                            iprot.readString().decode("utf-8", errors="replace")
+                            if sys.version_info[0] == 2
+                            else iprot.readString()
+                        )
+                        self.accepted_locale_codes.append(_elem5)
+                    iprot.readListEnd()
+                else:
+                    iprot.skip(ftype)
             else:
                 iprot.skip(ftype)
             iprot.readFieldEnd()
@@ -604,6 +993,19 @@ class Locale(object):
                 self.locale_code.encode("utf-8") if sys.version_info[0] == 2 else self.locale_code
             )
             oprot.writeFieldEnd()
+        if self.timezone is not None:
+            oprot.writeFieldBegin("timezone", TType.STRING, 2)
+            oprot.writeString(
+                self.timezone.encode("utf-8") if sys.version_info[0] == 2 else self.timezone
+            )
+            oprot.writeFieldEnd()
+        if self.accepted_locale_codes is not None:
+            oprot.writeFieldBegin("accepted_locale_codes", TType.LIST, 3)
+            oprot.writeListBegin(TType.STRING, len(self.accepted_locale_codes))
+            for iter6 in self.accepted_locale_codes:
+                oprot.writeString(iter6.encode("utf-8") if sys.version_info[0] == 2 else iter6)
+            oprot.writeListEnd()
+            oprot.writeFieldEnd()
         oprot.writeFieldStop()
         oprot.writeStructEnd()
 
@@ -628,49 +1030,638 @@ class Locale(object):
         return not (self == other)
 
 
-class Request(object):
+class Client(object):
     """
-    Container model for the Edge-Request context header.
+    Metadata about the client making the request to our services that we want
+    to propagate between services.
 
-    Baseplate will automatically parse this from the "Edge-Request" header and
-    provides an interface that wraps this Thrift model.  You should not need to
+    This model is a component of the "Edge-Request" header.  You should not need to
     interact with this model directly, but rather through the EdgeRequestContext
     interface provided by baseplate.
 
 
     Attributes:
-     - loid
-     - session
-     - authentication_token
-     - device
-     - origin_service
-     - geolocation
-     - request_id
-     - locale
+     - ip: The IP address of the client, as seen by the edge.
+
+    Either IPv4 dotted decimal ("192.0.2.1") or IPv6 ("2001:db8::1") form.
+     - user_agent: The User-Agent header sent by the client to the edge.
+     - app_version: The semantic version of the client application, e.g. "2023.21.0".
+
+     - build_number: The build number of the client application.
+
+     - platform: The platform of the client, one of "ios", "android", "web", "mweb"
+    (mobile web), or "api" (third party API clients).
+
+     - internal_network: Whether the request originates from the corporate network (VPN) or an
+    employee device.
+     - limit_ad_tracking: Whether the client asked to limit ad tracking, e.g. via iOS App Tracking
+    Transparency, the Android advertising ID opt-out, or the DNT and Sec-GPC
+    headers.
+     - anonymizing_proxy: Whether the edge determined the client IP belongs to a VPN or an
+    anonymizing proxy.
+     - asn: The autonomous system number of the client IP, 0 if unknown.
 
     """
 
     __slots__ = (
-        "loid",
-        "session",
-        "authentication_token",
-        "device",
-        "origin_service",
-        "geolocation",
-        "request_id",
-        "locale",
+        "ip",
+        "user_agent",
+        "app_version",
+        "build_number",
+        "platform",
+        "internal_network",
+        "limit_ad_tracking",
+        "anonymizing_proxy",
+        "asn",
     )
 
     def __init__(
         self,
-        loid=None,
-        session=None,
-        authentication_token=None,
-        device=None,
-        origin_service=None,
-        geolocation=None,
-        request_id=None,
+        ip=None,
+        user_agent=None,
+        app_version=None,
+        build_number=None,
+        platform=None,
+        internal_network=None,
+        limit_ad_tracking=None,
+        anonymizing_proxy=None,
+        asn=None,
+    ):
+        self.ip = ip
+        self.user_agent = user_agent
+        self.app_version = app_version
+        self.build_number = build_number
+        self.platform = platform
+        self.internal_network = internal_network
+        self.limit_ad_tracking = limit_ad_tracking
+        self.anonymizing_proxy = anonymizing_proxy
+        self.asn = asn
+
+    def read(self, iprot):
+        if (
+            iprot._fast_decode is not None
+            and isinstance(iprot.trans, TTransport.CReadableTransport)
+            and self.thrift_spec is not None
+        ):
+            iprot._fast_decode(self, iprot, [self.__class__, self.thrift_spec])
+            return
+        iprot.readStructBegin()
+        while True:
+            (fname, ftype, fid) = iprot.readFieldBegin()
+            if ftype == TType.STOP:
+                break
+            if fid == 1:
+                if ftype == TType.STRING:
+                    self.ip = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.STRING:
+                    self.user_agent = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 3:
+                if ftype == TType.STRING:
+                    self.app_version = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 4:
+                if ftype == TType.I64:
+                    self.build_number = iprot.readI64()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 5:
+                if ftype == TType.STRING:
+                    self.platform = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 6:
+                if ftype == TType.BOOL:
+                    self.internal_network = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 7:
+                if ftype == TType.BOOL:
+                    self.limit_ad_tracking = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 8:
+                if ftype == TType.BOOL:
+                    self.anonymizing_proxy = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 9:
+                if ftype == TType.I64:
+                    self.asn = iprot.readI64()
+                else:
+                    iprot.skip(ftype)
+            else:
+                iprot.skip(ftype)
+            iprot.readFieldEnd()
+        iprot.readStructEnd()
+
+    def write(self, oprot):
+        if oprot._fast_encode is not None and self.thrift_spec is not None:
+            oprot.trans.write(oprot._fast_encode(self, [self.__class__, self.thrift_spec]))
+            return
+        oprot.writeStructBegin("Client")
+        if self.ip is not None:
+            oprot.writeFieldBegin("ip", TType.STRING, 1)
+            oprot.writeString(self.ip.encode("utf-8") if sys.version_info[0] == 2 else self.ip)
+            oprot.writeFieldEnd()
+        if self.user_agent is not None:
+            oprot.writeFieldBegin("user_agent", TType.STRING, 2)
+            oprot.writeString(
+                self.user_agent.encode("utf-8") if sys.version_info[0] == 2 else self.user_agent
+            )
+            oprot.writeFieldEnd()
+        if self.app_version is not None:
+            oprot.writeFieldBegin("app_version", TType.STRING, 3)
+            oprot.writeString(
+                self.app_version.encode("utf-8") if sys.version_info[0] == 2 else self.app_version
+            )
+            oprot.writeFieldEnd()
+        if self.build_number is not None:
+            oprot.writeFieldBegin("build_number", TType.I64, 4)
+            oprot.writeI64(self.build_number)
+            oprot.writeFieldEnd()
+        if self.platform is not None:
+            oprot.writeFieldBegin("platform", TType.STRING, 5)
+            oprot.writeString(
+                self.platform.encode("utf-8") if sys.version_info[0] == 2 else self.platform
+            )
+            oprot.writeFieldEnd()
+        if self.internal_network is not None:
+            oprot.writeFieldBegin("internal_network", TType.BOOL, 6)
+            oprot.writeBool(self.internal_network)
+            oprot.writeFieldEnd()
+        if self.limit_ad_tracking is not None:
+            oprot.writeFieldBegin("limit_ad_tracking", TType.BOOL, 7)
+            oprot.writeBool(self.limit_ad_tracking)
+            oprot.writeFieldEnd()
+        if self.anonymizing_proxy is not None:
+            oprot.writeFieldBegin("anonymizing_proxy", TType.BOOL, 8)
+            oprot.writeBool(self.anonymizing_proxy)
+            oprot.writeFieldEnd()
+        if self.asn is not None:
+            oprot.writeFieldBegin("asn", TType.I64, 9)
+            oprot.writeI64(self.asn)
+            oprot.writeFieldEnd()
+        oprot.writeFieldStop()
+        oprot.writeStructEnd()
+
+    def validate(self):
+        return
+
+    def __repr__(self):
+        L = ["%s=%r" % (key, getattr(self, key)) for key in self.__slots__]
+        return "%s(%s)" % (self.__class__.__name__, ", ".join(L))
+
+    def __eq__(self, other):
+        if not isinstance(other, self.__class__):
+            return False
+        for attr in self.__slots__:
+            my_val = getattr(self, attr)
+            other_val = getattr(other, attr)
+            if my_val != other_val:
+                return False
+        return True
+
+    def __ne__(self, other):
+        return not (self == other)
+
+
+class Consent(object):
+    """
+    The privacy consents given by the user making the request.
+
+    This model is a component of the "Edge-Request" header.  You should not need to
+    interact with this model directly, but rather through the EdgeRequestContext
+    interface provided by baseplate.
+
+
+    Attributes:
+     - ads_personalization: Whether the user consented to personalized ads.
+     - analytics: Whether the user consented to analytics tracking.
+     - third_party_sharing: Whether the user consented to sharing their data with third parties.
+
+    """
+
+    __slots__ = (
+        "ads_personalization",
+        "analytics",
+        "third_party_sharing",
+    )
+
+    def __init__(
+        self,
+        ads_personalization=None,
+        analytics=None,
+        third_party_sharing=None,
+    ):
+        self.ads_personalization = ads_personalization
+        self.analytics = analytics
+        self.third_party_sharing = third_party_sharing
+
+    def read(self, iprot):
+        if (
+            iprot._fast_decode is not None
+            and isinstance(iprot.trans, TTransport.CReadableTransport)
+            and self.thrift_spec is not None
+        ):
+            iprot._fast_decode(self, iprot, [self.__class__, self.thrift_spec])
+            return
+        iprot.readStructBegin()
+        while True:
+            (fname, ftype, fid) = iprot.readFieldBegin()
+            if ftype == TType.STOP:
+                break
+            if fid == 1:
+                if ftype == TType.BOOL:
+                    self.ads_personalization = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.BOOL:
+                    self.analytics = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 3:
+                if ftype == TType.BOOL:
+                    self.third_party_sharing = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            else:
+                iprot.skip(ftype)
+            iprot.readFieldEnd()
+        iprot.readStructEnd()
+
+    def write(self, oprot):
+        if oprot._fast_encode is not None and self.thrift_spec is not None:
+            oprot.trans.write(oprot._fast_encode(self, [self.__class__, self.thrift_spec]))
+            return
+        oprot.writeStructBegin("Consent")
+        if self.ads_personalization is not None:
+            oprot.writeFieldBegin("ads_personalization", TType.BOOL, 1)
+            oprot.writeBool(self.ads_personalization)
+            oprot.writeFieldEnd()
+        if self.analytics is not None:
+            oprot.writeFieldBegin("analytics", TType.BOOL, 2)
+            oprot.writeBool(self.analytics)
+            oprot.writeFieldEnd()
+        if self.third_party_sharing is not None:
+            oprot.writeFieldBegin("third_party_sharing", TType.BOOL, 3)
+            oprot.writeBool(self.third_party_sharing)
+            oprot.writeFieldEnd()
+        oprot.writeFieldStop()
+        oprot.writeStructEnd()
+
+    def validate(self):
+        return
+
+    def __repr__(self):
+        L = ["%s=%r" % (key, getattr(self, key)) for key in self.__slots__]
+        return "%s(%s)" % (self.__class__.__name__, ", ".join(L))
+
+    def __eq__(self, other):
+        if not isinstance(other, self.__class__):
+            return False
+        for attr in self.__slots__:
+            my_val = getattr(self, attr)
+            other_val = getattr(other, attr)
+            if my_val != other_val:
+                return False
+        return True
+
+    def __ne__(self, other):
+        return not (self == other)
+
+
+class Account(object):
+    """
+    The components of the account of the user making the request that we want
+    to propagate between services.
+
+    This model is a component of the "Edge-Request" header.  You should not need to
+    interact with this model directly, but rather through the EdgeRequestContext
+    interface provided by baseplate.
+
+
+    Attributes:
+     - teen_restricted: Whether the account is subject to teen-safety or parental-control
+    restrictions.
+     - email_verified: Whether the email address of the account is verified.
+     - premium: Whether the account has an active premium subscription at the time of
+    the request.
+
+    """
+
+    __slots__ = (
+        "teen_restricted",
+        "email_verified",
+        "premium",
+    )
+
+    def __init__(
+        self,
+        teen_restricted=None,
+        email_verified=None,
+        premium=None,
+    ):
+        self.teen_restricted = teen_restricted
+        self.email_verified = email_verified
+        self.premium = premium
+
+    def read(self, iprot):
+        if (
+            iprot._fast_decode is not None
+            and isinstance(iprot.trans, TTransport.CReadableTransport)
+            and self.thrift_spec is not None
+        ):
+            iprot._fast_decode(self, iprot, [self.__class__, self.thrift_spec])
+            return
+        iprot.readStructBegin()
+        while True:
+            (fname, ftype, fid) = iprot.readFieldBegin()
+            if ftype == TType.STOP:
+                break
+            if fid == 1:
+                if ftype == TType.BOOL:
+                    self.teen_restricted = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.BOOL:
+                    self.email_verified = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 3:
+                if ftype == TType.BOOL:
+                    self.premium = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            else:
+                iprot.skip(ftype)
+            iprot.readFieldEnd()
+        iprot.readStructEnd()
+
+    def write(self, oprot):
+        if oprot._fast_encode is not None and self.thrift_spec is not None:
+            oprot.trans.write(oprot._fast_encode(self, [self.__class__, self.thrift_spec]))
+            return
+        oprot.writeStructBegin("Account")
+        if self.teen_restricted is not None:
+            oprot.writeFieldBegin("teen_restricted", TType.BOOL, 1)
+            oprot.writeBool(self.teen_restricted)
+            oprot.writeFieldEnd()
+        if self.email_verified is not None:
+            oprot.writeFieldBegin("email_verified", TType.BOOL, 2)
+            oprot.writeBool(self.email_verified)
+            oprot.writeFieldEnd()
+        if self.premium is not None:
+            oprot.writeFieldBegin("premium", TType.BOOL, 3)
+            oprot.writeBool(self.premium)
+            oprot.writeFieldEnd()
+        oprot.writeFieldStop()
+        oprot.writeStructEnd()
+
+    def validate(self):
+        return
+
+    def __repr__(self):
+        L = ["%s=%r" % (key, getattr(self, key)) for key in self.__slots__]
+        return "%s(%s)" % (self.__class__.__name__, ", ".join(L))
+
+    def __eq__(self, other):
+        if not isinstance(other, self.__class__):
+            return False
+        for attr in self.__slots__:
+            my_val = getattr(self, attr)
+            other_val = getattr(other, attr)
+            if my_val != other_val:
+                return False
+        return True
+
+    def __ne__(self, other):
+        return not (self == other)
+
+
+class Traffic(object):
+    """
+    The classification of the traffic a request belongs to, set by the edge.
+
+    This model is a component of the "Edge-Request" header.  You should not need to
+    interact with this model directly, but rather through the EdgeRequestContext
+    interface provided by baseplate.
+
+
+    Attributes:
+     - shadow: Whether the request is replayed or shadowed (dark launches, traffic
+    mirroring), in which case side effects should be suppressed.
+     - synthetic: Whether the request is generated by a synthetic probe or a load test.
+     - canary: Whether the request belongs to the canary (early rollout) cohort chosen
+    by the edge.
+
+    """
+
+    __slots__ = (
+        "shadow",
+        "synthetic",
+        "canary",
+    )
+
+    def __init__(
+        self,
+        shadow=None,
+        synthetic=None,
+        canary=None,
+    ):
+        self.shadow = shadow
+        self.synthetic = synthetic
+        self.canary = canary
+
+    def read(self, iprot):
+        if (
+            iprot._fast_decode is not None
+            and isinstance(iprot.trans, TTransport.CReadableTransport)
+            and self.thrift_spec is not None
+        ):
+            iprot._fast_decode(self, iprot, [self.__class__, self.thrift_spec])
+            return
+        iprot.readStructBegin()
+        while True:
+            (fname, ftype, fid) = iprot.readFieldBegin()
+            if ftype == TType.STOP:
+                break
+            if fid == 1:
+                if ftype == TType.BOOL:
+                    self.shadow = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 2:
+                if ftype == TType.BOOL:
+                    self.synthetic = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 3:
+                if ftype == TType.BOOL:
+                    self.canary = iprot.readBool()
+                else:
+                    iprot.skip(ftype)
+            else:
+                iprot.skip(ftype)
+            iprot.readFieldEnd()
+        iprot.readStructEnd()
+
+    def write(self, oprot):
+        if oprot._fast_encode is not None and self.thrift_spec is not None:
+            oprot.trans.write(oprot._fast_encode(self, [self.__class__, self.thrift_spec]))
+            return
+        oprot.writeStructBegin("Traffic")
+        if self.shadow is not None:
+            oprot.writeFieldBegin("shadow", TType.BOOL, 1)
+            oprot.writeBool(self.shadow)
+            oprot.writeFieldEnd()
+        if self.synthetic is not None:
+            oprot.writeFieldBegin("synthetic", TType.BOOL, 2)
+            oprot.writeBool(self.synthetic)
+            oprot.writeFieldEnd()
+        if self.canary is not None:
+            oprot.writeFieldBegin("canary", TType.BOOL, 3)
+            oprot.writeBool(self.canary)
+            oprot.writeFieldEnd()
+        oprot.writeFieldStop()
+        oprot.writeStructEnd()
+
+    def validate(self):
+        return
+
+    def __repr__(self):
+        L = ["%s=%r" % (key, getattr(self, key)) for key in self.__slots__]
+        return "%s(%s)" % (self.__class__.__name__, ", ".join(L))
+
+    def __eq__(self, other):
+        if not isinstance(other, self.__class__):
+            return False
+        for attr in self.__slots__:
+            my_val = getattr(self, attr)
+            other_val = getattr(other, attr)
+            if my_val != other_val:
+                return False
+        return True
+
+    def __ne__(self, other):
+        return not (self == other)
+
+
+class Request(object):
+    """
+    Container model for the Edge-Request context header.
+
+    Baseplate will automatically parse this from the "Edge-Request" header and
+    provides an interface that wraps this Thrift model.  You should not need to
+    interact with this model directly, but rather through the EdgeRequestContext
+    interface provided by baseplate.
+
+
+    Attributes:
+     - loid
+     - session
+     - authentication_token
+     - device
+     - origin_service
+     - geolocation
+     - request_id
+     - locale
+     - client
+     - feature_flag_overrides: Per-request feature flag overrides, keyed by feature flag name.
+
+    Only set by the edge for internal and debug clients.
+     - consent
+     - compliance_region: The data protection regime that applies to the request, computed once at
+    the edge, one of "gdpr", "ccpa", "lgpd", or "none".
+     - account
+     - baggage: Generic low-cardinality metadata set by the edge, for values that don't
+    warrant their own field.
+     - workload_identity: The SPIFFE ID of the calling workload, for requests initiated by a
+    service instead of a user, e.g. "spiffe://reddit.com/ns/jobs/sa/backfill".
+     - deadline_ms: The absolute deadline by which the response must be sent back to the
+    client, in number of milliseconds since epoch.
+     - traffic
+     - hops: The names of the services that forwarded this Edge-Request header, in
+    order, with the most recent hops last.
+     - tenant_id: The id of the tenant (brand) the request is served for, in deployments
+    that serve multiple tenants from shared infrastructure.
+     - referring_surface: The product surface that initiated the request, one of "home_feed",
+    "push_notification", "email", "embed", or "other".
+     - service_authentication_token: The auth token of the service initiating the request on behalf of the
+    user in authentication_token, if any.
+
+    """
+
+    __slots__ = (
+        "loid",
+        "session",
+        "authentication_token",
+        "device",
+        "origin_service",
+        "geolocation",
+        "request_id",
+        "locale",
+        "client",
+        "feature_flag_overrides",
+        "consent",
+        "compliance_region",
+        "account",
+        "baggage",
+        "workload_identity",
+        "deadline_ms",
+        "traffic",
+        "hops",
+        "tenant_id",
+        "referring_surface",
+        "service_authentication_token",
+    )
+
+    def __init__(
+        self,
+        loid=None,
+        session=None,
+        authentication_token=None,
+        device=None,
+        origin_service=None,
+        geolocation=None,
+        request_id=None,
         locale=None,
+        client=None,
+        feature_flag_overrides=None,
+        consent=None,
+        compliance_region=None,
+        account=None,
+        baggage=None,
+        workload_identity=None,
+        deadline_ms=None,
+        traffic=None,
+        hops=None,
+        tenant_id=None,
+        referring_surface=None,
+        service_authentication_token=None,
     ):
         self.loid = loid
         self.session = session
@@ -680,6 +1671,19 @@ class Request(object):
         self.geolocation = geolocation
         self.request_id = request_id
         self.locale = locale
+        self.client = client
+        self.feature_flag_overrides = feature_flag_overrides
+        self.consent = consent
+        self.compliance_region = compliance_region
+        self.account = account
+        self.baggage = baggage
+        self.workload_identity = workload_identity
+        self.deadline_ms = deadline_ms
+        self.traffic = traffic
+        self.hops = hops
+        self.tenant_id = tenant_id
+        self.referring_surface = referring_surface
+        self.service_authentication_token = service_authentication_token
 
     def read(self, iprot):
         if (
@@ -745,6 +1749,132 @@ class Request(object):
                     self.locale.read(iprot)
                 else:
                     iprot.skip(ftype)
+            elif fid == 9:
+                if ftype == TType.STRUCT:
+                    self.client = Client()
+                    self.client.read(iprot)
+                else:
+                    iprot.skip(ftype)
+            elif fid == 10:
+                if ftype == TType.MAP:
+                    self.feature_flag_overrides = {}
+                    (_ktype8, _vtype9, _size7) = iprot.readMapBegin()
+                    for _i11 in range(_size7):
+                        _key12 = (
+                            iprot.readString().decode("utf-8", errors="replace")
+                            if sys.version_info[0] == 2
+                            else iprot.readString()
+                        )
+                        _val13 = (
+                            iprot.readString().decode("utf-8", errors="replace")
+                            if sys.version_info[0] == 2
+                            else iprot.readString()
+                        )
+                        self.feature_flag_overrides[_key12] = _val13
+                    iprot.readMapEnd()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 11:
+                if ftype == TType.STRUCT:
+                    self.consent = Consent()
+                    self.consent.read(iprot)
+                else:
+                    iprot.skip(ftype)
+            elif fid == 12:
+                if ftype == TType.STRING:
+                    self.compliance_region = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 13:
+                if ftype == TType.STRUCT:
+                    self.account = Account()
+                    self.account.read(iprot)
+                else:
+                    iprot.skip(ftype)
+            elif fid == 14:
+                if ftype == TType.MAP:
+                    self.baggage = {}
+                    (_ktype15, _vtype16, _size14) = iprot.readMapBegin()
+                    for _i18 in range(_size14):
+                        _key19 = (
+                            iprot.readString().decode("utf-8", errors="replace")
+                            if sys.version_info[0] == 2
+                            else iprot.readString()
+                        )
+                        _val20 = (
+                            iprot.readString().decode("utf-8", errors="replace")
+                            if sys.version_info[0] == 2
+                            else iprot.readString()
+                        )
+                        self.baggage[_key19] = _val20
+                    iprot.readMapEnd()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 15:
+                if ftype == TType.STRING:
+                    self.workload_identity = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 16:
+                if ftype == TType.I64:
+                    self.deadline_ms = iprot.readI64()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 17:
+                if ftype == TType.STRUCT:
+                    self.traffic = Traffic()
+                    self.traffic.read(iprot)
+                else:
+                    iprot.skip(ftype)
+            elif fid == 18:
+                if ftype == TType.LIST:
+                    self.hops = []
+                    (_etype24, _size21) = iprot.readListBegin()
+                    for _i25 in range(_size21):
+                        _elem26 = (
+                            iprot.readString().decode("utf-8", errors="replace")
+                            if sys.version_info[0] == 2
+                            else iprot.readString()
+                        )
+                        self.hops.append(_elem26)
+                    iprot.readListEnd()
+                else:
+                    iprot.skip(ftype)
+            elif fid == 19:
+                if ftype == TType.STRING:
+                    self.tenant_id = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 20:
+                if ftype == TType.STRING:
+                    self.referring_surface = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
+            elif fid == 21:
+                if ftype == TType.STRING:
+                    self.service_authentication_token = (
+                        iprot.readString().decode("utf-8", errors="replace")
+                        if sys.version_info[0] == 2
+                        else iprot.readString()
+                    )
+                else:
+                    iprot.skip(ftype)
             else:
                 iprot.skip(ftype)
             iprot.readFieldEnd()
@@ -791,6 +1921,87 @@ class Request(object):
             oprot.writeFieldBegin("locale", TType.STRUCT, 8)
             self.locale.write(oprot)
             oprot.writeFieldEnd()
+        if self.client is not None:
+            oprot.writeFieldBegin("client", TType.STRUCT, 9)
+            self.client.write(oprot)
+            oprot.writeFieldEnd()
+        if self.feature_flag_overrides is not None:
+            oprot.writeFieldBegin("feature_flag_overrides", TType.MAP, 10)
+            oprot.writeMapBegin(TType.STRING, TType.STRING, len(self.feature_flag_overrides))
+            for kiter27, viter28 in self.feature_flag_overrides.items():
+                oprot.writeString(kiter27.encode("utf-8") if sys.version_info[0] == 2 else kiter27)
+                oprot.writeString(viter28.encode("utf-8") if sys.version_info[0] == 2 else viter28)
+            oprot.writeMapEnd()
+            oprot.writeFieldEnd()
+        if self.consent is not None:
+            oprot.writeFieldBegin("consent", TType.STRUCT, 11)
+            self.consent.write(oprot)
+            oprot.writeFieldEnd()
+        if self.compliance_region is not None:
+            oprot.writeFieldBegin("compliance_region", TType.STRING, 12)
+            oprot.writeString(
+                self.compliance_region.encode("utf-8")
+                if sys.version_info[0] == 2
+                else self.compliance_region
+            )
+            oprot.writeFieldEnd()
+        if self.account is not None:
+            oprot.writeFieldBegin("account", TType.STRUCT, 13)
+            self.account.write(oprot)
+            oprot.writeFieldEnd()
+        if self.baggage is not None:
+            oprot.writeFieldBegin("baggage", TType.MAP, 14)
+            oprot.writeMapBegin(TType.STRING, TType.STRING, len(self.baggage))
+            for kiter29, viter30 in self.baggage.items():
+                oprot.writeString(kiter29.encode("utf-8") if sys.version_info[0] == 2 else kiter29)
+                oprot.writeString(viter30.encode("utf-8") if sys.version_info[0] == 2 else viter30)
+            oprot.writeMapEnd()
+            oprot.writeFieldEnd()
+        if self.workload_identity is not None:
+            oprot.writeFieldBegin("workload_identity", TType.STRING, 15)
+            oprot.writeString(
+                self.workload_identity.encode("utf-8")
+                if sys.version_info[0] == 2
+                else self.workload_identity
+            )
+            oprot.writeFieldEnd()
+        if self.deadline_ms is not None:
+            oprot.writeFieldBegin("deadline_ms", TType.I64, 16)
+            oprot.writeI64(self.deadline_ms)
+            oprot.writeFieldEnd()
+        if self.traffic is not None:
+            oprot.writeFieldBegin("traffic", TType.STRUCT, 17)
+            self.traffic.write(oprot)
+            oprot.writeFieldEnd()
+        if self.hops is not None:
+            oprot.writeFieldBegin("hops", TType.LIST, 18)
+            oprot.writeListBegin(TType.STRING, len(self.hops))
+            for iter31 in self.hops:
+                oprot.writeString(iter31.encode("utf-8") if sys.version_info[0] == 2 else iter31)
+            oprot.writeListEnd()
+            oprot.writeFieldEnd()
+        if self.tenant_id is not None:
+            oprot.writeFieldBegin("tenant_id", TType.STRING, 19)
+            oprot.writeString(
+                self.tenant_id.encode("utf-8") if sys.version_info[0] == 2 else self.tenant_id
+            )
+            oprot.writeFieldEnd()
+        if self.referring_surface is not None:
+            oprot.writeFieldBegin("referring_surface", TType.STRING, 20)
+            oprot.writeString(
+                self.referring_surface.encode("utf-8")
+                if sys.version_info[0] == 2
+                else self.referring_surface
+            )
+            oprot.writeFieldEnd()
+        if self.service_authentication_token is not None:
+            oprot.writeFieldBegin("service_authentication_token", TType.STRING, 21)
+            oprot.writeString(
+                self.service_authentication_token.encode("utf-8")
+                if sys.version_info[0] == 2
+                else self.service_authentication_token
+            )
+            oprot.writeFieldEnd()
         oprot.writeFieldStop()
         oprot.writeStructEnd()
 
@@ -819,30 +2030,65 @@ all_structs.append(Loid)
 Loid.thrift_spec = (
     None,  # 0
     (
-        1,
+        1,
+        TType.STRING,
+        "id",
+        "UTF8",
+        None,
+    ),  # 1
+    (
+        2,
+        TType.I64,
+        "created_ms",
+        None,
+        None,
+    ),  # 2
+)
+all_structs.append(Session)
+Session.thrift_spec = (
+    None,  # 0
+    (
+        1,
+        TType.STRING,
+        "id",
+        "UTF8",
+        None,
+    ),  # 1
+    (
+        2,
+        TType.I64,
+        "created_ms",
+        None,
+        None,
+    ),  # 2
+    (
+        3,
         TType.STRING,
-        "id",
+        "type",
         "UTF8",
         None,
-    ),  # 1
+    ),  # 3
     (
-        2,
+        4,
         TType.I64,
-        "created_ms",
+        "elevated_until_ms",
         None,
         None,
-    ),  # 2
-)
-all_structs.append(Session)
-Session.thrift_spec = (
-    None,  # 0
+    ),  # 4
     (
-        1,
+        5,
+        TType.BOOL,
+        "mfa",
+        None,
+        None,
+    ),  # 5
+    (
+        6,
         TType.STRING,
-        "id",
+        "auth_method",
         "UTF8",
         None,
-    ),  # 1
+    ),  # 6
 )
 all_structs.append(Device)
 Device.thrift_spec = (
@@ -854,6 +2100,41 @@ Device.thrift_spec = (
         "UTF8",
         None,
     ),  # 1
+    (
+        2,
+        TType.STRING,
+        "model",
+        "UTF8",
+        None,
+    ),  # 2
+    (
+        3,
+        TType.STRING,
+        "os_name",
+        "UTF8",
+        None,
+    ),  # 3
+    (
+        4,
+        TType.STRING,
+        "os_version",
+        "UTF8",
+        None,
+    ),  # 4
+    (
+        5,
+        TType.STRING,
+        "form_factor",
+        "UTF8",
+        None,
+    ),  # 5
+    (
+        6,
+        TType.STRING,
+        "attestation_verdict",
+        "UTF8",
+        None,
+    ),  # 6
 )
 all_structs.append(OriginService)
 OriginService.thrift_spec = (
@@ -865,6 +2146,27 @@ OriginService.thrift_spec = (
         "UTF8",
         None,
     ),  # 1
+    (
+        2,
+        TType.STRING,
+        "version",
+        "UTF8",
+        None,
+    ),  # 2
+    (
+        3,
+        TType.STRING,
+        "deploy_id",
+        "UTF8",
+        None,
+    ),  # 3
+    (
+        4,
+        TType.STRING,
+        "edge_pop",
+        "UTF8",
+        None,
+    ),  # 4
 )
 all_structs.append(Geolocation)
 Geolocation.thrift_spec = (
@@ -876,6 +2178,34 @@ Geolocation.thrift_spec = (
         "UTF8",
         None,
     ),  # 1
+    (
+        2,
+        TType.STRING,
+        "subdivision_code",
+        "UTF8",
+        None,
+    ),  # 2
+    (
+        3,
+        TType.STRING,
+        "city",
+        "UTF8",
+        None,
+    ),  # 3
+    (
+        4,
+        TType.I32,
+        "metro_code",
+        None,
+        None,
+    ),  # 4
+    (
+        5,
+        TType.STRING,
+        "provenance",
+        "UTF8",
+        None,
+    ),  # 5
 )
 all_structs.append(RequestId)
 RequestId.thrift_spec = (
@@ -887,6 +2217,13 @@ RequestId.thrift_spec = (
         "UTF8",
         None,
     ),  # 1
+    (
+        2,
+        TType.STRING,
+        "trace_id",
+        "UTF8",
+        None,
+    ),  # 2
 )
 all_structs.append(Locale)
 Locale.thrift_spec = (
@@ -898,6 +2235,162 @@ Locale.thrift_spec = (
         "UTF8",
         None,
     ),  # 1
+    (
+        2,
+        TType.STRING,
+        "timezone",
+        "UTF8",
+        None,
+    ),  # 2
+    (
+        3,
+        TType.LIST,
+        "accepted_locale_codes",
+        (TType.STRING, "UTF8", False),
+        None,
+    ),  # 3
+)
+all_structs.append(Client)
+Client.thrift_spec = (
+    None,  # 0
+    (
+        1,
+        TType.STRING,
+        "ip",
+        "UTF8",
+        None,
+    ),  # 1
+    (
+        2,
+        TType.STRING,
+        "user_agent",
+        "UTF8",
+        None,
+    ),  # 2
+    (
+        3,
+        TType.STRING,
+        "app_version",
+        "UTF8",
+        None,
+    ),  # 3
+    (
+        4,
+        TType.I64,
+        "build_number",
+        None,
+        None,
+    ),  # 4
+    (
+        5,
+        TType.STRING,
+        "platform",
+        "UTF8",
+        None,
+    ),  # 5
+    (
+        6,
+        TType.BOOL,
+        "internal_network",
+        None,
+        None,
+    ),  # 6
+    (
+        7,
+        TType.BOOL,
+        "limit_ad_tracking",
+        None,
+        None,
+    ),  # 7
+    (
+        8,
+        TType.BOOL,
+        "anonymizing_proxy",
+        None,
+        None,
+    ),  # 8
+    (
+        9,
+        TType.I64,
+        "asn",
+        None,
+        None,
+    ),  # 9
+)
+all_structs.append(Consent)
+Consent.thrift_spec = (
+    None,  # 0
+    (
+        1,
+        TType.BOOL,
+        "ads_personalization",
+        None,
+        None,
+    ),  # 1
+    (
+        2,
+        TType.BOOL,
+        "analytics",
+        None,
+        None,
+    ),  # 2
+    (
+        3,
+        TType.BOOL,
+        "third_party_sharing",
+        None,
+        None,
+    ),  # 3
+)
+all_structs.append(Account)
+Account.thrift_spec = (
+    None,  # 0
+    (
+        1,
+        TType.BOOL,
+        "teen_restricted",
+        None,
+        None,
+    ),  # 1
+    (
+        2,
+        TType.BOOL,
+        "email_verified",
+        None,
+        None,
+    ),  # 2
+    (
+        3,
+        TType.BOOL,
+        "premium",
+        None,
+        None,
+    ),  # 3
+)
+all_structs.append(Traffic)
+Traffic.thrift_spec = (
+    None,  # 0
+    (
+        1,
+        TType.BOOL,
+        "shadow",
+        None,
+        None,
+    ),  # 1
+    (
+        2,
+        TType.BOOL,
+        "synthetic",
+        None,
+        None,
+    ),  # 2
+    (
+        3,
+        TType.BOOL,
+        "canary",
+        None,
+        None,
+    ),  # 3
 )
 all_structs.append(Request)
 Request.thrift_spec = (
@@ -958,6 +2451,97 @@ Request.thrift_spec = (
         [Locale, None],
         None,
     ),  # 8
+    (
+        9,
+        TType.STRUCT,
+        "client",
+        [Client, None],
+        None,
+    ),  # 9
+    (
+        10,
+        TType.MAP,
+        "feature_flag_overrides",
+        (TType.STRING, "UTF8", TType.STRING, "UTF8", False),
+        None,
+    ),  # 10
+    (
+        11,
+        TType.STRUCT,
+        "consent",
+        [Consent, None],
+        None,
+    ),  # 11
+    (
+        12,
+        TType.STRING,
+        "compliance_region",
+        "UTF8",
+        None,
+    ),  # 12
+    (
+        13,
+        TType.STRUCT,
+        "account",
+        [Account, None],
+        None,
+    ),  # 13
+    (
+        14,
+        TType.MAP,
+        "baggage",
+        (TType.STRING, "UTF8", TType.STRING, "UTF8", False),
+        None,
+    ),  # 14
+    (
+        15,
+        TType.STRING,
+        "workload_identity",
+        "UTF8",
+        None,
+    ),  # 15
+    (
+        16,
+        TType.I64,
+        "deadline_ms",
+        None,
+        None,
+    ),  # 16
+    (
+        17,
+        TType.STRUCT,
+        "traffic",
+        [Traffic, None],
+        None,
+    ),  # 17
+    (
+        18,
+        TType.LIST,
+        "hops",
+        (TType.STRING, "UTF8", False),
+        None,
+    ),  # 18
+    (
+        19,
+        TType.STRING,
+        "tenant_id",
+        "UTF8",
+        None,
+    ),  # 19
+    (
+        20,
+        TType.STRING,
+        "referring_surface",
+        "UTF8",
+        None,
+    ),  # 20
+    (
+        21,
+        TType.STRING,
+        "service_authentication_token",
+        "UTF8",
+        None,
+    ),  # 21
 )
 fix_spec(all_structs)
 del all_structs